@@ -0,0 +1,360 @@
+package data
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditAction the kind of change recorded by an AuditEvent
+type AuditAction string
+
+const (
+	// AuditActionCreate the object was created
+	AuditActionCreate AuditAction = "create"
+	// AuditActionUpdate a field of the object was changed
+	AuditActionUpdate AuditAction = "update"
+	// AuditActionSetTags tags were added to a certificate
+	AuditActionSetTags AuditAction = "set_tags"
+	// AuditActionRemoveTags tags were removed from a certificate
+	AuditActionRemoveTags AuditAction = "remove_tags"
+	// AuditActionSetLabels labels were set on a certificate
+	AuditActionSetLabels AuditAction = "set_labels"
+	// AuditActionRemoveLabels labels were removed from a certificate
+	AuditActionRemoveLabels AuditAction = "remove_labels"
+	// AuditActionRename a tag was renamed
+	AuditActionRename AuditAction = "rename"
+	// AuditActionMerge tags were merged into a tag
+	AuditActionMerge AuditAction = "merge"
+	// AuditActionDelete the object was moved to the trash
+	AuditActionDelete AuditAction = "delete"
+	// AuditActionRestore the object was restored from the trash
+	AuditActionRestore AuditAction = "restore"
+	// AuditActionPurge the object was permanently deleted
+	AuditActionPurge AuditAction = "purge"
+)
+
+// auditActions all known actions
+var auditActions = []AuditAction{
+	AuditActionCreate,
+	AuditActionUpdate,
+	AuditActionSetTags,
+	AuditActionRemoveTags,
+	AuditActionSetLabels,
+	AuditActionRemoveLabels,
+	AuditActionRename,
+	AuditActionMerge,
+	AuditActionDelete,
+	AuditActionRestore,
+	AuditActionPurge,
+}
+
+// AuditObjectType the type of the object changed
+type AuditObjectType string
+
+const (
+	// AuditObjectCertificate a Certificate
+	AuditObjectCertificate AuditObjectType = "certificate"
+	// AuditObjectTag a Tag
+	AuditObjectTag AuditObjectType = "tag"
+	// AuditObjectAutoTagRule an AutoTagRule
+	AuditObjectAutoTagRule AuditObjectType = "auto_tag_rule"
+)
+
+// AuditContext who is doing the changes, and in which request
+type AuditContext struct {
+	// the user or service doing the changes
+	Actor string
+	// the id of the API request doing the changes (empty for the server jobs)
+	RequestID string
+}
+
+// SystemAuditContext the AuditContext of the changes done by the server itself (e.g. trash purge)
+var SystemAuditContext = AuditContext{Actor: "system"}
+
+// AuditEvent defines a change to a certificate or a tag
+// swagger:model
+type AuditEvent struct {
+	// the id for the event
+	//
+	// required: false
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primary_key;"`
+
+	// the time of the change
+	//
+	// required: false
+	CreatedAt time.Time `json:"timestamp" gorm:"index"`
+
+	// the user or service that did the change
+	//
+	// required: false
+	Actor string `json:"actor" gorm:"index"`
+
+	// the id of the API request that did the change
+	//
+	// required: false
+	RequestID string `json:"request_id,omitempty" gorm:"index"`
+
+	// the change: create, update, set_tags, remove_tags, delete, restore or purge
+	//
+	// required: false
+	Action AuditAction `json:"action" gorm:"index"`
+
+	// the type of the changed object: certificate or tag
+	//
+	// required: false
+	ObjectType AuditObjectType `json:"object_type" gorm:"index:idx_audit_object"`
+
+	// the id of the changed object
+	//
+	// required: false
+	ObjectID uuid.UUID `json:"object_id" gorm:"type:uuid;index:idx_audit_object"`
+
+	// the state of the object before the change
+	//
+	// required: false
+	Before AuditState `json:"before,omitempty" gorm:"type:text"`
+
+	// the state of the object after the change
+	//
+	// required: false
+	After AuditState `json:"after,omitempty" gorm:"type:text"`
+
+	// the position of the event in the audit chain (starting at 1)
+	//
+	// required: false
+	Sequence uint64 `json:"sequence" gorm:"index"`
+
+	// the hash of the previous event in the audit chain
+	//
+	// required: false
+	PrevHash string `json:"prev_hash"`
+
+	// the sha256 of the event and the previous hash
+	//
+	// required: false
+	Hash string `json:"hash"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (event *AuditEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	if event.ID == uuid.Nil {
+		uuid := uuid.New()
+		event.ID = uuid
+	}
+
+	return
+}
+
+// AuditState the JSON encoded state of an object (stored as text)
+type AuditState []byte
+
+// Value implements driver.Valuer
+func (state AuditState) Value() (driver.Value, error) {
+	if len(state) == 0 {
+		return nil, nil
+	}
+
+	return string(state), nil
+}
+
+// Scan implements sql.Scanner
+func (state *AuditState) Scan(value interface{}) error {
+
+	switch v := value.(type) {
+	case nil:
+		*state = nil
+	case string:
+		*state = AuditState(v)
+	case []byte:
+		*state = append(AuditState{}, v...)
+	default:
+		return fmt.Errorf("cannot scan %T into AuditState", value)
+	}
+
+	return nil
+}
+
+// MarshalJSON return the state as is
+func (state AuditState) MarshalJSON() ([]byte, error) {
+	if len(state) == 0 {
+		return []byte("null"), nil
+	}
+
+	return state, nil
+}
+
+// UnmarshalJSON keep the state as is
+func (state *AuditState) UnmarshalJSON(data []byte) error {
+	*state = append(AuditState{}, data...)
+	return nil
+}
+
+// AuditCertState the state of a certificate recorded in the audit log
+type AuditCertState struct {
+	SHA256       string            `json:"sha256"`
+	Subject      string            `json:"subject"`
+	SerialNumber string            `json:"serial_number"`
+	Tags         []string          `json:"tags"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+// NewAuditCertState return the state of cert recorded in the audit log
+func NewAuditCertState(cert *Certificate) *AuditCertState {
+
+	tags := []string{}
+	for _, t := range cert.Tags {
+		tags = append(tags, t.Name)
+	}
+
+	var labels map[string]string
+	for _, l := range cert.Labels {
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[l.Key] = l.Value
+	}
+
+	return &AuditCertState{
+		SHA256:       cert.SHA256,
+		Subject:      cert.Subject,
+		SerialNumber: cert.SerialNumber,
+		Tags:         tags,
+		Labels:       labels,
+	}
+}
+
+// AuditTagState the state of a tag recorded in the audit log
+type AuditTagState struct {
+	Name                   string   `json:"name"`
+	Description            string   `json:"description"`
+	NotificationThresholds string   `json:"notification_thresholds"`
+	Aliases                []string `json:"aliases,omitempty"`
+}
+
+// NewAuditTagState return the state of tag recorded in the audit log
+func NewAuditTagState(tag *Tag) *AuditTagState {
+
+	var aliases []string
+	for _, a := range tag.Aliases {
+		aliases = append(aliases, a.Name)
+	}
+
+	return &AuditTagState{
+		Name:                   tag.Name,
+		Description:            tag.Description,
+		NotificationThresholds: tag.NotificationThresholds,
+		Aliases:                aliases,
+	}
+}
+
+// AuditAutoTagRuleState the state of an auto-tag rule recorded in the audit log
+type AuditAutoTagRuleState struct {
+	Name               string `json:"name"`
+	Tag                string `json:"tag"`
+	IssuerPattern      string `json:"issuer_pattern,omitempty"`
+	SubjectPattern     string `json:"subject_pattern,omitempty"`
+	SANPattern         string `json:"san_pattern,omitempty"`
+	PublicKeyAlgorithm string `json:"public_key_algorithm,omitempty"`
+	IsCA               *bool  `json:"is_ca,omitempty"`
+	ExtKeyUsage        string `json:"ext_key_usage,omitempty"`
+}
+
+// NewAuditAutoTagRuleState return the state of rule recorded in the audit log
+func NewAuditAutoTagRuleState(rule *AutoTagRule) *AuditAutoTagRuleState {
+	return &AuditAutoTagRuleState{
+		Name:               rule.Name,
+		Tag:                rule.TagName,
+		IssuerPattern:      rule.IssuerPattern,
+		SubjectPattern:     rule.SubjectPattern,
+		SANPattern:         rule.SANPattern,
+		PublicKeyAlgorithm: rule.PublicKeyAlgorithm,
+		IsCA:               rule.IsCA,
+		ExtKeyUsage:        rule.ExtKeyUsage,
+	}
+}
+
+// AuditFilter defines the conditions to select audit events
+// empty fields are ignored
+type AuditFilter struct {
+	// the user or service that did the change
+	Actor string
+	// the change
+	Action AuditAction
+	// the type of the changed object
+	ObjectType AuditObjectType
+	// the id of the changed object
+	ObjectID *uuid.UUID
+	// the id of the API request that did the change
+	RequestID string
+	// the changes done at or after Since
+	Since *time.Time
+	// the changes done before Until
+	Until *time.Time
+}
+
+// Validate return a DBObjectValidationError if the filter is invalid
+func (filter *AuditFilter) Validate() error {
+
+	if filter.Action != "" {
+		known := false
+		for _, a := range auditActions {
+			if a == filter.Action {
+				known = true
+			}
+		}
+
+		if !known {
+			return &DBObjectValidationError{Msg: fmt.Sprintf("unknown audit action '%s'", filter.Action)}
+		}
+	}
+
+	if filter.ObjectType != "" && filter.ObjectType != AuditObjectCertificate && filter.ObjectType != AuditObjectTag &&
+		filter.ObjectType != AuditObjectAutoTagRule {
+		return &DBObjectValidationError{Msg: fmt.Sprintf("unknown audit object type '%s'", filter.ObjectType)}
+	}
+
+	if filter.Since != nil && filter.Until != nil && filter.Until.Before(*filter.Since) {
+		return &DBObjectValidationError{Msg: "until must be after since"}
+	}
+
+	return nil
+}
+
+// apply add the conditions of the filter to the query
+func (filter *AuditFilter) apply(query *gorm.DB) *gorm.DB {
+
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	if filter.ObjectType != "" {
+		query = query.Where("object_type = ?", filter.ObjectType)
+	}
+
+	if filter.ObjectID != nil {
+		query = query.Where("object_id = ?", *filter.ObjectID)
+	}
+
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+
+	// times are compared as strings by sqlite
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", filter.Since.UTC())
+	}
+
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", filter.Until.UTC())
+	}
+
+	return query
+}
@@ -0,0 +1,309 @@
+package data
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//
+// Audit functions
+// Create: recordAudit, auditTransaction, ChainAuditEvents
+// Read:   ListAuditEvents, ListAuditEventsByObject, VerifyAuditChain, ExportAuditChain
+//
+
+// auditChainMu serializes the events appended to the audit chain
+var auditChainMu sync.Mutex
+
+// WithAuditContext return a CertBackend recording its changes in the audit log with ac
+func (certBackend *CertBackend) WithAuditContext(ac AuditContext) *CertBackend {
+
+	backend := *certBackend
+	backend.audit = ac
+
+	return &backend
+}
+
+// withTx return a CertBackend reading and writing in tx, a transaction started by auditTransaction
+func (certBackend *CertBackend) withTx(tx *gorm.DB) *CertBackend {
+
+	backend := *certBackend
+	backend.db = tx
+	backend.inTx = true
+
+	return &backend
+}
+
+// ListAuditEvents return the audit events matching the filter (the last change first)
+func (certBackend *CertBackend) ListAuditEvents(filter AuditFilter) ([]*AuditEvent, error) {
+	certBackend.logger.Debug("ListAuditEvents: Listing events...", "filter", filter)
+
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// the rowid orders the events recorded within the same millisecond
+	events := []*AuditEvent{}
+	result := filter.apply(certBackend.db).Order("created_at DESC, rowid DESC").Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return events, nil
+}
+
+// ListAuditEventsByObject return the history of the object (uuid) of objectType (the first change first)
+// the history of deleted and purged objects is kept
+func (certBackend *CertBackend) ListAuditEventsByObject(objectType AuditObjectType, uuid uuid.UUID) ([]*AuditEvent, error) {
+	certBackend.logger.Debug("ListAuditEventsByObject: Listing events...", "objectType", objectType, "uuid", uuid)
+
+	filter := AuditFilter{ObjectType: objectType, ObjectID: &uuid}
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*AuditEvent{}
+	result := filter.apply(certBackend.db).Order("created_at, rowid").Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// an object without history never existed
+	if len(events) == 0 {
+		return nil, &DBObjectNotFound{ID: uuid.String()}
+	}
+
+	return events, nil
+}
+
+// auditTransaction run fn in a transaction holding the audit chain, so the changes of fn
+// and their events (recorded by withTx(tx)) are committed or rolled back together
+func (certBackend *CertBackend) auditTransaction(fn func(tx *gorm.DB) error) error {
+
+	// already in the transaction, the changes of fn are rolled back to a savepoint on error
+	if certBackend.inTx {
+		return certBackend.db.Transaction(fn)
+	}
+
+	auditChainMu.Lock()
+	defer auditChainMu.Unlock()
+
+	return certBackend.db.Transaction(fn)
+}
+
+// recordAudit record the change of the object (uuid) from before to after (nil if the object
+// does not exist before or after the change) by the actor of the audit context
+// the change must be done in the same transaction (see auditTransaction), the error
+// is returned to roll back the change
+func (certBackend *CertBackend) recordAudit(action AuditAction, objectType AuditObjectType, uuid uuid.UUID, before interface{}, after interface{}) error {
+
+	event, err := certBackend.newAuditEvent(action, objectType, uuid, before, after)
+	if err != nil {
+		return err
+	}
+
+	return certBackend.auditTransaction(func(tx *gorm.DB) error {
+		return appendAuditEventTx(tx, event)
+	})
+}
+
+// newAuditEvent return the event of the change by the actor of the audit context
+func (certBackend *CertBackend) newAuditEvent(action AuditAction, objectType AuditObjectType, uuid uuid.UUID, before interface{}, after interface{}) (*AuditEvent, error) {
+
+	event := &AuditEvent{
+		Actor:      certBackend.audit.Actor,
+		RequestID:  certBackend.audit.RequestID,
+		Action:     action,
+		ObjectType: objectType,
+		ObjectID:   uuid,
+	}
+
+	var err error
+	if before != nil {
+		event.Before, err = json.Marshal(before)
+	}
+	if err == nil && after != nil {
+		event.After, err = json.Marshal(after)
+	}
+
+	return event, err
+}
+
+// lastChainedAuditEvent return the last event of the audit chain (nil if the chain is empty)
+func lastChainedAuditEvent(tx *gorm.DB) (*AuditEvent, error) {
+
+	var last AuditEvent
+	result := tx.Where("hash <> ''").Order("sequence DESC").Limit(1).Find(&last)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &last, nil
+}
+
+// chainAuditEvent set the sequence, the previous hash and the hash of event to follow last
+func chainAuditEvent(event *AuditEvent, last *AuditEvent) {
+
+	event.Sequence = 1
+	event.PrevHash = AuditChainGenesisHash
+	if last != nil {
+		event.Sequence = last.Sequence + 1
+		event.PrevHash = last.Hash
+	}
+
+	event.Hash = event.ComputeHash()
+}
+
+// appendAuditEventTx create event at the end of the audit chain in tx (auditChainMu must be held)
+func appendAuditEventTx(tx *gorm.DB, event *AuditEvent) error {
+
+	// the id and the timestamp are part of the hash, they must be set before
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	last, err := lastChainedAuditEvent(tx)
+	if err != nil {
+		return err
+	}
+
+	chainAuditEvent(event, last)
+
+	return tx.Create(event).Error
+}
+
+// ChainAuditEvents add the events recorded before the audit chain to the chain
+func (certBackend *CertBackend) ChainAuditEvents() error {
+	certBackend.logger.Debug("ChainAuditEvents: Chaining audit events...")
+
+	auditChainMu.Lock()
+	defer auditChainMu.Unlock()
+
+	return certBackend.db.Transaction(func(tx *gorm.DB) error {
+
+		var events []*AuditEvent
+		result := tx.Where("hash = '' OR hash IS NULL").Order("created_at, rowid").Find(&events)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		last, err := lastChainedAuditEvent(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			event.CreatedAt = event.CreatedAt.UTC()
+			chainAuditEvent(event, last)
+
+			result = tx.Model(&AuditEvent{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+				"created_at": event.CreatedAt,
+				"sequence":   event.Sequence,
+				"prev_hash":  event.PrevHash,
+				"hash":       event.Hash,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			last = event
+		}
+
+		if len(events) > 0 {
+			certBackend.logger.Info("ChainAuditEvents: chained audit events", "count", len(events))
+		}
+
+		return nil
+	})
+}
+
+// VerifyAuditChain verify the hash chain of the stored audit events
+// and report the gaps and the modified events
+func (certBackend *CertBackend) VerifyAuditChain() (*AuditChainVerification, error) {
+	certBackend.logger.Debug("VerifyAuditChain: Verifying audit chain...")
+
+	verifier := newAuditChainVerifier()
+
+	var batch []*AuditEvent
+	result := certBackend.db.Order("sequence, rowid").FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, event := range batch {
+			verifier.add(event)
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return verifier.result, nil
+}
+
+// ExportAuditChain write the audit events as JSON Lines followed by
+// an AuditChainSignature line signed with key
+func (certBackend *CertBackend) ExportAuditChain(w io.Writer, key ed25519.PrivateKey) (*AuditChainSignature, error) {
+	certBackend.logger.Debug("ExportAuditChain: Exporting audit chain...")
+
+	digest := sha256.New()
+	out := io.MultiWriter(w, digest)
+
+	sig := &AuditChainSignature{
+		Type:       AuditChainSignatureType,
+		Algorithm:  "ed25519",
+		PublicKey:  base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		ExportedAt: time.Now().UTC(),
+		LastHash:   AuditChainGenesisHash,
+	}
+
+	var batch []*AuditEvent
+	result := certBackend.db.Order("sequence, rowid").FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, event := range batch {
+			line, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+
+			_, err = out.Write(append(line, '\n'))
+			if err != nil {
+				return err
+			}
+
+			sig.Count++
+			sig.LastSequence = event.Sequence
+			sig.LastHash = event.Hash
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	sig.Digest = hex.EncodeToString(digest.Sum(nil))
+	sig.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, sig.signedPayload()))
+
+	line, err := json.Marshal(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(append(line, '\n'))
+	if err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
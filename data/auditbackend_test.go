@@ -0,0 +1,288 @@
+package data
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAuditLog(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+	alice := certBakcend.WithAuditContext(AuditContext{Actor: "alice", RequestID: "req-1"})
+
+	tag, err := alice.CreateTagWithDescription("web", "web servers")
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	leaf, err := alice.CreateCertificateWithTags(testLeafPEM, []string{"web"})
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	bob := certBakcend.WithAuditContext(AuditContext{Actor: "bob", RequestID: "req-2"})
+
+	_, err = bob.DeleteCertificateTagsByID(leaf.ID, []string{"web"})
+	if err == nil {
+		_, err = bob.SetTagDescriptionByID(tag.ID, "public web servers")
+	}
+	if err == nil {
+		err = bob.DeleteCertByID(leaf.ID)
+	}
+	if err == nil {
+		_, err = certBakcend.PurgeTrash(time.Now().Add(time.Minute))
+	}
+	if err != nil {
+		t.Logf("Error changing objects %s", err.Error())
+		t.FailNow()
+	}
+
+	// the history of a purged object is kept
+	history, err := certBakcend.ListAuditEventsByObject(AuditObjectCertificate, leaf.ID)
+	if err != nil {
+		t.Logf("Error listing history %s", err.Error())
+		t.FailNow()
+	}
+
+	expect := []struct {
+		action AuditAction
+		actor  string
+	}{
+		{AuditActionCreate, "alice"},
+		{AuditActionSetTags, "alice"},
+		{AuditActionRemoveTags, "bob"},
+		{AuditActionDelete, "bob"},
+		{AuditActionPurge, "system"},
+	}
+
+	if len(history) != len(expect) {
+		t.Logf("Expecting %d events, but got %d", len(expect), len(history))
+		t.FailNow()
+	}
+
+	for i, e := range expect {
+		if history[i].Action != e.action || history[i].Actor != e.actor {
+			t.Logf("Expecting %s by %s at %d, but got %s by %s", e.action, e.actor, i, history[i].Action, history[i].Actor)
+			t.FailNow()
+		}
+	}
+
+	// before and after of the tag change
+	var before, after AuditCertState
+	if json.Unmarshal(history[1].Before, &before) != nil || json.Unmarshal(history[1].After, &after) != nil {
+		t.Logf("Error parsing states %s %s", history[1].Before, history[1].After)
+		t.FailNow()
+	}
+
+	if len(before.Tags) != 0 || len(after.Tags) != 1 || after.Tags[0] != "web" || after.SHA256 != leaf.SHA256 {
+		t.Logf("Unexpected states %+v %+v", before, after)
+		t.FailNow()
+	}
+
+	if history[0].Before != nil || history[4].After != nil {
+		t.Logf("Expecting no state before create and after purge")
+		t.FailNow()
+	}
+
+	tests := []struct {
+		filter AuditFilter
+		count  int
+	}{
+		{AuditFilter{}, 7},
+		{AuditFilter{Actor: "bob"}, 3},
+		{AuditFilter{RequestID: "req-1"}, 3},
+		{AuditFilter{ObjectType: AuditObjectTag}, 2},
+		{AuditFilter{ObjectType: AuditObjectTag, Action: AuditActionUpdate}, 1},
+		{AuditFilter{ObjectID: &tag.ID}, 2},
+	}
+
+	for _, test := range tests {
+		events, err := certBakcend.ListAuditEvents(test.filter)
+		if err != nil || len(events) != test.count {
+			t.Logf("Expecting %d events for %+v, but got %d (%v)", test.count, test.filter, len(events), err)
+			t.FailNow()
+		}
+	}
+
+	// the last change first
+	events, _ := certBakcend.ListAuditEvents(AuditFilter{})
+	if events[0].Action != AuditActionPurge {
+		t.Logf("Expecting purge first, but got %s", events[0].Action)
+		t.FailNow()
+	}
+
+	since := time.Now().Add(time.Hour)
+	events, err = certBakcend.ListAuditEvents(AuditFilter{Since: &since})
+	if err != nil || len(events) != 0 {
+		t.Logf("Expecting no future events got %d (%v)", len(events), err)
+		t.FailNow()
+	}
+
+	for _, invalid := range []AuditFilter{{Action: "archive"}, {ObjectType: "bundle"}} {
+		_, err = certBakcend.ListAuditEvents(invalid)
+		if _, ok := err.(*DBObjectValidationError); !ok {
+			t.Logf("Expecting validation error for %+v got %v", invalid, err)
+			t.FailNow()
+		}
+	}
+
+	_, err = certBakcend.ListAuditEventsByObject(AuditObjectCertificate, uuid.New())
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting not found got %v", err)
+		t.FailNow()
+	}
+}
+
+func TestAuditFailures(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	cert, err := certBakcend.CreateCertificate(testLeafPEM)
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	tag, err := certBakcend.CreateTag("api")
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	// the audit log cannot be written anymore
+	err = certBakcend.db.Migrator().DropTable(&AuditEvent{})
+	if err != nil {
+		t.Logf("Error dropping audit table %s", err.Error())
+		t.FailNow()
+	}
+
+	// a change in a transaction is rolled back with its event
+	_, err = certBakcend.SetCertLabelsByID(cert.ID, map[string]string{"env": "prod"})
+	if err == nil {
+		t.Logf("Expecting error setting labels without audit log")
+		t.FailNow()
+	}
+
+	cert, err = certBakcend.GetCertByID(cert.ID)
+	if err != nil || len(cert.Labels) != 0 {
+		t.Logf("Expecting labels rolled back got %v (%v)", cert, err)
+		t.FailNow()
+	}
+
+	// a created tag is rolled back with its event
+	_, err = certBakcend.CreateTag("web")
+	if err == nil {
+		t.Logf("Expecting error creating tag without audit log")
+		t.FailNow()
+	}
+
+	_, err = certBakcend.getTagByNameUnscoped("web")
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting tag rolled back got %v", err)
+		t.FailNow()
+	}
+
+	// the parent created by a rename is rolled back with the rename
+	_, err = certBakcend.RenameTagByID(tag.ID, "team/api")
+	if err == nil {
+		t.Logf("Expecting error renaming tag without audit log")
+		t.FailNow()
+	}
+
+	_, err = certBakcend.getTagByNameUnscoped("team")
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting parent tag rolled back got %v", err)
+		t.FailNow()
+	}
+}
+
+func TestAuditRollback(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	root, err := certBakcend.CreateCertificate(testRootPEM)
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	leaf, err := certBakcend.CreateCertificate(testLeafPEM)
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	err = certBakcend.DeleteCertByID(leaf.ID)
+	if err != nil {
+		t.Logf("Error deleting cert %s", err.Error())
+		t.FailNow()
+	}
+
+	// the events cannot be appended anymore
+	result := certBakcend.db.Exec("CREATE TRIGGER audit_fail BEFORE INSERT ON audit_events BEGIN SELECT RAISE(ABORT, 'audit log unavailable'); END")
+	if result.Error != nil {
+		t.Logf("Error creating trigger %s", result.Error.Error())
+		t.FailNow()
+	}
+
+	// every change is rolled back with its event
+	_, err = certBakcend.CreateCertificate(testIntermediatePEM)
+	if err == nil {
+		t.Logf("Expecting error creating cert without audit log")
+		t.FailNow()
+	}
+
+	certs, err := certBakcend.ListCerts()
+	if err != nil || len(certs) != 1 {
+		t.Logf("Expecting only the root cert got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+
+	err = certBakcend.DeleteCertByID(root.ID)
+	if err == nil {
+		t.Logf("Expecting error deleting cert without audit log")
+		t.FailNow()
+	}
+
+	_, err = certBakcend.RestoreCertByID(leaf.ID)
+	if err == nil {
+		t.Logf("Expecting error restoring cert without audit log")
+		t.FailNow()
+	}
+
+	_, err = certBakcend.PurgeTrash(time.Now().Add(time.Hour))
+	if err == nil {
+		t.Logf("Expecting error purging trash without audit log")
+		t.FailNow()
+	}
+
+	certs, err = certBakcend.ListTrashedCerts()
+	if err != nil || len(certs) != 1 || certs[0].ID != leaf.ID {
+		t.Logf("Expecting the leaf in the trash got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.GetCertByID(root.ID)
+	if err != nil {
+		t.Logf("Expecting root cert not deleted got %v", err)
+		t.FailNow()
+	}
+
+	// the chain has the event of every done change
+	result = certBakcend.db.Exec("DROP TRIGGER audit_fail")
+	if result.Error != nil {
+		t.Logf("Error dropping trigger %s", result.Error.Error())
+		t.FailNow()
+	}
+
+	verification, err := certBakcend.VerifyAuditChain()
+	if err != nil || !verification.Valid || verification.Count != 3 {
+		t.Logf("Expecting a valid chain of 3 events got %v (%v)", verification, err)
+		t.FailNow()
+	}
+}
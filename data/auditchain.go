@@ -0,0 +1,320 @@
+package data
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// AuditChainGenesisHash the previous hash of the first event of the audit chain
+var AuditChainGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// AuditChainSignatureType the type of the last line of an audit chain export
+const AuditChainSignatureType = "signature"
+
+// auditEventHashInput the fields of an event covered by its hash
+type auditEventHashInput struct {
+	ID         string          `json:"id"`
+	Sequence   uint64          `json:"sequence"`
+	Timestamp  string          `json:"timestamp"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id"`
+	Action     AuditAction     `json:"action"`
+	ObjectType AuditObjectType `json:"object_type"`
+	ObjectID   string          `json:"object_id"`
+	Before     AuditState      `json:"before"`
+	After      AuditState      `json:"after"`
+	PrevHash   string          `json:"prev_hash"`
+}
+
+// ComputeHash return the hex encoded sha256 of the fields of the event and of the previous hash
+func (event *AuditEvent) ComputeHash() string {
+
+	// the fields are fixed, marshalling cannot fail
+	input, _ := json.Marshal(&auditEventHashInput{
+		ID:         event.ID.String(),
+		Sequence:   event.Sequence,
+		Timestamp:  event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Actor:      event.Actor,
+		RequestID:  event.RequestID,
+		Action:     event.Action,
+		ObjectType: event.ObjectType,
+		ObjectID:   event.ObjectID.String(),
+		Before:     event.Before,
+		After:      event.After,
+		PrevHash:   event.PrevHash,
+	})
+
+	sum := sha256.Sum256(input)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditChainError defines a problem found in the audit chain
+// swagger:model
+type AuditChainError struct {
+	// the sequence of the event (0 if the problem is not on an event)
+	//
+	// required: false
+	Sequence uint64 `json:"sequence"`
+
+	// the description of the problem
+	//
+	// required: false
+	Message string `json:"message"`
+}
+
+// AuditChainVerification the result of the verification of an audit chain
+// an attacker could still remove the last events: compare the last sequence
+// and hash with the ones of an archived export
+// swagger:model
+type AuditChainVerification struct {
+	// true if no problem was found
+	//
+	// required: false
+	Valid bool `json:"valid"`
+
+	// the number of verified events
+	//
+	// required: false
+	Count int `json:"count"`
+
+	// the sequence of the last event
+	//
+	// required: false
+	LastSequence uint64 `json:"last_sequence"`
+
+	// the hash of the last event
+	//
+	// required: false
+	LastHash string `json:"last_hash"`
+
+	// the problems found
+	//
+	// required: false
+	Errors []*AuditChainError `json:"errors"`
+}
+
+// AuditChainSignature the last line of an audit chain export
+// swagger:model
+type AuditChainSignature struct {
+	// always "signature"
+	//
+	// required: false
+	Type string `json:"type"`
+
+	// the signature algorithm (ed25519)
+	//
+	// required: false
+	Algorithm string `json:"algorithm"`
+
+	// the base64 encoded public key of the signing key
+	//
+	// required: false
+	PublicKey string `json:"public_key"`
+
+	// the time of the export
+	//
+	// required: false
+	ExportedAt time.Time `json:"exported_at"`
+
+	// the number of exported events
+	//
+	// required: false
+	Count int `json:"count"`
+
+	// the sequence of the last exported event
+	//
+	// required: false
+	LastSequence uint64 `json:"last_sequence"`
+
+	// the hash of the last exported event
+	//
+	// required: false
+	LastHash string `json:"last_hash"`
+
+	// the hex encoded sha256 of the lines of the events
+	//
+	// required: false
+	Digest string `json:"digest"`
+
+	// the base64 encoded signature of this line without the signature
+	//
+	// required: false
+	Signature string `json:"signature"`
+}
+
+// signedPayload return the content signed by the signature
+func (sig AuditChainSignature) signedPayload() []byte {
+	sig.Signature = ""
+
+	// the fields are fixed, marshalling cannot fail
+	payload, _ := json.Marshal(&sig)
+	return payload
+}
+
+// auditChainVerifier verify the events of an audit chain one by one
+type auditChainVerifier struct {
+	result *AuditChainVerification
+}
+
+// newAuditChainVerifier create a verifier for a chain starting at the first event
+func newAuditChainVerifier() *auditChainVerifier {
+	return &auditChainVerifier{
+		result: &AuditChainVerification{
+			Valid:    true,
+			LastHash: AuditChainGenesisHash,
+			Errors:   []*AuditChainError{},
+		},
+	}
+}
+
+// fail record a problem
+func (v *auditChainVerifier) fail(sequence uint64, format string, a ...interface{}) {
+	v.result.Valid = false
+	v.result.Errors = append(v.result.Errors, &AuditChainError{Sequence: sequence, Message: fmt.Sprintf(format, a...)})
+}
+
+// add verify that event follows the previous one
+func (v *auditChainVerifier) add(event *AuditEvent) {
+
+	v.result.Count++
+
+	if event.Hash == "" {
+		v.fail(event.Sequence, "event %s is not chained", event.ID)
+	}
+
+	expected := v.result.LastSequence + 1
+	switch {
+	case event.Sequence > expected:
+		v.fail(event.Sequence, "missing events %d to %d", expected, event.Sequence-1)
+	case event.Sequence < expected:
+		v.fail(event.Sequence, "event out of order after event %d", v.result.LastSequence)
+	}
+
+	if event.PrevHash != v.result.LastHash {
+		v.fail(event.Sequence, "previous hash does not match the hash of event %d", v.result.LastSequence)
+	}
+
+	if event.Hash != event.ComputeHash() {
+		v.fail(event.Sequence, "hash does not match the content of the event")
+	}
+
+	v.result.LastSequence = event.Sequence
+	v.result.LastHash = event.Hash
+}
+
+// VerifyAuditExport verify an export of ExportAuditChain: the hash chain of the events,
+// and the digest and the signature of the last line with the public key
+func VerifyAuditExport(r io.Reader, publicKey ed25519.PublicKey) (*AuditChainVerification, error) {
+
+	verifier := newAuditChainVerifier()
+	digest := sha256.New()
+	var sig *AuditChainSignature
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		if sig != nil {
+			verifier.fail(0, "unexpected line after the signature")
+			break
+		}
+
+		var probe struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(line, &probe) == nil && probe.Type == AuditChainSignatureType {
+			sig = &AuditChainSignature{}
+			if err := json.Unmarshal(line, sig); err != nil {
+				verifier.fail(0, "invalid signature line: %s", err)
+			}
+			continue
+		}
+
+		digest.Write(line)
+		digest.Write([]byte{'\n'})
+
+		event := &AuditEvent{}
+		if err := json.Unmarshal(line, event); err != nil {
+			verifier.fail(verifier.result.LastSequence+1, "invalid event line: %s", err)
+			continue
+		}
+		verifier.add(event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if sig == nil {
+		verifier.fail(0, "missing signature line")
+		return verifier.result, nil
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil || !ed25519.Verify(publicKey, sig.signedPayload(), signature) {
+		verifier.fail(0, "invalid signature")
+	}
+
+	if sig.Digest != hex.EncodeToString(digest.Sum(nil)) {
+		verifier.fail(0, "the events do not match the signed digest")
+	}
+
+	if sig.Count != verifier.result.Count || sig.LastSequence != verifier.result.LastSequence || sig.LastHash != verifier.result.LastHash {
+		verifier.fail(0, "the events do not match the signed count and last event")
+	}
+
+	return verifier.result, nil
+}
+
+// ParseAuditSigningKey parse a PEM encoded PKCS#8 Ed25519 private key
+func ParseAuditSigningKey(data []byte) (ed25519.PrivateKey, error) {
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expecting an Ed25519 key, got %T", key)
+	}
+
+	return edKey, nil
+}
+
+// ParseAuditVerificationKey parse a PEM encoded PKIX Ed25519 public key
+func ParseAuditVerificationKey(data []byte) (ed25519.PublicKey, error) {
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("expecting an Ed25519 key, got %T", key)
+	}
+
+	return edKey, nil
+}
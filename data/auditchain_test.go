@@ -0,0 +1,163 @@
+package data
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+func TestAuditChain(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+	alice := certBakcend.WithAuditContext(AuditContext{Actor: "alice"})
+
+	tag, err := alice.CreateTagWithDescription("web", "web servers")
+	if err == nil {
+		_, err = alice.CreateCertificateWithTags(testLeafPEM, []string{"web"})
+	}
+	if err == nil {
+		_, err = alice.SetTagDescriptionByID(tag.ID, "public web servers")
+	}
+	if err != nil {
+		t.Logf("Error changing objects %s", err.Error())
+		t.FailNow()
+	}
+
+	verification, err := certBakcend.VerifyAuditChain()
+	if err != nil {
+		t.Logf("Error verifying chain %s", err.Error())
+		t.FailNow()
+	}
+
+	// create tag, create cert, set tags, update tag
+	if !verification.Valid || verification.Count != 4 || verification.LastSequence != 4 {
+		t.Logf("Expecting valid chain of 4 events got %+v %+v", verification, verification.Errors)
+		t.FailNow()
+	}
+
+	// export and verify the export offline
+	pub, key, _ := ed25519.GenerateKey(rand.Reader)
+
+	var export bytes.Buffer
+	sig, err := certBakcend.ExportAuditChain(&export, key)
+	if err != nil {
+		t.Logf("Error exporting chain %s", err.Error())
+		t.FailNow()
+	}
+
+	if sig.Count != 4 || sig.LastHash != verification.LastHash {
+		t.Logf("Unexpected signature %+v", sig)
+		t.FailNow()
+	}
+
+	exportVerification, err := VerifyAuditExport(bytes.NewReader(export.Bytes()), pub)
+	if err != nil || !exportVerification.Valid || exportVerification.Count != 4 {
+		t.Logf("Expecting valid export got %+v (%v)", exportVerification, err)
+		t.FailNow()
+	}
+
+	// a modified line of the export
+	tampered := strings.Replace(export.String(), `"actor":"alice"`, `"actor":"mallory"`, 1)
+	exportVerification, _ = VerifyAuditExport(strings.NewReader(tampered), pub)
+	if exportVerification.Valid {
+		t.Logf("Expecting tampered export to be invalid")
+		t.FailNow()
+	}
+
+	// a removed line of the export
+	lines := strings.SplitAfter(export.String(), "\n")
+	truncated := strings.Join(append(lines[:1:1], lines[2:]...), "")
+	exportVerification, _ = VerifyAuditExport(strings.NewReader(truncated), pub)
+	if exportVerification.Valid {
+		t.Logf("Expecting export with missing event to be invalid")
+		t.FailNow()
+	}
+
+	// another key
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	exportVerification, _ = VerifyAuditExport(bytes.NewReader(export.Bytes()), otherPub)
+	if exportVerification.Valid {
+		t.Logf("Expecting export verified with another key to be invalid")
+		t.FailNow()
+	}
+
+	// a modified event in the db
+	result := certBakcend.db.Exec("UPDATE audit_events SET actor = 'mallory' WHERE sequence = 2")
+	if result.Error != nil {
+		t.Logf("Error modifying event %s", result.Error.Error())
+		t.FailNow()
+	}
+
+	verification, _ = certBakcend.VerifyAuditChain()
+	if verification.Valid || len(verification.Errors) != 1 || verification.Errors[0].Sequence != 2 {
+		t.Logf("Expecting modified event 2 got %+v", verification.Errors)
+		t.FailNow()
+	}
+
+	// a removed event in the db
+	result = certBakcend.db.Exec("DELETE FROM audit_events WHERE sequence = 2")
+	if result.Error != nil {
+		t.Logf("Error removing event %s", result.Error.Error())
+		t.FailNow()
+	}
+
+	verification, _ = certBakcend.VerifyAuditChain()
+	if verification.Valid || verification.Count != 3 || verification.Errors[0].Sequence != 3 {
+		t.Logf("Expecting missing event 2 got %+v", verification.Errors)
+		t.FailNow()
+	}
+
+	// new events keep on chaining after the last event
+	_, err = alice.SetTagDescriptionByID(tag.ID, "web servers")
+	if err != nil {
+		t.Logf("Error updating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	events, _ := certBakcend.ListAuditEvents(AuditFilter{})
+	if events[0].Sequence != 5 || events[0].PrevHash != events[1].Hash {
+		t.Logf("Expecting event 5 chained to event 4 got %d", events[0].Sequence)
+		t.FailNow()
+	}
+}
+
+func TestChainAuditEvents(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	_, err := certBakcend.CreateTag("web")
+	if err == nil {
+		_, err = certBakcend.CreateTag("db")
+	}
+	if err != nil {
+		t.Logf("Error creating tags %s", err.Error())
+		t.FailNow()
+	}
+
+	// events recorded before the audit chain
+	result := certBakcend.db.Exec("UPDATE audit_events SET sequence = 0, prev_hash = '', hash = ''")
+	if result.Error != nil {
+		t.Logf("Error unchaining events %s", result.Error.Error())
+		t.FailNow()
+	}
+
+	verification, _ := certBakcend.VerifyAuditChain()
+	if verification.Valid {
+		t.Logf("Expecting unchained events to be invalid")
+		t.FailNow()
+	}
+
+	err = certBakcend.ChainAuditEvents()
+	if err != nil {
+		t.Logf("Error chaining events %s", err.Error())
+		t.FailNow()
+	}
+
+	verification, _ = certBakcend.VerifyAuditChain()
+	if !verification.Valid || verification.Count != 2 {
+		t.Logf("Expecting valid chain of 2 events got %+v", verification.Errors)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,225 @@
+package data
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AutoTagRule defines a rule tagging the certificates matching all its conditions (e.g. the
+// certs issued by the internal CA with internal-pki), the rules are applied to the created certs
+// swagger:model
+type AutoTagRule struct {
+	// the id for the rule
+	//
+	// required: false
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primary_key;"`
+
+	// the name of the rule
+	//
+	// required: true
+	Name string `json:"name" gorm:"uniqueIndex" validate:"required,max=50"`
+
+	// the id of the tag of the matching certs
+	//
+	// required: false
+	TagID uuid.UUID `json:"tag_id" gorm:"type:uuid;index"`
+
+	// the name of the tag of the matching certs
+	//
+	// required: true
+	TagName string `json:"tag" gorm:"-" validate:"required"`
+
+	// the regexp the Issuer must match (e.g. ^CN=Example Internal CA)
+	//
+	// required: false
+	IssuerPattern string `json:"issuer_pattern,omitempty" validate:"max=256"`
+
+	// the regexp the Subject must match
+	//
+	// required: false
+	SubjectPattern string `json:"subject_pattern,omitempty" validate:"max=256"`
+
+	// the regexp one of the SANs must match (e.g. \.payments\.example\.com$)
+	//
+	// required: false
+	SANPattern string `json:"san_pattern,omitempty" validate:"max=256"`
+
+	// the public key algorithm (RSA, ECDSA, Ed25519, DSA)
+	//
+	// required: false
+	PublicKeyAlgorithm string `json:"public_key_algorithm,omitempty" validate:"omitempty,oneof=RSA ECDSA Ed25519 DSA"`
+
+	// if the cert is a CA
+	//
+	// required: false
+	IsCA *bool `json:"is_ca,omitempty"`
+
+	// the Extended Key Usage the cert must have (e.g. serverAuth or an OID)
+	//
+	// required: false
+	ExtKeyUsage string `json:"ext_key_usage,omitempty" validate:"max=100"`
+
+	// the creation date of the rule
+	//
+	// required: false
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (rule *AutoTagRule) BeforeCreate(tx *gorm.DB) (err error) {
+	if rule.ID == uuid.Nil {
+		uuid := uuid.New()
+		rule.ID = uuid
+	}
+
+	return
+}
+
+// AutoTagRules defines a slice of AutoTagRule
+type AutoTagRules []*AutoTagRule
+
+// autoTagMatcher the compiled conditions of an AutoTagRule
+type autoTagMatcher struct {
+	rule    *AutoTagRule
+	issuer  *regexp.Regexp
+	subject *regexp.Regexp
+	san     *regexp.Regexp
+}
+
+// compile return the matcher of the rule, an invalid condition returns a DBObjectValidationError
+func (rule *AutoTagRule) compile() (*autoTagMatcher, error) {
+
+	if rule.IssuerPattern == "" && rule.SubjectPattern == "" && rule.SANPattern == "" &&
+		rule.PublicKeyAlgorithm == "" && rule.IsCA == nil && rule.ExtKeyUsage == "" {
+		return nil, &DBObjectValidationError{Msg: fmt.Sprintf("rule '%s' has no condition", rule.Name)}
+	}
+
+	if rule.ExtKeyUsage != "" && !IsExtKeyUsageName(rule.ExtKeyUsage) {
+		return nil, &DBObjectValidationError{Msg: fmt.Sprintf("invalid extended key usage '%s'", rule.ExtKeyUsage)}
+	}
+
+	matcher := &autoTagMatcher{rule: rule}
+	for _, p := range []struct {
+		field   string
+		pattern string
+		regexp  **regexp.Regexp
+	}{
+		{"issuer", rule.IssuerPattern, &matcher.issuer},
+		{"subject", rule.SubjectPattern, &matcher.subject},
+		{"san", rule.SANPattern, &matcher.san},
+	} {
+		if p.pattern == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(p.pattern)
+		if err != nil {
+			return nil, &DBObjectValidationError{Msg: fmt.Sprintf("invalid %s pattern '%s': %s", p.field, p.pattern, err.Error())}
+		}
+		*p.regexp = re
+	}
+
+	return matcher, nil
+}
+
+// match return true if the cert (with its SANs) matches all the conditions of the rule
+func (matcher *autoTagMatcher) match(cert *Certificate) bool {
+
+	rule := matcher.rule
+
+	if matcher.issuer != nil && !matcher.issuer.MatchString(cert.Issuer) {
+		return false
+	}
+
+	if matcher.subject != nil && !matcher.subject.MatchString(cert.Subject) {
+		return false
+	}
+
+	if matcher.san != nil {
+		found := false
+		for _, san := range cert.SubjectAltNames {
+			found = found || matcher.san.MatchString(san.Value)
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if rule.PublicKeyAlgorithm != "" && rule.PublicKeyAlgorithm != cert.PublicKeyAlgorithm {
+		return false
+	}
+
+	if rule.IsCA != nil && *rule.IsCA != cert.IsCA {
+		return false
+	}
+
+	if rule.ExtKeyUsage != "" && !listContains(cert.ExtKeyUsage, rule.ExtKeyUsage) {
+		return false
+	}
+
+	return true
+}
+
+// listContains return true if value is an element of the '|' separated list
+func listContains(list string, value string) bool {
+	for _, v := range strings.Split(list, "|") {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AutoTagMatch a certificate tagged by an auto-tag rule
+// swagger:model
+type AutoTagMatch struct {
+	// the id of the rule
+	//
+	// required: false
+	RuleID uuid.UUID `json:"rule_id"`
+	// the name of the rule
+	//
+	// required: false
+	RuleName string `json:"rule_name"`
+	// the name of the tag added to the certificate
+	//
+	// required: false
+	Tag string `json:"tag"`
+	// the id of the certificate
+	//
+	// required: false
+	CertificateID uuid.UUID `json:"certificate_id"`
+	// the Subject of the certificate
+	//
+	// required: false
+	Subject string `json:"subject"`
+	// the sha256 fingerprint of the certificate
+	//
+	// required: false
+	SHA256 string `json:"sha256"`
+}
+
+// AutoTagReport report of applying auto-tag rules to the certificates
+// swagger:model
+type AutoTagReport struct {
+	// if the tags were only computed and not added
+	//
+	// required: false
+	DryRun bool `json:"dry_run"`
+	// the number of evaluated certificates
+	//
+	// required: false
+	Evaluated int `json:"evaluated"`
+	// the certificates tagged (or that would be tagged) by a rule, the certificates
+	// already having the tag of the rule are not listed
+	//
+	// required: false
+	Tagged []*AutoTagMatch `json:"tagged"`
+}
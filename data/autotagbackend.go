@@ -0,0 +1,335 @@
+package data
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//
+// Auto-tag rule functions
+// Create: CreateAutoTagRule
+// Read:   GetAutoTagRuleByID, ListAutoTagRules
+// Update: ApplyAutoTagRuleByID, ApplyAutoTagRules, autoTagCertificate
+// Delete: DeleteAutoTagRuleByID
+//
+
+// autoTagRuleTag a compiled rule and the tag of the matching certs
+type autoTagRuleTag struct {
+	matcher *autoTagMatcher
+	tag     Tag
+}
+
+// autoTagTarget a certificate and the tags added by the rules
+type autoTagTarget struct {
+	cert    *Certificate
+	added   []Tag
+	matches []*AutoTagMatch
+}
+
+// CreateAutoTagRule create a new auto-tag rule adding the tag (rule.TagName) to the matching certs
+// an unknown tag returns a DBObjectNotFound, an invalid condition returns a DBObjectValidationError
+func (certBackend *CertBackend) CreateAutoTagRule(rule AutoTagRule) (*AutoTagRule, error) {
+	certBackend.logger.Debug("CreateAutoTagRule: Creating rule...", "rule", rule)
+
+	validationErr := certBackend.v.Validate(&rule)
+	if validationErr != nil {
+		return nil, &DBObjectValidationError{Msg: strings.Join(validationErr.Errors(), "\n")}
+	}
+
+	_, err := rule.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	// lookup the tag (the name of the tag is used for an alias)
+	tag, err := certBackend.getTagByNameWithoutPreload(rule.TagName)
+	if err != nil {
+		return nil, &DBObjectNotFound{Err: err, ID: rule.TagName}
+	}
+	rule.TagID = tag.ID
+	rule.TagName = tag.Name
+
+	// lookup if rule already exist
+	var foundRule AutoTagRule
+	result := certBackend.db.Where("name = ?", rule.Name).Limit(1).Find(&foundRule)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected != 0 {
+		return &foundRule, &DBObjectAlreadyExist{ID: foundRule.ID.String()}
+	}
+
+	err = certBackend.auditTransaction(func(tx *gorm.DB) error {
+		result := tx.Create(&rule)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionCreate, AuditObjectAutoTagRule, rule.ID, nil, NewAuditAutoTagRuleState(&rule))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// GetAutoTagRuleByID return the auto-tag rule with uuid
+func (certBackend *CertBackend) GetAutoTagRuleByID(uuid uuid.UUID) (*AutoTagRule, error) {
+	certBackend.logger.Debug("GetAutoTagRuleByID: Getting rule...", "uuid", uuid)
+
+	rules, err := certBackend.findAutoTagRules(certBackend.db.Where("id = ?", uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return nil, &DBObjectNotFound{ID: uuid.String()}
+	}
+
+	return rules[0], nil
+}
+
+// ListAutoTagRules return all the auto-tag rules ordered by name
+func (certBackend *CertBackend) ListAutoTagRules() (AutoTagRules, error) {
+	certBackend.logger.Debug("ListAutoTagRules: Listing rules...")
+
+	return certBackend.findAutoTagRules(certBackend.db)
+}
+
+// DeleteAutoTagRuleByID permanently delete the auto-tag rule with uuid, the certs keep their tags
+func (certBackend *CertBackend) DeleteAutoTagRuleByID(uuid uuid.UUID) error {
+	certBackend.logger.Debug("DeleteAutoTagRuleByID: Deleting rule", "uuid", uuid)
+
+	rule, err := certBackend.GetAutoTagRuleByID(uuid)
+	if err != nil {
+		return err
+	}
+
+	return certBackend.auditTransaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&AutoTagRule{}, rule.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionDelete, AuditObjectAutoTagRule, rule.ID, NewAuditAutoTagRuleState(rule), nil)
+	})
+}
+
+// ApplyAutoTagRuleByID apply the auto-tag rule with uuid to all the certs, in dry run the
+// certs that would be tagged are returned without being tagged
+func (certBackend *CertBackend) ApplyAutoTagRuleByID(uuid uuid.UUID, dryRun bool) (*AutoTagReport, error) {
+	certBackend.logger.Debug("ApplyAutoTagRuleByID: Applying rule", "uuid", uuid, "dryRun", dryRun)
+
+	rule, err := certBackend.GetAutoTagRuleByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return certBackend.applyAutoTagRules(AutoTagRules{rule}, dryRun)
+}
+
+// ApplyAutoTagRules apply all the auto-tag rules to all the certs, in dry run the
+// certs that would be tagged are returned without being tagged
+func (certBackend *CertBackend) ApplyAutoTagRules(dryRun bool) (*AutoTagReport, error) {
+	certBackend.logger.Debug("ApplyAutoTagRules: Applying rules", "dryRun", dryRun)
+
+	rules, err := certBackend.ListAutoTagRules()
+	if err != nil {
+		return nil, err
+	}
+
+	return certBackend.applyAutoTagRules(rules, dryRun)
+}
+
+// autoTagCertificate add to the created cert (with its SANs) the tags of the matching rules
+// in the transaction creating the cert (see withTx)
+func (certBackend *CertBackend) autoTagCertificate(cert *Certificate) error {
+
+	rules, err := certBackend.ListAutoTagRules()
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	ruleTags, err := certBackend.compileAutoTagRules(rules)
+	if err != nil {
+		return err
+	}
+
+	return certBackend.tagAutoTagTargets(certBackend.evaluateAutoTagRules(ruleTags, Certificates{cert}))
+}
+
+// applyAutoTagRules apply the rules to all the certs (in one transaction)
+func (certBackend *CertBackend) applyAutoTagRules(rules AutoTagRules, dryRun bool) (*AutoTagReport, error) {
+
+	report := &AutoTagReport{DryRun: dryRun, Tagged: []*AutoTagMatch{}}
+
+	// the certs are evaluated in the transaction adding their tags
+	err := certBackend.auditTransaction(func(tx *gorm.DB) error {
+		txBackend := certBackend.withTx(tx)
+
+		ruleTags, err := txBackend.compileAutoTagRules(rules)
+		if err != nil {
+			return err
+		}
+
+		var certList Certificates
+		result := tx.Preload("Tags").Preload("Labels").Preload("SubjectAltNames").Order("created_at").Find(&certList)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		targets := txBackend.evaluateAutoTagRules(ruleTags, certList)
+
+		report.Evaluated = len(certList)
+		for _, target := range targets {
+			report.Tagged = append(report.Tagged, target.matches...)
+		}
+
+		if dryRun {
+			return nil
+		}
+
+		return txBackend.tagAutoTagTargets(targets)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// findAutoTagRules return the rules of the query ordered by name with the name of their tag
+func (certBackend *CertBackend) findAutoTagRules(query *gorm.DB) (AutoTagRules, error) {
+
+	rules := AutoTagRules{}
+	result := query.Order("name").Find(&rules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if len(rules) == 0 {
+		return rules, nil
+	}
+
+	var tagIDs []uuid.UUID
+	for _, r := range rules {
+		tagIDs = append(tagIDs, r.TagID)
+	}
+
+	// the tags in the trash are still named
+	var tags Tags
+	result = certBackend.db.Unscoped().Where("id IN ?", tagIDs).Find(&tags)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, r := range rules {
+		for _, t := range tags {
+			if t.ID == r.TagID {
+				r.TagName = t.Name
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+// compileAutoTagRules return the compiled rules with their tag, the rules of the tags in the trash are skipped
+func (certBackend *CertBackend) compileAutoTagRules(rules AutoTagRules) ([]*autoTagRuleTag, error) {
+
+	var ruleTags []*autoTagRuleTag
+	for _, r := range rules {
+		matcher, err := r.compile()
+		if err != nil {
+			return nil, err
+		}
+
+		var tag Tag
+		result := certBackend.db.Where("id = ?", r.TagID).First(&tag)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			certBackend.logger.Debug("compileAutoTagRules: skipping rule of a deleted tag", "rule", r.Name, "tagID", r.TagID)
+			continue
+		}
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		ruleTags = append(ruleTags, &autoTagRuleTag{matcher: matcher, tag: tag})
+	}
+
+	return ruleTags, nil
+}
+
+// evaluateAutoTagRules return the certs (with their tags and SANs) matching a rule
+// and the tags of the rules the certs do not have yet
+func (certBackend *CertBackend) evaluateAutoTagRules(ruleTags []*autoTagRuleTag, certList Certificates) []*autoTagTarget {
+
+	var targets []*autoTagTarget
+	for _, cert := range certList {
+		target := &autoTagTarget{cert: cert}
+
+		for _, rt := range ruleTags {
+			if certBackend.isTagAlreadyInList(&rt.tag, cert.Tags) || certBackend.isTagAlreadyInList(&rt.tag, target.added) || !rt.matcher.match(cert) {
+				continue
+			}
+
+			target.added = append(target.added, rt.tag)
+			target.matches = append(target.matches, &AutoTagMatch{
+				RuleID:        rt.matcher.rule.ID,
+				RuleName:      rt.matcher.rule.Name,
+				Tag:           rt.tag.Name,
+				CertificateID: cert.ID,
+				Subject:       cert.Subject,
+				SHA256:        cert.SHA256,
+			})
+		}
+
+		if len(target.added) != 0 {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// tagAutoTagTargets add the tags to the certs and record the changes in the audit log in one transaction
+func (certBackend *CertBackend) tagAutoTagTargets(targets []*autoTagTarget) error {
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	err := certBackend.auditTransaction(func(tx *gorm.DB) error {
+		for _, target := range targets {
+			for _, t := range target.added {
+				result := tx.Exec("INSERT INTO tags_ref (certificate_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING", target.cert.ID, t.ID)
+				if result.Error != nil {
+					return result.Error
+				}
+			}
+
+			before := NewAuditCertState(target.cert)
+			after := *target.cert
+			after.Tags = append(append([]Tag{}, target.cert.Tags...), target.added...)
+			err := certBackend.withTx(tx).recordAudit(AuditActionSetTags, AuditObjectCertificate, target.cert.ID, before, NewAuditCertState(&after))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		target.cert.Tags = append(target.cert.Tags, target.added...)
+	}
+
+	return nil
+}
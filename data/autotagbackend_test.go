@@ -0,0 +1,173 @@
+package data
+
+import (
+	"testing"
+)
+
+func TestAutoTagRules(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	for _, name := range []string{"internal-pki", "ca", "web"} {
+		_, err := certBakcend.CreateTag(name)
+		if err != nil {
+			t.Logf("Error creating tag %s", err.Error())
+			t.FailNow()
+		}
+	}
+
+	isCA := true
+	_, err := certBakcend.CreateAutoTagRule(AutoTagRule{Name: "internal", TagName: "internal-pki", IssuerPattern: "^CN=Acme Test Issuing CA,"})
+	if err == nil {
+		_, err = certBakcend.CreateAutoTagRule(AutoTagRule{Name: "ca", TagName: "ca", IsCA: &isCA})
+	}
+	if err != nil {
+		t.Logf("Error creating rule %s", err.Error())
+		t.FailNow()
+	}
+
+	// the rules are applied on ingest
+	root, err := certBakcend.CreateCertificate(testRootPEM)
+	if err != nil || len(root.Tags) != 1 || root.Tags[0].Name != "ca" {
+		t.Logf("Expecting the root tagged with ca got %v (%v)", root, err)
+		t.FailNow()
+	}
+
+	leaf, err := certBakcend.CreateCertificateWithTags(testLeafPEM, []string{"web"})
+	if err != nil || len(leaf.Tags) != 2 {
+		t.Logf("Expecting the leaf tagged with internal-pki and web got %v (%v)", leaf, err)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.CreateCertificate(testIntermediatePEM)
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	// a rule created later is applied to the existing certs
+	web, err := certBakcend.CreateAutoTagRule(AutoTagRule{
+		Name:        "web",
+		TagName:     "web",
+		SANPattern:  `\.example\.com$`,
+		ExtKeyUsage: "serverAuth",
+	})
+	if err != nil {
+		t.Logf("Error creating rule %s", err.Error())
+		t.FailNow()
+	}
+
+	report, err := certBakcend.ApplyAutoTagRuleByID(web.ID, true)
+	if err != nil || report.Evaluated != 3 || len(report.Tagged) != 0 {
+		t.Logf("Expecting no cert to tag (the leaf is already tagged) got %v (%v)", report, err)
+		t.FailNow()
+	}
+
+	leaf, err = certBakcend.DeleteCertificateTagsByID(leaf.ID, []string{"web"})
+	if err != nil {
+		t.Logf("Error removing tag %s", err.Error())
+		t.FailNow()
+	}
+
+	// the dry run does not tag
+	for _, dryRun := range []bool{true, true, false} {
+		report, err = certBakcend.ApplyAutoTagRules(dryRun)
+		if err != nil || len(report.Tagged) != 1 || report.Tagged[0].CertificateID != leaf.ID || report.Tagged[0].Tag != "web" {
+			t.Logf("Expecting the leaf to tag with web got %v (%v)", report, err)
+			t.FailNow()
+		}
+	}
+
+	report, err = certBakcend.ApplyAutoTagRules(false)
+	if err != nil || len(report.Tagged) != 0 {
+		t.Logf("Expecting no cert to tag got %v (%v)", report, err)
+		t.FailNow()
+	}
+
+	certs, err := certBakcend.ListCertsWithFilter(CertFilter{Tag: "web"})
+	if err != nil || len(certs) != 1 {
+		t.Logf("Expecting 1 cert tagged with web got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+
+	invalids := []AutoTagRule{
+		{Name: "empty", TagName: "web"},
+		{Name: "regexp", TagName: "web", SANPattern: "(example"},
+		{Name: "eku", TagName: "web", ExtKeyUsage: "webServer"},
+		{Name: "key", TagName: "web", PublicKeyAlgorithm: "rsa"},
+		{Name: "", TagName: "web", IsCA: &isCA},
+	}
+
+	for _, invalid := range invalids {
+		_, err = certBakcend.CreateAutoTagRule(invalid)
+		if _, ok := err.(*DBObjectValidationError); !ok {
+			t.Logf("Expecting validation error for rule %v got %v", invalid, err)
+			t.FailNow()
+		}
+	}
+
+	_, err = certBakcend.CreateAutoTagRule(AutoTagRule{Name: "web", TagName: "web", IsCA: &isCA})
+	if _, ok := err.(*DBObjectAlreadyExist); !ok {
+		t.Logf("Expecting already exist error got %v", err)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.CreateAutoTagRule(AutoTagRule{Name: "missing", TagName: "missing", IsCA: &isCA})
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting not found error got %v", err)
+		t.FailNow()
+	}
+
+	err = certBakcend.DeleteAutoTagRuleByID(web.ID)
+	if err == nil {
+		_, err = certBakcend.GetAutoTagRuleByID(web.ID)
+	}
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting not found error got %v", err)
+		t.FailNow()
+	}
+
+	rules, err := certBakcend.ListAutoTagRules()
+	if err != nil || len(rules) != 2 || rules[0].TagName != "ca" {
+		t.Logf("Expecting the ca and internal rules got %v (%v)", rules, err)
+		t.FailNow()
+	}
+}
+
+func TestAutoTagRollback(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	_, err := certBakcend.CreateTag("ca")
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	isCA := true
+	_, err = certBakcend.CreateAutoTagRule(AutoTagRule{Name: "ca", TagName: "ca", IsCA: &isCA})
+	if err != nil {
+		t.Logf("Error creating rule %s", err.Error())
+		t.FailNow()
+	}
+
+	// the tags cannot be added anymore
+	result := certBakcend.db.Exec("CREATE TRIGGER tags_ref_fail BEFORE INSERT ON tags_ref BEGIN SELECT RAISE(ABORT, 'tags unavailable'); END")
+	if result.Error != nil {
+		t.Logf("Error creating trigger %s", result.Error.Error())
+		t.FailNow()
+	}
+
+	// the created cert is rolled back with its tags
+	_, err = certBakcend.CreateCertificate(testRootPEM)
+	if err == nil {
+		t.Logf("Expecting error auto-tagging the cert")
+		t.FailNow()
+	}
+
+	certs, err := certBakcend.ListCerts()
+	if err != nil || len(certs) != 0 {
+		t.Logf("Expecting no cert got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,109 @@
+package data
+
+import (
+	"github.com/google/uuid"
+)
+
+// maxBulkTagTargets the max number of ids and fingerprints, and of selected certs, of a bulk tag operation
+const maxBulkTagTargets = 1000
+
+// BulkTagSelection the certificates targeted by a bulk tag operation: the certs with
+// the ids, the certs with the fingerprints and the certs matching the search query
+// empty fields are ignored
+type BulkTagSelection struct {
+	// the ids of the certs
+	IDs []uuid.UUID
+	// the hex fingerprints (sha256, sha1 or md5) of the certs
+	Fingerprints []string
+	// the search query the certs must match (e.g. issuer:*Example* AND not_after<2026-12-01)
+	Query string
+}
+
+// isEmpty return true if the selection does not target any cert
+func (selection *BulkTagSelection) isEmpty() bool {
+	return len(selection.IDs) == 0 && len(selection.Fingerprints) == 0 && selection.Query == ""
+}
+
+// BulkTagStatus the outcome of a bulk tag operation on a single certificate
+type BulkTagStatus string
+
+const (
+	// BulkTagUpdated tags were added or removed on the certificate
+	BulkTagUpdated BulkTagStatus = "updated"
+	// BulkTagUnchanged the certificate already had the tags to add and none of the tags to remove
+	BulkTagUnchanged BulkTagStatus = "unchanged"
+	// BulkTagNotFound no certificate has the id or fingerprint
+	BulkTagNotFound BulkTagStatus = "not_found"
+)
+
+// BulkTagResult result of a bulk tag operation on a single certificate
+// swagger:model
+type BulkTagResult struct {
+	// the id or fingerprint of the request, 'query' for the certificates only matching the query
+	//
+	// required: false
+	Target string `json:"target"`
+	// the id of the certificate (empty if not found)
+	//
+	// required: false
+	CertificateID *uuid.UUID `json:"certificate_id,omitempty"`
+	// the outcome: updated, unchanged or not_found
+	//
+	// required: false
+	Status BulkTagStatus `json:"status"`
+	// the tags added to the certificate
+	//
+	// required: false
+	AddedTags []string `json:"added_tags,omitempty"`
+	// the tags removed from the certificate
+	//
+	// required: false
+	RemovedTags []string `json:"removed_tags,omitempty"`
+	// the tags of the certificate after the operation
+	//
+	// required: false
+	Tags []string `json:"tags,omitempty"`
+}
+
+// BulkTagReport report of a bulk tag operation
+// swagger:model
+type BulkTagReport struct {
+	// the number of updated certificates
+	//
+	// required: false
+	Updated int `json:"updated"`
+	// the number of unchanged certificates
+	//
+	// required: false
+	Unchanged int `json:"unchanged"`
+	// the number of ids and fingerprints not found
+	//
+	// required: false
+	NotFound int `json:"not_found"`
+	// the result per certificate (in the order of the ids, the fingerprints then the query)
+	//
+	// required: false
+	Results []*BulkTagResult `json:"results"`
+}
+
+// NewBulkTagReport create an empty BulkTagReport
+func NewBulkTagReport() *BulkTagReport {
+	return &BulkTagReport{
+		Results: []*BulkTagResult{},
+	}
+}
+
+// add append the result to the report
+func (report *BulkTagReport) add(result *BulkTagResult) {
+
+	switch result.Status {
+	case BulkTagUpdated:
+		report.Updated++
+	case BulkTagUnchanged:
+		report.Unchanged++
+	case BulkTagNotFound:
+		report.NotFound++
+	}
+
+	report.Results = append(report.Results, result)
+}
@@ -0,0 +1,269 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//
+// Bulk tag functions
+// Update: BulkUpdateCertTags
+//
+
+// bulkTagTarget a certificate targeted by a bulk tag operation and its changes
+type bulkTagTarget struct {
+	result  *BulkTagResult
+	cert    *Certificate
+	added   []Tag
+	removed []Tag
+}
+
+// BulkUpdateCertTags add the tags (addTags) to and remove the tags (removeTags) from the certs of the
+// selection in one transaction and return the result per cert, an unknown tag returns a DBObjectNotFound
+// and nothing is changed, the ids and fingerprints not found are reported in the results
+func (certBackend *CertBackend) BulkUpdateCertTags(selection BulkTagSelection, addTags []string, removeTags []string) (*BulkTagReport, error) {
+	certBackend.logger.Debug("BulkUpdateCertTags: Updating tags", "selection", selection, "addTags", addTags, "removeTags", removeTags)
+
+	if len(addTags) == 0 && len(removeTags) == 0 {
+		return nil, &DBObjectValidationError{Msg: "no tags to add or remove"}
+	}
+
+	if selection.isEmpty() {
+		return nil, &DBObjectValidationError{Msg: "no ids, fingerprints or query to select the certificates"}
+	}
+
+	if len(selection.IDs)+len(selection.Fingerprints) > maxBulkTagTargets {
+		return nil, &DBObjectValidationError{Msg: fmt.Sprintf("too many ids and fingerprints (max %d)", maxBulkTagTargets)}
+	}
+
+	// lookup the tags
+	added, err := certBackend.resolveBulkTags(addTags)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := certBackend.resolveBulkTags(removeTags)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range added {
+		if certBackend.isTagAlreadyInList(&t, removed) {
+			return nil, &DBObjectValidationError{Msg: fmt.Sprintf("tag '%s' cannot be both added and removed", t.Name)}
+		}
+	}
+
+	targets, results, err := certBackend.selectBulkTagTargets(selection)
+	if err != nil {
+		return nil, err
+	}
+
+	err = certBackend.auditTransaction(func(tx *gorm.DB) error {
+		for _, target := range targets {
+
+			// compute the changes of the cert from its tags in tx
+			var tags []Tag
+			err := tx.Model(target.cert).Association("Tags").Find(&tags)
+			if err != nil {
+				return err
+			}
+			target.cert.Tags = tags
+
+			for _, t := range added {
+				if !certBackend.isTagAlreadyInList(&t, target.cert.Tags) {
+					target.added = append(target.added, t)
+				}
+			}
+
+			for _, t := range removed {
+				if certBackend.isTagAlreadyInList(&t, target.cert.Tags) {
+					target.removed = append(target.removed, t)
+				}
+			}
+
+			for _, t := range target.added {
+				result := tx.Exec("INSERT INTO tags_ref (certificate_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING", target.cert.ID, t.ID)
+				if result.Error != nil {
+					return result.Error
+				}
+			}
+
+			if len(target.removed) != 0 {
+				var tagIDs []uuid.UUID
+				for _, t := range target.removed {
+					tagIDs = append(tagIDs, t.ID)
+				}
+
+				result := tx.Exec("DELETE FROM tags_ref WHERE certificate_id = ? AND tag_id IN ?", target.cert.ID, tagIDs)
+				if result.Error != nil {
+					return result.Error
+				}
+			}
+
+			err = certBackend.recordBulkTagAudit(tx, target)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := NewBulkTagReport()
+	for _, result := range results {
+		report.add(result)
+	}
+
+	return report, nil
+}
+
+// resolveBulkTags return the tags with the names (or aliases), an unknown tag returns a DBObjectNotFound
+func (certBackend *CertBackend) resolveBulkTags(tagList []string) ([]Tag, error) {
+
+	var tags []Tag
+	for _, t := range tagList {
+		tag, err := certBackend.getTagByNameWithoutPreload(t)
+		if err != nil {
+			// if one tag does not exist abort update
+			return nil, &DBObjectNotFound{Err: err, ID: t}
+		}
+
+		if !certBackend.isTagAlreadyInList(tag, tags) {
+			tags = append(tags, *tag)
+		}
+	}
+
+	return tags, nil
+}
+
+// selectBulkTagTargets return the certs of the selection (each cert once) and the results of the
+// selection in its order (the results of the ids and fingerprints not found included), an invalid
+// fingerprint or query returns a DBObjectValidationError as well as more than maxBulkTagTargets certs
+func (certBackend *CertBackend) selectBulkTagTargets(selection BulkTagSelection) ([]*bulkTagTarget, []*BulkTagResult, error) {
+
+	var targets []*bulkTagTarget
+	var results []*BulkTagResult
+	selected := map[uuid.UUID]bool{}
+
+	addTarget := func(target string, cert *Certificate, err error) error {
+		if _, ok := err.(*DBObjectNotFound); ok {
+			results = append(results, &BulkTagResult{Target: target, Status: BulkTagNotFound})
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !selected[cert.ID] {
+			selected[cert.ID] = true
+			result := &BulkTagResult{Target: target}
+			results = append(results, result)
+			targets = append(targets, &bulkTagTarget{result: result, cert: cert})
+		}
+
+		return nil
+	}
+
+	for _, id := range selection.IDs {
+		cert, err := certBackend.GetCertByID(id)
+		err = addTarget(id.String(), cert, err)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, fingerprint := range selection.Fingerprints {
+		algorithm, err := DetectFingerprintAlgorithm(fingerprint)
+		if err != nil {
+			return nil, nil, &DBObjectValidationError{Msg: fmt.Sprintf("invalid fingerprint '%s': %s", fingerprint, err.Error())}
+		}
+
+		cert, err := certBackend.GetCertByFingerprintAlgorithm(algorithm, fingerprint)
+		err = addTarget(fingerprint, cert, err)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if selection.Query != "" {
+		condition, err := parseCertSearch(selection.Query, time.Now())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// one more than the max to detect a query matching too many certs
+		var certList Certificates
+		result := certBackend.db.Preload("Tags").Preload("Labels").Where(condition.clause, condition.args...).
+			Order("certificates.created_at").Limit(maxBulkTagTargets + 1).Find(&certList)
+		if result.Error != nil {
+			return nil, nil, result.Error
+		}
+
+		for _, cert := range certList {
+			err = addTarget("query", cert, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if len(targets) > maxBulkTagTargets {
+		return nil, nil, &DBObjectValidationError{Msg: fmt.Sprintf("too many certificates selected (max %d)", maxBulkTagTargets)}
+	}
+
+	return targets, results, nil
+}
+
+// recordBulkTagAudit set the result of the target and record the added and removed tags in the audit log in tx
+func (certBackend *CertBackend) recordBulkTagAudit(tx *gorm.DB, target *bulkTagTarget) error {
+
+	cert := target.cert
+	target.result.CertificateID = &cert.ID
+
+	if len(target.added) == 0 && len(target.removed) == 0 {
+		target.result.Status = BulkTagUnchanged
+	} else {
+		target.result.Status = BulkTagUpdated
+	}
+
+	if len(target.added) != 0 {
+		before := NewAuditCertState(cert)
+		cert.Tags = append(cert.Tags, target.added...)
+		err := certBackend.withTx(tx).recordAudit(AuditActionSetTags, AuditObjectCertificate, cert.ID, before, NewAuditCertState(cert))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(target.removed) != 0 {
+		before := NewAuditCertState(cert)
+		var tags []Tag
+		for _, t := range cert.Tags {
+			if !certBackend.isTagAlreadyInList(&t, target.removed) {
+				tags = append(tags, t)
+			}
+		}
+		cert.Tags = tags
+		err := certBackend.withTx(tx).recordAudit(AuditActionRemoveTags, AuditObjectCertificate, cert.ID, before, NewAuditCertState(cert))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, t := range target.added {
+		target.result.AddedTags = append(target.result.AddedTags, t.Name)
+	}
+	for _, t := range target.removed {
+		target.result.RemovedTags = append(target.result.RemovedTags, t.Name)
+	}
+	for _, t := range cert.Tags {
+		target.result.Tags = append(target.result.Tags, t.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBulkUpdateCertTags(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	for _, name := range []string{"prod", "legacy", "web"} {
+		_, err := certBakcend.CreateTag(name)
+		if err != nil {
+			t.Logf("Error creating tag %s", err.Error())
+			t.FailNow()
+		}
+	}
+
+	root, err := certBakcend.CreateCertificateWithTags(testRootPEM, []string{"legacy"})
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	leaf, err := certBakcend.CreateCertificateWithTags(testLeafPEM, []string{"prod"})
+	if err == nil {
+		_, err = certBakcend.CreateCertificate(testIntermediatePEM)
+	}
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	missing := uuid.New()
+	selection := BulkTagSelection{
+		IDs:          []uuid.UUID{root.ID, missing},
+		Fingerprints: []string{leaf.SHA256},
+		Query:        "san:*.example.com",
+	}
+
+	report, err := certBakcend.BulkUpdateCertTags(selection, []string{"prod", "web"}, []string{"legacy"})
+	if err != nil {
+		t.Logf("Error updating tags %s", err.Error())
+		t.FailNow()
+	}
+
+	// the leaf is selected by fingerprint and by the query but reported once
+	if report.Updated != 2 || report.Unchanged != 0 || report.NotFound != 1 || len(report.Results) != 3 {
+		t.Logf("Expecting 2 updated and 1 not found got %v", report)
+		t.FailNow()
+	}
+
+	if report.Results[0].Target != root.ID.String() || len(report.Results[0].AddedTags) != 2 || len(report.Results[0].RemovedTags) != 1 {
+		t.Logf("Expecting 2 tags added and 1 removed on the root got %v", report.Results[0])
+		t.FailNow()
+	}
+
+	// the results are in the order of the selection
+	if report.Results[1].Target != missing.String() || report.Results[1].Status != BulkTagNotFound {
+		t.Logf("Expecting %s not found got %v", missing, report.Results[1])
+		t.FailNow()
+	}
+
+	if report.Results[2].Target != leaf.SHA256 || report.Results[2].Status != BulkTagUpdated {
+		t.Logf("Expecting %s updated got %v", leaf.SHA256, report.Results[2])
+		t.FailNow()
+	}
+
+	certs, err := certBakcend.ListCertsWithFilter(CertFilter{Tag: "web"})
+	if err != nil || len(certs) != 2 {
+		t.Logf("Expecting 2 certs tagged with web got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+
+	// applying the same operation again does not change anything
+	report, err = certBakcend.BulkUpdateCertTags(selection, []string{"prod", "web"}, []string{"legacy"})
+	if err != nil || report.Updated != 0 || report.Unchanged != 2 {
+		t.Logf("Expecting 2 unchanged certs got %v (%v)", report, err)
+		t.FailNow()
+	}
+
+	// an unknown tag aborts the operation
+	_, err = certBakcend.BulkUpdateCertTags(BulkTagSelection{Query: "is_ca=true"}, []string{"legacy", "missing"}, nil)
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting not found error got %v", err)
+		t.FailNow()
+	}
+
+	certs, err = certBakcend.ListCertsWithFilter(CertFilter{Tag: "legacy"})
+	if err != nil || len(certs) != 0 {
+		t.Logf("Expecting no cert tagged with legacy got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+
+	invalids := []struct {
+		selection  BulkTagSelection
+		addTags    []string
+		removeTags []string
+	}{
+		{BulkTagSelection{}, []string{"prod"}, nil},
+		{BulkTagSelection{Query: "is_ca=true"}, nil, nil},
+		{BulkTagSelection{Query: "is_ca=true"}, []string{"prod"}, []string{"prod"}},
+		{BulkTagSelection{Query: "not_after<"}, []string{"prod"}, nil},
+		{BulkTagSelection{Fingerprints: []string{"abcd"}}, []string{"prod"}, nil},
+	}
+
+	for _, invalid := range invalids {
+		_, err = certBakcend.BulkUpdateCertTags(invalid.selection, invalid.addTags, invalid.removeTags)
+		if _, ok := err.(*DBObjectValidationError); !ok {
+			t.Logf("Expecting validation error for %v got %v", invalid, err)
+			t.FailNow()
+		}
+	}
+
+	events, err := certBakcend.ListAuditEvents(AuditFilter{Action: AuditActionRemoveTags})
+	if err != nil || len(events) != 1 {
+		t.Logf("Expecting 1 remove_tags event got %d (%v)", len(events), err)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,63 @@
+package data
+
+// BundleEntryStatus the outcome of the import of a single entry of a bundle
+type BundleEntryStatus string
+
+const (
+	// BundleEntryFailed the entry could not be parsed or inserted
+	BundleEntryFailed BundleEntryStatus = "failed"
+	// BundleEntrySkipped the entry is not a certificate
+	BundleEntrySkipped BundleEntryStatus = "skipped"
+)
+
+// BundleEntryReport report of the import of a single entry of a bundle
+// swagger:model
+type BundleEntryReport struct {
+	// the position of the entry in the bundle (starting at 0)
+	//
+	// required: false
+	Index int `json:"index"`
+	// the type of the entry (e.g. the PEM block type)
+	//
+	// required: false
+	Type string `json:"type"`
+	// the outcome of the import: failed or skipped
+	//
+	// required: false
+	Status BundleEntryStatus `json:"status"`
+	// the reason why the entry failed or was skipped
+	//
+	// required: false
+	Error string `json:"error,omitempty"`
+}
+
+// BundleReport report of the import of a bundle of certificates
+// swagger:model
+type BundleReport struct {
+	// the list of newly created Certificates
+	//
+	// required: false
+	Created Certificates `json:"created"`
+	// the list of Certificates that were already in the DB
+	//
+	// required: false
+	AlreadyExist Certificates `json:"already_exist"`
+	// the list of entries that could not be imported
+	//
+	// required: false
+	Failed []*BundleEntryReport `json:"failed"`
+	// the list of entries that are not certificates
+	//
+	// required: false
+	Skipped []*BundleEntryReport `json:"skipped"`
+}
+
+// NewBundleReport create an empty BundleReport
+func NewBundleReport() *BundleReport {
+	return &BundleReport{
+		Created:      Certificates{},
+		AlreadyExist: Certificates{},
+		Failed:       []*BundleEntryReport{},
+		Skipped:      []*BundleEntryReport{},
+	}
+}
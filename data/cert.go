@@ -1,6 +1,7 @@
 package data
 
 import (
+	"crypto/x509"
 	"fmt"
 	"time"
 
@@ -20,34 +21,74 @@ type Certificate struct {
 	//
 	// required: false
 	SHA256 string `json:"sha256"  gorm:"uniqueIndex" validate:"required"`
+	// the sha1 of the pem cert (e.g. Windows thumbprint)
+	//
+	// required: false
+	SHA1 string `json:"sha1" gorm:"index"`
+	// the md5 of the pem cert (legacy inventories)
+	//
+	// required: false
+	MD5 string `json:"md5" gorm:"index"`
 	// the Subject of the pem cert
 	//
 	// required: false
-	Subject string `json:"subject,omitempty"`
+	Subject string `json:"subject,omitempty" gorm:"index"`
 	// the Issuer of the pem cert
 	//
 	// required: false
-	Issuer string `json:"issuer,omitempty"`
+	Issuer string `json:"issuer,omitempty" gorm:"index"`
+	// the parsed Subject attributes of the pem cert
+	//
+	// required: false
+	SubjectDN DistinguishedName `json:"subject_dn" gorm:"embedded;embeddedPrefix:subject_"`
+	// the parsed Issuer attributes of the pem cert
+	//
+	// required: false
+	IssuerDN DistinguishedName `json:"issuer_dn" gorm:"embedded;embeddedPrefix:issuer_"`
 	// the Serial Number of the pem cert
 	//
 	// required: false
-	SerialNumber string `json:"serial_number,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty" gorm:"index"`
 	// the List of Subject Alternative Names of the pem cert
 	//
 	// required: false
 	SANs string `json:"sans,omitempty"`
+	// the List of typed Subject Alternative Names (dns, ip, email, uri) of the pem cert
+	//
+	// required: false
+	SubjectAltNames []SubjectAltName `json:"subject_alt_names" gorm:"foreignKey:CertificateID"`
 	// the Not Before validity of the pem cert
 	//
 	// required: false
-	NotBefore time.Time `json:"not_before"`
+	NotBefore time.Time `json:"not_before" gorm:"index"`
 	// the Not After validity of the pem cert
 	//
 	// required: false
-	NotAfter time.Time `json:"not_after"`
+	NotAfter time.Time `json:"not_after" gorm:"index"`
+	// the validity status of the pem cert now: valid, expiring, expired or not-yet-valid
+	//
+	// required: false
+	Status CertificateStatus `json:"status" gorm:"-"`
 	// the signature Algorithm of the pem cert
 	//
 	// required: false
-	SignatureAlgorithm string `json:"sigalg"`
+	SignatureAlgorithm string `json:"sigalg" gorm:"index"`
+	// the public key Algorithm of the pem cert (RSA, ECDSA, Ed25519, DSA)
+	//
+	// required: false
+	PublicKeyAlgorithm string `json:"public_key_algorithm" gorm:"index"`
+	// the size in bits of the public key (RSA modulus or EC curve size)
+	//
+	// required: false
+	PublicKeySize int `json:"public_key_size" gorm:"index"`
+	// the curve of an ECDSA public key
+	//
+	// required: false
+	PublicKeyCurve string `json:"public_key_curve,omitempty"`
+	// the base64 sha256 of the SubjectPublicKeyInfo (HPKP pin-sha256)
+	//
+	// required: false
+	SPKISHA256 string `json:"spki_sha256" gorm:"column:spki_sha256;index"`
 	// the Authority Key ID of the pem cert
 	//
 	// required: false
@@ -72,24 +113,130 @@ type Certificate struct {
 	//
 	// required: false
 	IsCA bool `json:"is_ca"`
-	// the Raw PEM string of the pem cert
+	// the Key Usages of the pem cert (e.g. digitalSignature|keyEncipherment)
+	//
+	// required: false
+	KeyUsage string `json:"key_usage"`
+	// the Extended Key Usages of the pem cert (e.g. serverAuth|clientAuth), unknown usages as OID
+	//
+	// required: false
+	ExtKeyUsage string `json:"ext_key_usage"`
+	// the path length constraint of the pem cert if it is a CA (no constraint if absent)
+	//
+	// required: false
+	MaxPathLen *int `json:"max_path_len,omitempty"`
+	// the permitted name constraints of the pem cert (e.g. dns:.example.com|ip:10.0.0.0/8)
+	//
+	// required: false
+	PermittedNameConstraints string `json:"permitted_name_constraints,omitempty"`
+	// the excluded name constraints of the pem cert (e.g. dns:.example.com|ip:10.0.0.0/8)
 	//
 	// required: false
-	RawPEM string `json:"pem"`
+	ExcludedNameConstraints string `json:"excluded_name_constraints,omitempty"`
+	// if the pem cert is self-signed (subject is issuer and signature verifies with its own key)
+	//
+	// required: false
+	IsSelfSigned bool `json:"is_self_signed"`
+	// the id of the stored certificate that issued the pem cert
+	//
+	// required: false
+	IssuerID *uuid.UUID `json:"issuer_id,omitempty" gorm:"type:uuid;index"`
+	// the id of the stored certificate renewed by the pem cert
+	//
+	// required: false
+	PredecessorID *uuid.UUID `json:"predecessor_id,omitempty" gorm:"type:uuid;index"`
+	// the id of the stored certificate renewing the pem cert
+	//
+	// required: false
+	SuccessorID *uuid.UUID `json:"successor_id,omitempty" gorm:"type:uuid;index"`
+	// if the pem cert was replaced by a renewal (it has a successor)
+	//
+	// required: false
+	Replaced bool `json:"replaced" gorm:"-"`
+	// the problems found by the lint rules on the pem cert
+	//
+	// required: false
+	LintFindings []LintFinding `json:"lint_findings" gorm:"foreignKey:CertificateID"`
+	// the Raw PEM string of the pem cert (not returned by the lists)
+	//
+	// required: false
+	RawPEM string `json:"pem,omitempty"`
 	// the List of tags for the pem cert
 	//
 	// required: false
 	Tags []Tag `json:"tags"  gorm:"many2many:tags_ref;"`
 	// Tags string `json:"tags" `
+	// the List of key/value labels for the pem cert (e.g. env=prod)
+	//
+	// required: false
+	Labels []Label `json:"labels" gorm:"foreignKey:CertificateID"`
 	// the CreatedAt timestamp for the Cert
 	//
 	// required: false
-	CreatedAt time.Time `json:"-" `
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
 
 	// the UpdatedAt timestamp for the Cert
 	//
 	// required: false
-	UpdatedAt time.Time `json:"-" `
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// the DeletedAt timestamp for the Cert (only set for the certs in the trash)
+	//
+	// required: false
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	// the version of the parser that extracted the fields from the RawPEM
+	//
+	// required: false
+	ParserVersion int `json:"-" `
+
+	// the version of the lint rules (see Linter.Version) of the stored findings
+	//
+	// required: false
+	LintVersion string `json:"-" `
+}
+
+// CertificateParserVersion the current version of the fields extracted by NewCertificateFromX509
+// bump it when a new field is extracted so existing certs get refreshed on startup
+const CertificateParserVersion = 7
+
+// CertificateStatus the validity status of a certificate
+type CertificateStatus string
+
+const (
+	// CertificateStatusValid the cert is valid for more than CertificateExpiringWindow
+	CertificateStatusValid CertificateStatus = "valid"
+	// CertificateStatusExpiring the cert expires within CertificateExpiringWindow
+	CertificateStatusExpiring CertificateStatus = "expiring"
+	// CertificateStatusExpired the cert is no longer valid
+	CertificateStatusExpired CertificateStatus = "expired"
+	// CertificateStatusNotYetValid the cert is not valid yet
+	CertificateStatusNotYetValid CertificateStatus = "not-yet-valid"
+)
+
+// CertificateExpiringWindow the time before NotAfter when a cert is expiring
+var CertificateExpiringWindow = 30 * 24 * time.Hour
+
+// GetStatus return the validity status of the cert at now
+func (cert *Certificate) GetStatus(now time.Time) CertificateStatus {
+
+	switch {
+	case now.Before(cert.NotBefore):
+		return CertificateStatusNotYetValid
+	case now.After(cert.NotAfter):
+		return CertificateStatusExpired
+	case now.Add(CertificateExpiringWindow).After(cert.NotAfter):
+		return CertificateStatusExpiring
+	}
+
+	return CertificateStatusValid
+}
+
+// AfterFind will compute the status of the cert.
+func (cert *Certificate) AfterFind(tx *gorm.DB) (err error) {
+	cert.Status = cert.GetStatus(time.Now())
+	cert.Replaced = cert.SuccessorID != nil
+	return
 }
 
 // BeforeCreate will set a UUID rather than numeric ID.
@@ -114,22 +261,55 @@ func NewCertificate(pem string) (*Certificate, error) {
 		}
 	}
 
-	return &Certificate{
-		SHA256:             GetSHA256FingerprintFromX509Cert(x509Cert),
-		Subject:            GetSubjectFromX509Cert(x509Cert),
-		Issuer:             GetIssuerFromX509Cert(x509Cert),
-		SerialNumber:       GetSerialNumberFromX509Cert(x509Cert),
-		SignatureAlgorithm: GetSignatureAlgorithmFromX509Cert(x509Cert),
-		AKI:                GetAKIFromX509Cert(x509Cert),
-		SKI:                GetSKIFromX509Cert(x509Cert),
-		SANs:               GetSNASFromX509Cert(x509Cert),
-		OCSP:               GetOCSPFromX509Cert(x509Cert),
-		CRL:                GetCRLFromX509Cert(x509Cert),
-		IssuingCAUrl:       GetIssuingCAFromX509Cert(x509Cert),
-		IsCA:               GetIsCAFromX509Cert(x509Cert),
-		RawPEM:             pem,
-	}, nil
+	return NewCertificateFromX509(x509Cert), nil
+
+}
+
+// NewCertificateFromX509 Creates a Certificate from a parsed x509 Certificate
+// the RawPEM is the PEM encoding of that single certificate
+func NewCertificateFromX509(x509Cert *x509.Certificate) *Certificate {
+
+	cert := &Certificate{
+		SHA256:                   GetSHA256FingerprintFromX509Cert(x509Cert),
+		SHA1:                     GetSHA1FingerprintFromX509Cert(x509Cert),
+		MD5:                      GetMD5FingerprintFromX509Cert(x509Cert),
+		Subject:                  GetSubjectFromX509Cert(x509Cert),
+		Issuer:                   GetIssuerFromX509Cert(x509Cert),
+		SubjectDN:                NewDistinguishedName(x509Cert.Subject),
+		IssuerDN:                 NewDistinguishedName(x509Cert.Issuer),
+		SerialNumber:             GetSerialNumberFromX509Cert(x509Cert),
+		NotBefore:                x509Cert.NotBefore,
+		NotAfter:                 x509Cert.NotAfter,
+		SignatureAlgorithm:       GetSignatureAlgorithmFromX509Cert(x509Cert),
+		PublicKeyAlgorithm:       GetPublicKeyAlgorithmFromX509Cert(x509Cert),
+		PublicKeySize:            GetPublicKeySizeFromX509Cert(x509Cert),
+		PublicKeyCurve:           GetPublicKeyCurveFromX509Cert(x509Cert),
+		SPKISHA256:               GetSPKISHA256FromX509Cert(x509Cert),
+		AKI:                      GetAKIFromX509Cert(x509Cert),
+		SKI:                      GetSKIFromX509Cert(x509Cert),
+		SANs:                     GetSNASFromX509Cert(x509Cert),
+		SubjectAltNames:          GetSubjectAltNamesFromX509Cert(x509Cert),
+		OCSP:                     GetOCSPFromX509Cert(x509Cert),
+		CRL:                      GetCRLFromX509Cert(x509Cert),
+		IssuingCAUrl:             GetIssuingCAFromX509Cert(x509Cert),
+		IsCA:                     GetIsCAFromX509Cert(x509Cert),
+		KeyUsage:                 GetKeyUsageFromX509Cert(x509Cert),
+		ExtKeyUsage:              GetExtKeyUsageFromX509Cert(x509Cert),
+		MaxPathLen:               GetMaxPathLenFromX509Cert(x509Cert),
+		PermittedNameConstraints: GetPermittedNameConstraintsFromX509Cert(x509Cert),
+		ExcludedNameConstraints:  GetExcludedNameConstraintsFromX509Cert(x509Cert),
+		IsSelfSigned:             GetIsSelfSignedFromX509Cert(x509Cert),
+		RawPEM:                   GetPEMFromX509Cert(x509Cert),
+		ParserVersion:            CertificateParserVersion,
+	}
+	cert.Status = cert.GetStatus(time.Now())
+
+	return cert
+}
 
+// ToX509 parse the RawPEM of the Certificate
+func (cert *Certificate) ToX509() (*x509.Certificate, error) {
+	return ParseX509FromPEM(cert.RawPEM)
 }
 
 // Certificates a list of Certificate
@@ -2,6 +2,7 @@ package data
 
 import (
 	"testing"
+	"time"
 )
 
 func TestTagValidationFail(t *testing.T) {
@@ -38,3 +39,29 @@ func TestTagValidationSuccess(t *testing.T) {
 	}
 
 }
+
+func TestCertificateGetStatus(t *testing.T) {
+
+	cert, err := NewCertificate(testLeafPEM)
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	tests := []struct {
+		now    time.Time
+		status CertificateStatus
+	}{
+		{time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), CertificateStatusNotYetValid},
+		{time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), CertificateStatusValid},
+		{time.Date(2031, time.May, 20, 0, 0, 0, 0, time.UTC), CertificateStatusExpiring},
+		{time.Date(2031, time.June, 2, 0, 0, 0, 0, time.UTC), CertificateStatusExpired},
+	}
+
+	for _, test := range tests {
+		if status := cert.GetStatus(test.now); status != test.status {
+			t.Logf("Expecting '%s' at %s, but got '%s'", test.status, test.now, status)
+			t.FailNow()
+		}
+	}
+}
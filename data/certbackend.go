@@ -1,7 +1,9 @@
 package data
 
 import (
+	"crypto/x509"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
@@ -16,6 +18,10 @@ type CertBackend struct {
 	logger hclog.Logger
 	db     *gorm.DB
 	v      *Validation
+	linter *Linter
+	audit  AuditContext
+	// if db is a transaction started by auditTransaction (see withTx)
+	inTx bool
 }
 
 // NewCertBackend creates a new CertBackend
@@ -25,15 +31,18 @@ func NewCertBackend(logger hclog.Logger, db *gorm.DB, v *Validation) *CertBacken
 		logger: logger,
 		db:     db,
 		v:      v,
+		linter: NewLinter(DefaultLintRules()...),
+		audit:  SystemAuditContext,
 	}
 }
 
 //
 // Cert CRUD functions
-// Create: CreateCertificate, CreateCertificateWithTags
-// Read:   GetCertByID, GetCertByFingerprint, ListCerts
-// Update: SetCertTagNameByID, SetCertTagsNameByID
-// Delete: DeleteCertByID, DeleteCertPendingRecords
+// Create: CreateCertificate, CreateCertificateWithTags, CreateCertificateBundle, CreateCertificateFromData
+// Read:   GetCertByID, GetCertByFingerprint, GetCertByFingerprintAlgorithm, GetCertByIssuerAndSerial,
+//         ListCerts, ListCertsWithFilter, ListCertsPage, ListCertsByExpiry, ListCertsExpiry
+// Update: SetCertTagNameByID, SetCertTagsNameByID, RefreshCertificates
+// Delete: DeleteCertByID, DeleteCertPendingRecords, purgeCerts
 //
 
 // CreateCertificate create a new Certificate from pem and insert it into DB
@@ -45,6 +54,12 @@ func (certBackend *CertBackend) CreateCertificate(pem string) (*Certificate, err
 		return nil, err
 	}
 
+	return certBackend.insertCertificate(cert)
+}
+
+// insertCertificate validate the Certificate and insert it into DB
+func (certBackend *CertBackend) insertCertificate(cert *Certificate) (*Certificate, error) {
+
 	validationErr := certBackend.v.Validate(cert)
 	if validationErr != nil {
 		return nil, &DBObjectValidationError{Msg: strings.Join(validationErr.Errors(), "\n")}
@@ -56,12 +71,57 @@ func (certBackend *CertBackend) CreateCertificate(pem string) (*Certificate, err
 		return foundCert, &DBObjectAlreadyExist{ID: foundCert.ID.String()}
 	}
 
-	// Create new entry
-	result := certBackend.db.Create(&cert)
+	// a cert in the trash is restored when it is created again
+	var trashedCert Certificate
+	result := certBackend.db.Unscoped().Where("sha256 = ? AND deleted_at IS NOT NULL", cert.SHA256).Limit(1).Find(&trashedCert)
 	if result.Error != nil {
-		// log.Fatal(result.Error)
 		return nil, result.Error
 	}
+	if result.RowsAffected != 0 {
+		return certBackend.RestoreCertByID(trashedCert.ID)
+	}
+
+	// lint the cert, the findings are created with the cert
+	cert.LintFindings = certBackend.lintCertificate(cert)
+	cert.LintVersion = certBackend.linter.Version()
+
+	err := certBackend.auditTransaction(func(tx *gorm.DB) error {
+		txBackend := certBackend.withTx(tx)
+
+		// Create new entry
+		result := tx.Create(&cert)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		err := txBackend.recordAudit(AuditActionCreate, AuditObjectCertificate, cert.ID, nil, NewAuditCertState(cert))
+		if err != nil {
+			return err
+		}
+
+		// link the cert with its issuer and with the certs it issued
+		err = txBackend.resolveCertIssuer(cert)
+		if err != nil {
+			certBackend.logger.Error("insertCertificate: error resolving issuer", "id", cert.ID, "err", err)
+		}
+
+		err = txBackend.resolveIssuedCerts(cert)
+		if err != nil {
+			certBackend.logger.Error("insertCertificate: error resolving issued certs", "id", cert.ID, "err", err)
+		}
+
+		// link the cert with the cert it renews and its renewal
+		err = txBackend.resolveCertRenewals(cert)
+		if err != nil {
+			certBackend.logger.Error("insertCertificate: error resolving renewals", "id", cert.ID, "err", err)
+		}
+
+		// tag the cert with the tags of the matching auto-tag rules
+		return txBackend.autoTagCertificate(cert)
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cert, nil
 }
@@ -70,8 +130,22 @@ func (certBackend *CertBackend) CreateCertificate(pem string) (*Certificate, err
 func (certBackend *CertBackend) CreateCertificateWithTags(pem string, tags []string) (*Certificate, error) {
 	certBackend.logger.Debug("CreateCertificateWithTags: Creating Cert...")
 
+	cert, err := NewCertificate(pem)
+	if err != nil {
+		return nil, err
+	}
+
+	// lookup the tags before inserting the cert
+	// so an unknown tag does not leave an untagged cert
+	for _, t := range tags {
+		_, err := certBackend.getTagByNameWithoutPreload(t)
+		if err != nil {
+			return nil, &DBObjectNotFound{Err: err, ID: t}
+		}
+	}
+
 	// Create cert in db
-	cert, err := certBackend.CreateCertificate(pem)
+	cert, err = certBackend.insertCertificate(cert)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +155,96 @@ func (certBackend *CertBackend) CreateCertificateWithTags(pem string, tags []str
 	return certBackend.SetCertTagsNameByID(cert.ID, tags)
 }
 
+// CreateCertificateBundle create a new Certificate for every CERTIFICATE block of
+// the pem bundle and assign them the tags, other PEM blocks are skipped
+func (certBackend *CertBackend) CreateCertificateBundle(pem string, tags []string) (*BundleReport, error) {
+	certBackend.logger.Debug("CreateCertificateBundle: Creating Certs...", "tags", tags)
+
+	return certBackend.CreateCertificateFromData([]byte(pem), CertificateFormatPEM, "", tags)
+}
+
+// CreateCertificateFromData create a new Certificate for every certificate found in raw
+// (PEM, DER, PKCS#7 or PKCS#12 protected by password) and assign them the tags
+// private keys found in raw are reported as skipped and never stored
+func (certBackend *CertBackend) CreateCertificateFromData(raw []byte, format CertificateFormat, password string, tags []string) (*BundleReport, error) {
+	certBackend.logger.Debug("CreateCertificateFromData: Creating Certs...", "format", format, "tags", tags)
+
+	blocks, err := DecodeCertificateBlocks(raw, format, password)
+	if err != nil {
+		return nil, &DBObjectValidationError{
+			Err: err,
+			Msg: fmt.Sprintf("Invalid input: %s", err.Error()),
+		}
+	}
+
+	// lookup the tags before inserting anything
+	// so an unknown tag does not leave a partially tagged bundle
+	for _, t := range tags {
+		_, err := certBackend.getTagByNameWithoutPreload(t)
+		if err != nil {
+			return nil, &DBObjectNotFound{Err: err, ID: t}
+		}
+	}
+
+	report := NewBundleReport()
+	for i, block := range blocks {
+		entry := &BundleEntryReport{Index: i, Type: block.Type}
+
+		if block.Type != pemCertificateType {
+			entry.Status = BundleEntrySkipped
+			entry.Error = fmt.Sprintf("not a certificate: %s", block.Type)
+			report.Skipped = append(report.Skipped, entry)
+			continue
+		}
+
+		x509Cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			entry.Status = BundleEntryFailed
+			entry.Error = fmt.Sprintf("Invalid certificate: %s", err.Error())
+			report.Failed = append(report.Failed, entry)
+			continue
+		}
+
+		certBackend.addCertificateToReport(report, entry, NewCertificateFromX509(x509Cert), tags)
+	}
+
+	return report, nil
+}
+
+// addCertificateToReport insert the cert with its tags and record the outcome in the report
+// an existing cert is also assigned the tags, a cert that cannot be tagged is reported as failed
+func (certBackend *CertBackend) addCertificateToReport(report *BundleReport, entry *BundleEntryReport, cert *Certificate, tags []string) {
+
+	cert, err := certBackend.insertCertificate(cert)
+	_, alreadyExist := err.(*DBObjectAlreadyExist)
+	if err != nil && !alreadyExist {
+		entry.Status = BundleEntryFailed
+		entry.Error = err.Error()
+		report.Failed = append(report.Failed, entry)
+		return
+	}
+
+	if len(tags) != 0 {
+		taggedCert, err := certBackend.SetCertTagsNameByID(cert.ID, tags)
+		if err != nil {
+			certBackend.logger.Error("CreateCertificateBundle: error setting tags", "id", cert.ID, "err", err)
+
+			entry.Status = BundleEntryFailed
+			entry.Error = fmt.Sprintf("certificate %s stored but tags not set: %s", cert.ID.String(), err.Error())
+			report.Failed = append(report.Failed, entry)
+			return
+		}
+		cert = taggedCert
+	}
+
+	if alreadyExist {
+		report.AlreadyExist = append(report.AlreadyExist, cert)
+		return
+	}
+
+	report.Created = append(report.Created, cert)
+}
+
 // GetCertByID return Tag (without associated cert)
 func (certBackend *CertBackend) GetCertByID(uuid uuid.UUID) (*Certificate, error) {
 	certBackend.logger.Debug("GetCertByID: Getting Certificate...")
@@ -100,32 +264,156 @@ func (certBackend *CertBackend) GetCertByID(uuid uuid.UUID) (*Certificate, error
 	return &cert, nil
 }
 
-// GetCertByFingerprint return Tag (without associated cert)
+// GetCertByFingerprint return the Certificate with the sha256 fingerprint
 func (certBackend *CertBackend) GetCertByFingerprint(sha256 string) (*Certificate, error) {
-	certBackend.logger.Debug("GetCertByFingerprint: Getting Certificate...")
+	return certBackend.GetCertByFingerprintAlgorithm(FingerprintSHA256, sha256)
+}
+
+// GetCertByFingerprintAlgorithm return the Certificate with the fingerprint (lower case hex) of algorithm
+func (certBackend *CertBackend) GetCertByFingerprintAlgorithm(algorithm FingerprintAlgorithm, fingerprint string) (*Certificate, error) {
+	certBackend.logger.Debug("GetCertByFingerprintAlgorithm: Getting Certificate...", "algorithm", algorithm)
+
+	column, err := algorithm.column()
+	if err != nil {
+		return nil, err
+	}
 
 	var cert Certificate
 
-	result := certBackend.db.Preload("Tags").Where("sha256 = ?", sha256).First(&cert)
+	result := certBackend.db.Preload(clause.Associations).Where(column+" = ?", fingerprint).First(&cert)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, &DBObjectNotFound{Err: result.Error, ID: fingerprint}
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &cert, nil
+}
+
+// GetCertByIssuerAndSerial return the Certificate issued by issuer (as the Issuer of the
+// Certificate) with the hex serial number (RFC 5280 unique identifier of a certificate)
+// the issuer is compared with the parsed Issuer, so the order and case of its attributes do not matter
+func (certBackend *CertBackend) GetCertByIssuerAndSerial(issuer string, serial string) (*Certificate, error) {
+	certBackend.logger.Debug("GetCertByIssuerAndSerial: Getting Certificate...", "issuer", issuer, "serial", serial)
+
+	serialNumber, err := NormalizeSerialNumber(serial)
+	if err != nil {
+		return nil, err
+	}
+
+	issuerDN, err := ParseDistinguishedName(issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	// the serial number only collides between issuers
+	var certList Certificates
+	result := certBackend.db.Preload(clause.Associations).Where("serial_number = ?", serialNumber).Find(&certList)
 	if result.Error != nil {
-		return nil, errors.New("Cert not found " + sha256)
+		return nil, result.Error
 	}
 
-	// check if result is empty
-	if result.RowsAffected == 0 {
-		return nil, &DBObjectNotFound{ID: sha256}
+	for _, cert := range certList {
+		if cert.IssuerDN.EqualFold(&issuerDN) {
+			return cert, nil
+		}
 	}
 
-	return &cert, nil
+	return nil, &DBObjectNotFound{ID: issuer + " " + serialNumber}
 }
 
 // ListCerts returns all certs with their assosicated tags
 func (certBackend *CertBackend) ListCerts() (Certificates, error) {
+	return certBackend.ListCertsWithFilter(CertFilter{})
+}
+
+// ListCertsWithFilter returns the certs matching the filter with their assosicated tags
+func (certBackend *CertBackend) ListCertsWithFilter(filter CertFilter) (Certificates, error) {
+	certBackend.logger.Debug("ListCertsWithFilter: ", "filter", filter)
+
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
 
 	// List of Certificates
 	var certList Certificates
 
-	result := certBackend.db.Preload(clause.Associations).Find(&certList)
+	result := filter.apply(certBackend.db.Preload(clause.Associations)).Find(&certList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return certList, nil
+}
+
+// ListCertsPage returns a page of the certs matching the filter with their assosicated tags and
+// labels (without their PEM) and the total number of certs matching the filter
+func (certBackend *CertBackend) ListCertsPage(filter CertFilter, opts ListOptions) (Certificates, int64, error) {
+	certBackend.logger.Debug("ListCertsPage: ", "filter", filter, "opts", opts)
+
+	err := filter.Validate()
+	if err == nil {
+		err = opts.validate(certSortKeys)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	result := filter.apply(certBackend.db.Model(&Certificate{})).Count(&total)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var certList Certificates
+	query := filter.apply(certBackend.listCertsQuery())
+	result = opts.apply(query, certSortKeys, "certificates", "created_at").Find(&certList)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return certList, total, nil
+}
+
+// listCertsQuery return the query of the certs of a list: the certs without their PEM
+// with their tags and labels (the other associations are returned by GetCertByID)
+func (certBackend *CertBackend) listCertsQuery() *gorm.DB {
+	return certBackend.db.Omit("raw_pem").Preload("Tags").Preload("Labels")
+}
+
+// ListCertsByExpiry returns the certs matching the filter ordered by NotAfter
+// (the first to expire first) with their assosicated tags
+func (certBackend *CertBackend) ListCertsByExpiry(filter CertFilter) (Certificates, error) {
+	certBackend.logger.Debug("ListCertsByExpiry: ", "filter", filter)
+
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var certList Certificates
+	result := filter.apply(certBackend.db.Preload(clause.Associations)).Order("certificates.not_after").Find(&certList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return certList, nil
+}
+
+// ListCertsExpiry returns all certs with only the fields of their expiry (id, Subject CN, Issuer,
+// serial number, validity and successor) and the id and name of their tags
+func (certBackend *CertBackend) ListCertsExpiry() (Certificates, error) {
+	certBackend.logger.Debug("ListCertsExpiry: ")
+
+	var certList Certificates
+	result := certBackend.db.
+		Select("id", "subject_common_name", "issuer", "serial_number", "not_before", "not_after", "successor_id").
+		Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Select("tags.id", "tags.name")
+		}).
+		Find(&certList)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -150,6 +438,7 @@ func (certBackend *CertBackend) SetCertTagsNameByID(uuid uuid.UUID, tagList []st
 	if cerr != nil {
 		return nil, cerr
 	}
+	before := NewAuditCertState(cert)
 
 	// getting exitsing  tags
 	updatedTags := cert.Tags
@@ -158,7 +447,7 @@ func (certBackend *CertBackend) SetCertTagsNameByID(uuid uuid.UUID, tagList []st
 		tag, err := certBackend.getTagByNameWithoutPreload(t)
 		if err != nil {
 			// if one tag does not exist abort update
-			return nil, err
+			return nil, &DBObjectNotFound{Err: err, ID: t}
 		}
 
 		certBackend.logger.Debug("SetCertTagsNameByID: go tag", "tagName", t, "tag", tag)
@@ -169,10 +458,17 @@ func (certBackend *CertBackend) SetCertTagsNameByID(uuid uuid.UUID, tagList []st
 		}
 	}
 
-	// update the cert with tag new set of tags
-	tagErr := certBackend.db.Model(&cert).Association("Tags").Replace(updatedTags)
-	if tagErr != nil {
-		return nil, errors.New("error updating cert " + uuid.String() + " with tags " + strings.Join(tagList, ","))
+	err := certBackend.auditTransaction(func(tx *gorm.DB) error {
+		// update the cert with tag new set of tags
+		tagErr := tx.Model(&cert).Association("Tags").Replace(updatedTags)
+		if tagErr != nil {
+			return errors.New("error updating cert " + uuid.String() + " with tags " + strings.Join(tagList, ","))
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionSetTags, AuditObjectCertificate, cert.ID, before, NewAuditCertState(cert))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return cert, nil
@@ -187,6 +483,7 @@ func (certBackend *CertBackend) DeleteCertificateTagsByID(uuid uuid.UUID, tagLis
 	if cerr != nil {
 		return nil, cerr
 	}
+	before := NewAuditCertState(cert)
 
 	// lookup  tags from list
 	var resolvedTags []Tag
@@ -195,7 +492,7 @@ func (certBackend *CertBackend) DeleteCertificateTagsByID(uuid uuid.UUID, tagLis
 		tag, err := certBackend.getTagByNameWithoutPreload(t)
 		if err != nil {
 			// if one tag does not exist abort update
-			return nil, err
+			return nil, &DBObjectNotFound{Err: err, ID: t}
 		}
 
 		resolvedTags = append(resolvedTags, *tag)
@@ -216,57 +513,185 @@ func (certBackend *CertBackend) DeleteCertificateTagsByID(uuid uuid.UUID, tagLis
 
 	// update the cert with tag new set of tags
 	// tagErr := certBackend.db.Model(&cert).Association("Tags").Replace(updatedTags)
-	tagErr := certBackend.db.Model(&cert).Association("Tags").Delete(resolvedTags)
-	if tagErr != nil {
-		return nil, errors.New("error updating cert " + uuid.String() + " with tags " + strings.Join(tagList, ","))
+	err := certBackend.auditTransaction(func(tx *gorm.DB) error {
+		tagErr := tx.Model(&cert).Association("Tags").Delete(resolvedTags)
+		if tagErr != nil {
+			return errors.New("error updating cert " + uuid.String() + " with tags " + strings.Join(tagList, ","))
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionRemoveTags, AuditObjectCertificate, cert.ID, before, NewAuditCertState(cert))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return cert, nil
 }
 
 // DeleteCertByID delete the certificate with uuid
-// This is only doing a soft delete (update the DeleteAt field)
+// This is only doing a soft delete (update the DeletedAt field), the cert
+// is moved to the trash and can be restored until it is purged
 func (certBackend *CertBackend) DeleteCertByID(uuid uuid.UUID) error {
 	certBackend.logger.Debug("DeleteCertByID: Deleting cert", "uuid", uuid)
 
-	result := certBackend.db.Delete(&Certificate{}, uuid)
-	if result.Error != nil {
-		return result.Error
+	cert, err := certBackend.GetCertByID(uuid)
+	if err != nil {
+		return err
 	}
 
-	// check if result is empty
-	if result.RowsAffected == 0 {
-		return &DBObjectNotFound{ID: uuid.String()}
-	}
+	return certBackend.auditTransaction(func(tx *gorm.DB) error {
 
-	return nil
+		result := tx.Delete(&Certificate{}, uuid)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// check if result is empty
+		if result.RowsAffected == 0 {
+			return &DBObjectNotFound{ID: uuid.String()}
+		}
+
+		err := certBackend.withTx(tx).recordAudit(AuditActionDelete, AuditObjectCertificate, uuid, NewAuditCertState(cert), nil)
+		if err != nil {
+			return err
+		}
+
+		// the certs issued by this cert no longer have a known issuer
+		result = tx.Model(&Certificate{}).Where("issuer_id = ?", uuid).Update("issuer_id", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// the cert in the trash is no longer part of the renewals
+		result = tx.Unscoped().Model(&Certificate{}).Where("id = ?", uuid).
+			Updates(map[string]interface{}{"predecessor_id": nil, "successor_id": nil})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return certBackend.withTx(tx).resolveCertRenewals(cert)
+	})
 }
 
 // DeleteCertPendingRecords deletes all Certificates that where flagged for deleting
 func (certBackend *CertBackend) DeleteCertPendingRecords() error {
 	certBackend.logger.Debug("DeleteCertPendingRecords: Deletings cert")
 
+	_, err := certBackend.purgeCerts(certBackend.db.Unscoped().Where("deleted_at IS NOT NULL"))
+	return err
+}
+
+// purgeCerts permanently deletes the Certificates found by query
+// with their associated records, return the number of purged certs
+func (certBackend *CertBackend) purgeCerts(query *gorm.DB) (int, error) {
+
 	// lookup all unscoped
 	var unscopedCertificates Certificates
 
-	// lookup the entries with a deleted_at not null
-	result := certBackend.db.Unscoped().Where("deleted_at IS NOT NULL").Find(&unscopedCertificates)
+	// lookup the entries to purge
+	result := query.Preload("Tags").Find(&unscopedCertificates)
 	if result.Error != nil {
-		return result.Error
+		return 0, result.Error
 	}
 
 	// Iterate over unscoped certificates
-	// and delete them permanently
+	// and delete them permanently, each cert with its event
 	for _, c := range unscopedCertificates {
-		certBackend.logger.Debug("DeleteCertPendingRecords: Deletings cert", "cert", c)
+		certBackend.logger.Debug("purgeCerts: Deletings cert", "cert", c)
+
+		err := certBackend.auditTransaction(func(tx *gorm.DB) error {
+
+			// clearing all associated Tags with that cert
+			err := tx.Model(c).Association("Tags").Clear()
+			if err != nil {
+				return err
+			}
+
+			// the SANs, labels, lint findings and notification deliveries are only stored for the cert
+			for _, model := range []interface{}{&SubjectAltName{}, &Label{}, &LintFinding{}, &NotificationDelivery{}} {
+				result := tx.Where("certificate_id = ?", c.ID).Delete(model)
+				if result.Error != nil {
+					return result.Error
+				}
+			}
+
+			// the certs in the trash issued by this cert no longer have a known issuer
+			result := tx.Unscoped().Model(&Certificate{}).Where("issuer_id = ?", c.ID).Update("issuer_id", nil)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			// nor a link to this cert as renewal
+			for _, column := range []string{"predecessor_id", "successor_id"} {
+				result = tx.Unscoped().Model(&Certificate{}).Where(column+" = ?", c.ID).Update(column, nil)
+				if result.Error != nil {
+					return result.Error
+				}
+			}
+
+			result = tx.Unscoped().Delete(c)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			return certBackend.withTx(tx).recordAudit(AuditActionPurge, AuditObjectCertificate, c.ID, NewAuditCertState(c), nil)
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
 
-		// clearing all associated Tags with that cert
-		certBackend.db.Model(&c).Association("Tags").Clear()
+	return len(unscopedCertificates), nil
+}
+
+// RefreshCertificates recompute the fields extracted from the RawPEM of the certs
+// parsed by an older version of NewCertificateFromX509
+func (certBackend *CertBackend) RefreshCertificates() error {
+	certBackend.logger.Debug("RefreshCertificates: Refreshing certs...")
+
+	var certList Certificates
+	result := certBackend.db.Where("parser_version < ?", CertificateParserVersion).Find(&certList)
+	if result.Error != nil {
+		return result.Error
+	}
 
-		result := certBackend.db.Unscoped().Delete(&c)
+	for _, c := range certList {
+		x509Cert, err := c.ToX509()
+		if err != nil {
+			certBackend.logger.Error("RefreshCertificates: invalid PEM", "id", c.ID, "err", err)
+			continue
+		}
+
+		// keep the fields not extracted from the PEM, the
+		// empty LintVersion lints the refreshed cert again
+		refreshed := NewCertificateFromX509(x509Cert)
+		refreshed.ID = c.ID
+		refreshed.IssuerID = c.IssuerID
+		refreshed.PredecessorID = c.PredecessorID
+		refreshed.SuccessorID = c.SuccessorID
+		refreshed.CreatedAt = c.CreatedAt
+
+		result := certBackend.db.Omit(clause.Associations).Save(refreshed)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// replace the SANs
+		result = certBackend.db.Where("certificate_id = ?", c.ID).Delete(&SubjectAltName{})
 		if result.Error != nil {
 			return result.Error
 		}
+
+		for i := range refreshed.SubjectAltNames {
+			refreshed.SubjectAltNames[i].CertificateID = c.ID
+		}
+
+		if len(refreshed.SubjectAltNames) != 0 {
+			result = certBackend.db.Create(&refreshed.SubjectAltNames)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,271 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CertFilter filters for listing certificates
+// empty fields are ignored
+type CertFilter struct {
+	// the Subject to match, '*' matches any sequence of characters
+	Subject string
+	// the Issuer to match, '*' matches any sequence of characters
+	Issuer string
+	// the signature algorithm (e.g. SHA256-RSA)
+	SignatureAlgorithm string
+	// the type of the SAN to match: dns, ip, email or uri (any type if empty)
+	SANType string
+	// the value of the SAN to match, '*' matches any sequence of characters
+	SAN string
+	// the public key algorithm (RSA, ECDSA, Ed25519, DSA)
+	PublicKeyAlgorithm string
+	// the public key size in bits
+	PublicKeySize int
+	// the base64 sha256 of the SubjectPublicKeyInfo (certs sharing a key)
+	SPKISHA256 string
+	// the Key Usages the cert must all have (e.g. digitalSignature)
+	KeyUsages []string
+	// the Extended Key Usages the cert must all have (e.g. serverAuth or an OID)
+	ExtKeyUsages []string
+	// if the cert is a CA
+	IsCA *bool
+	// the path length constraint of the CA
+	MaxPathLen *int
+	// if the cert has name constraints
+	HasNameConstraints *bool
+	// the Subject attributes to match, '*' matches any sequence of characters
+	SubjectDN DistinguishedName
+	// the Issuer attributes to match, '*' matches any sequence of characters
+	IssuerDN DistinguishedName
+	// the certs valid from this time or after
+	IssuedAfter *time.Time
+	// the certs valid from this time or before
+	IssuedBefore *time.Time
+	// the certs expiring at or after this time
+	ExpiresAfter *time.Time
+	// the certs expiring at or before this time
+	ExpiresBefore *time.Time
+	// the name of a tag of the cert
+	Tag string
+	// also match the certs tagged with a descendant of Tag (e.g. payments/prod/eu for payments)
+	TagDescendants bool
+	// the id of the issuer of the cert
+	IssuerID *uuid.UUID
+	// if the cert was replaced by a renewal
+	Replaced *bool
+	// the label selector the labels of the cert must match (e.g. env=prod,tier!=db,owner in (alice,bob))
+	LabelSelector string
+}
+
+// Validate return an error if the filter is not valid
+func (filter *CertFilter) Validate() error {
+
+	switch filter.SANType {
+	case "", SANTypeDNS, SANTypeIP, SANTypeEmail, SANTypeURI:
+	default:
+		return &DBObjectValidationError{Msg: fmt.Sprintf("invalid SAN type '%s'", filter.SANType)}
+	}
+
+	if filter.PublicKeySize < 0 {
+		return &DBObjectValidationError{Msg: fmt.Sprintf("invalid public key size '%d'", filter.PublicKeySize)}
+	}
+
+	for _, ku := range filter.KeyUsages {
+		if !IsKeyUsageName(ku) {
+			return &DBObjectValidationError{Msg: fmt.Sprintf("invalid key usage '%s'", ku)}
+		}
+	}
+
+	for _, eku := range filter.ExtKeyUsages {
+		if !IsExtKeyUsageName(eku) {
+			return &DBObjectValidationError{Msg: fmt.Sprintf("invalid extended key usage '%s'", eku)}
+		}
+	}
+
+	if filter.ExpiresAfter != nil && filter.ExpiresBefore != nil && filter.ExpiresBefore.Before(*filter.ExpiresAfter) {
+		return &DBObjectValidationError{Msg: "the expiry window ends before it starts"}
+	}
+
+	if filter.IssuedAfter != nil && filter.IssuedBefore != nil && filter.IssuedBefore.Before(*filter.IssuedAfter) {
+		return &DBObjectValidationError{Msg: "the issuance window ends before it starts"}
+	}
+
+	if filter.MaxPathLen != nil && *filter.MaxPathLen < 0 {
+		return &DBObjectValidationError{Msg: fmt.Sprintf("invalid max path len '%d'", *filter.MaxPathLen)}
+	}
+
+	if filter.LabelSelector != "" {
+		_, err := parseLabelSelector(filter.LabelSelector)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// apply add the conditions of the filter to the certificates query
+func (filter *CertFilter) apply(query *gorm.DB) *gorm.DB {
+
+	if filter.Subject != "" {
+		query = query.Where("certificates.subject LIKE ? ESCAPE '\\'", toLikePattern(filter.Subject))
+	}
+
+	if filter.Issuer != "" {
+		query = query.Where("certificates.issuer LIKE ? ESCAPE '\\'", toLikePattern(filter.Issuer))
+	}
+
+	if filter.SignatureAlgorithm != "" {
+		query = query.Where("certificates.signature_algorithm = ?", filter.SignatureAlgorithm)
+	}
+
+	if filter.SAN != "" || filter.SANType != "" {
+		sanQuery := "SELECT certificate_id FROM subject_alt_names WHERE 1 = 1"
+		var sanArgs []interface{}
+
+		if filter.SANType != "" {
+			sanQuery += " AND type = ?"
+			sanArgs = append(sanArgs, filter.SANType)
+		}
+
+		if filter.SAN != "" {
+			sanQuery += " AND value LIKE ? ESCAPE '\\'"
+			sanArgs = append(sanArgs, toLikePattern(strings.ToLower(filter.SAN)))
+		}
+
+		query = query.Where("certificates.id IN ("+sanQuery+")", sanArgs...)
+	}
+
+	if filter.PublicKeyAlgorithm != "" {
+		query = query.Where("certificates.public_key_algorithm = ?", filter.PublicKeyAlgorithm)
+	}
+
+	if filter.PublicKeySize != 0 {
+		query = query.Where("certificates.public_key_size = ?", filter.PublicKeySize)
+	}
+
+	if filter.SPKISHA256 != "" {
+		query = query.Where("certificates.spki_sha256 = ?", filter.SPKISHA256)
+	}
+
+	for _, ku := range filter.KeyUsages {
+		query = query.Where(listContainsClause("certificates.key_usage"), listContainsPattern(ku))
+	}
+
+	for _, eku := range filter.ExtKeyUsages {
+		query = query.Where(listContainsClause("certificates.ext_key_usage"), listContainsPattern(eku))
+	}
+
+	if filter.IsCA != nil {
+		query = query.Where("certificates.is_ca = ?", *filter.IsCA)
+	}
+
+	if filter.MaxPathLen != nil {
+		query = query.Where("certificates.max_path_len = ?", *filter.MaxPathLen)
+	}
+
+	if filter.HasNameConstraints != nil {
+		if *filter.HasNameConstraints {
+			query = query.Where("(certificates.permitted_name_constraints <> '' OR certificates.excluded_name_constraints <> '')")
+		} else {
+			query = query.Where("certificates.permitted_name_constraints = '' AND certificates.excluded_name_constraints = ''")
+		}
+	}
+
+	query = filter.SubjectDN.apply(query, "subject_")
+	query = filter.IssuerDN.apply(query, "issuer_")
+
+	// the validity of the certs is stored in UTC
+	if filter.IssuedAfter != nil {
+		query = query.Where("certificates.not_before >= ?", filter.IssuedAfter.UTC())
+	}
+
+	if filter.IssuedBefore != nil {
+		query = query.Where("certificates.not_before <= ?", filter.IssuedBefore.UTC())
+	}
+
+	if filter.ExpiresAfter != nil {
+		query = query.Where("certificates.not_after >= ?", filter.ExpiresAfter.UTC())
+	}
+
+	if filter.ExpiresBefore != nil {
+		query = query.Where("certificates.not_after <= ?", filter.ExpiresBefore.UTC())
+	}
+
+	if filter.Tag != "" {
+		clause, args := tagCertsCondition(filter.Tag, filter.TagDescendants)
+		query = query.Where("certificates.id IN ("+clause+")", args...)
+	}
+
+	if filter.IssuerID != nil {
+		query = query.Where("certificates.issuer_id = ?", *filter.IssuerID)
+	}
+
+	if filter.LabelSelector != "" {
+		// the selector is checked by Validate
+		requirements, _ := parseLabelSelector(filter.LabelSelector)
+		for _, requirement := range requirements {
+			query = requirement.apply(query)
+		}
+	}
+
+	if filter.Replaced != nil {
+		if *filter.Replaced {
+			query = query.Where("certificates.successor_id IS NOT NULL")
+		} else {
+			query = query.Where("certificates.successor_id IS NULL")
+		}
+	}
+
+	return query
+}
+
+// certSortKeys the keys to sort the certificates
+var certSortKeys = sortKeys{
+	"id":                   "certificates.id",
+	"sha256":               "certificates.sha256",
+	"subject":              "certificates.subject",
+	"issuer":               "certificates.issuer",
+	"serial_number":        "certificates.serial_number",
+	"not_before":           "certificates.not_before",
+	"not_after":            "certificates.not_after",
+	"signature_algorithm":  "certificates.signature_algorithm",
+	"public_key_algorithm": "certificates.public_key_algorithm",
+	"public_key_size":      "certificates.public_key_size",
+	"created_at":           "certificates.created_at",
+}
+
+// tagCertsCondition return the query of the ids of the certs tagged with the tag name (or alias)
+// or any of its descendants
+func tagCertsCondition(name string, descendants bool) (string, []interface{}) {
+
+	query := "SELECT tags_ref.certificate_id FROM tags_ref JOIN tags ON tags.id = tags_ref.tag_id WHERE tags.deleted_at IS NULL AND "
+	if descendants {
+		return query + "(tags.name = ? OR " + tagAliasClause + " OR " + tagDescendantsClause + ")", []interface{}{name, name, name, name}
+	}
+
+	return query + "(tags.name = ? OR " + tagAliasClause + ")", []interface{}{name, name}
+}
+
+// toLikePattern convert a pattern where '*' matches any sequence of characters
+// into a SQL LIKE pattern (escaping the LIKE special characters)
+func toLikePattern(pattern string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `*`, `%`)
+	return replacer.Replace(pattern)
+}
+
+// listContainsClause return the condition matching a '|' separated list column
+// containing the value of listContainsPattern
+func listContainsClause(column string) string {
+	return "('|' || " + column + " || '|') LIKE ? ESCAPE '\\'"
+}
+
+// listContainsPattern return the LIKE pattern matching value as an element of a '|' separated list
+func listContainsPattern(value string) string {
+	return "%|" + toLikePattern(value) + "|%"
+}
@@ -0,0 +1,166 @@
+package data
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"go.mozilla.org/pkcs7"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// CertificateFormat the encoding of certificates submitted for import
+type CertificateFormat string
+
+const (
+	// CertificateFormatAuto detect the format from the content
+	CertificateFormatAuto CertificateFormat = ""
+	// CertificateFormatPEM one or more PEM blocks
+	CertificateFormatPEM CertificateFormat = "pem"
+	// CertificateFormatDER a single DER encoded certificate (.cer)
+	CertificateFormatDER CertificateFormat = "der"
+	// CertificateFormatPKCS7 a DER encoded PKCS#7 SignedData (.p7b)
+	CertificateFormatPKCS7 CertificateFormat = "pkcs7"
+	// CertificateFormatPKCS12 a DER encoded PKCS#12 (.pfx) protected by a password
+	CertificateFormatPKCS12 CertificateFormat = "pkcs12"
+)
+
+const (
+	pemCertificateType = "CERTIFICATE"
+	pemPKCS7Type       = "PKCS7"
+	pemPrivateKeyType  = "PRIVATE KEY"
+)
+
+// pfx the outer structure of a PKCS#12 file, only used for detection
+type pfx struct {
+	Version  int
+	AuthSafe asn1.RawValue
+	MacData  asn1.RawValue `asn1:"optional"`
+}
+
+// DetectCertificateFormat return the format of the raw input
+// or CertificateFormatAuto if the format is unknown
+func DetectCertificateFormat(raw []byte) CertificateFormat {
+
+	if bytes.Contains(raw, []byte("-----BEGIN ")) {
+		return CertificateFormatPEM
+	}
+
+	if _, err := x509.ParseCertificate(raw); err == nil {
+		return CertificateFormatDER
+	}
+
+	if _, err := pkcs7.Parse(raw); err == nil {
+		return CertificateFormatPKCS7
+	}
+
+	var p pfx
+	if rest, err := asn1.Unmarshal(raw, &p); err == nil && len(rest) == 0 && p.Version == 3 {
+		return CertificateFormatPKCS12
+	}
+
+	return CertificateFormatAuto
+}
+
+// DecodeCertificateBlocks decode the raw input into a list of PEM blocks
+// PKCS#7 and PKCS#12 containers are expanded into one CERTIFICATE block per certificate
+// private keys are returned as empty PRIVATE KEY blocks: they can be reported but are never kept
+func DecodeCertificateBlocks(raw []byte, format CertificateFormat, password string) ([]*pem.Block, error) {
+
+	if format == CertificateFormatAuto {
+		format = DetectCertificateFormat(raw)
+	}
+
+	switch format {
+	case CertificateFormatPEM:
+		var blocks []*pem.Block
+		for _, block := range DecodePEMBlocks(string(raw)) {
+			if block.Type == pemPKCS7Type {
+				certBlocks, err := decodePKCS7Blocks(block.Bytes)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, certBlocks...)
+				continue
+			}
+
+			if isPrivateKeyBlock(block) {
+				block = &pem.Block{Type: block.Type}
+			}
+			blocks = append(blocks, block)
+		}
+
+		if len(blocks) == 0 {
+			return nil, fmt.Errorf("no PEM block found")
+		}
+		return blocks, nil
+
+	case CertificateFormatDER:
+		return []*pem.Block{{Type: pemCertificateType, Bytes: raw}}, nil
+
+	case CertificateFormatPKCS7:
+		return decodePKCS7Blocks(raw)
+
+	case CertificateFormatPKCS12:
+		return decodePKCS12Blocks(raw, password)
+	}
+
+	return nil, fmt.Errorf("unknown certificate format '%s'", format)
+}
+
+// decodePKCS7Blocks return the certificates of a PKCS#7 SignedData
+func decodePKCS7Blocks(raw []byte) ([]*pem.Block, error) {
+
+	p7, err := pkcs7.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PKCS#7: %s", err.Error())
+	}
+
+	return x509CertsToBlocks(p7.Certificates), nil
+}
+
+// decodePKCS12Blocks return the certificates of a PKCS#12 file
+// the private key (if any) is replaced by an empty PRIVATE KEY block
+func decodePKCS12Blocks(raw []byte, password string) ([]*pem.Block, error) {
+
+	// a PKCS#12 with a private key (key + leaf + chain)
+	key, leaf, caCerts, err := pkcs12.DecodeChain(raw, password)
+	if err == nil {
+		blocks := x509CertsToBlocks(append([]*x509.Certificate{leaf}, caCerts...))
+		if key != nil {
+			blocks = append(blocks, &pem.Block{Type: pemPrivateKeyType})
+		}
+		return blocks, nil
+	}
+
+	if err == pkcs12.ErrIncorrectPassword {
+		return nil, fmt.Errorf("invalid PKCS#12: %s", err.Error())
+	}
+
+	// a PKCS#12 trust store (certificates only)
+	certs, tsErr := pkcs12.DecodeTrustStore(raw, password)
+	if tsErr != nil {
+		return nil, fmt.Errorf("invalid PKCS#12: %s", err.Error())
+	}
+
+	return x509CertsToBlocks(certs), nil
+}
+
+// x509CertsToBlocks convert certificates into CERTIFICATE PEM blocks
+func x509CertsToBlocks(certs []*x509.Certificate) []*pem.Block {
+
+	blocks := make([]*pem.Block, 0, len(certs))
+	for _, c := range certs {
+		blocks = append(blocks, &pem.Block{Type: pemCertificateType, Bytes: c.Raw})
+	}
+
+	return blocks
+}
+
+// isPrivateKeyBlock return true if the PEM block holds a private key
+func isPrivateKeyBlock(block *pem.Block) bool {
+	return strings.HasSuffix(block.Type, pemPrivateKeyType)
+}
@@ -1,12 +1,22 @@
 package data
 
 import (
+	"bytes"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"math/big"
+	"net"
+	"regexp"
 	"strings"
 )
 
@@ -26,6 +36,28 @@ func ParseX509FromPEM(rawpem string) (*x509.Certificate, error) {
 	return cert, nil
 }
 
+// DecodePEMBlocks return all the PEM blocks found in the input string
+// any non PEM data between blocks is ignored
+func DecodePEMBlocks(rawpem string) []*pem.Block {
+
+	var blocks []*pem.Block
+	rest := []byte(rawpem)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return blocks
+		}
+
+		blocks = append(blocks, block)
+	}
+}
+
+// GetPEMFromX509Cert return the PEM encoding of the X509 Cert
+func GetPEMFromX509Cert(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
 // GetSHA256FingerprintFromX509Cert compute the sha256 fingerprint of X509 Cert
 func GetSHA256FingerprintFromX509Cert(cert *x509.Certificate) string {
 
@@ -34,6 +66,22 @@ func GetSHA256FingerprintFromX509Cert(cert *x509.Certificate) string {
 
 }
 
+// GetSHA1FingerprintFromX509Cert return hex sha1 Fingerprint of X509 Cert
+func GetSHA1FingerprintFromX509Cert(cert *x509.Certificate) string {
+
+	fingerprint := sha1.Sum(cert.Raw)
+	return fmt.Sprintf("%x", fingerprint)
+
+}
+
+// GetMD5FingerprintFromX509Cert return hex md5 Fingerprint of X509 Cert
+func GetMD5FingerprintFromX509Cert(cert *x509.Certificate) string {
+
+	fingerprint := md5.Sum(cert.Raw)
+	return fmt.Sprintf("%x", fingerprint)
+
+}
+
 // GetSubjectFromX509Cert return X509 Cert Subject as string
 func GetSubjectFromX509Cert(cert *x509.Certificate) string {
 	return cert.Subject.String()
@@ -54,6 +102,44 @@ func GetSignatureAlgorithmFromX509Cert(cert *x509.Certificate) string {
 	return cert.SignatureAlgorithm.String()
 }
 
+// GetPublicKeyAlgorithmFromX509Cert return X509 Cert public key algorithm as string
+func GetPublicKeyAlgorithmFromX509Cert(cert *x509.Certificate) string {
+	return cert.PublicKeyAlgorithm.String()
+}
+
+// GetPublicKeySizeFromX509Cert return the size in bits of the X509 Cert public key
+// (modulus size for RSA and DSA, curve size for ECDSA)
+func GetPublicKeySizeFromX509Cert(cert *x509.Certificate) int {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return key.Curve.Params().BitSize
+	case *dsa.PublicKey:
+		return key.P.BitLen()
+	case ed25519.PublicKey:
+		return 256
+	}
+
+	return 0
+}
+
+// GetPublicKeyCurveFromX509Cert return the curve name of a X509 Cert ECDSA public key
+func GetPublicKeyCurveFromX509Cert(cert *x509.Certificate) string {
+	if key, ok := cert.PublicKey.(*ecdsa.PublicKey); ok {
+		return key.Curve.Params().Name
+	}
+
+	return ""
+}
+
+// GetSPKISHA256FromX509Cert return the base64 sha256 of the X509 Cert SubjectPublicKeyInfo
+// (the HPKP pin-sha256 value)
+func GetSPKISHA256FromX509Cert(cert *x509.Certificate) string {
+	spki := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(spki[:])
+}
+
 // GetAKIFromX509Cert return X509 Cert Authority Key ID as string
 func GetAKIFromX509Cert(cert *x509.Certificate) string {
 	return formatKeyID(cert.AuthorityKeyId)
@@ -76,6 +162,30 @@ func GetSNASFromX509Cert(cert *x509.Certificate) string {
 	return strings.Join(sans, "|")
 }
 
+// GetSubjectAltNamesFromX509Cert return X509 Cert Subject Alternative Names with their type
+// the DNS names are lower case without the trailing dot of a fully qualified name
+func GetSubjectAltNamesFromX509Cert(cert *x509.Certificate) []SubjectAltName {
+
+	sans := []SubjectAltName{}
+	for _, dns := range cert.DNSNames {
+		sans = append(sans, SubjectAltName{Type: SANTypeDNS, Value: strings.TrimSuffix(strings.ToLower(dns), ".")})
+	}
+
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, SubjectAltName{Type: SANTypeIP, Value: ip.String()})
+	}
+
+	for _, email := range cert.EmailAddresses {
+		sans = append(sans, SubjectAltName{Type: SANTypeEmail, Value: email})
+	}
+
+	for _, uri := range cert.URIs {
+		sans = append(sans, SubjectAltName{Type: SANTypeURI, Value: uri.String()})
+	}
+
+	return sans
+}
+
 // GetOCSPFromX509Cert return X509 OCSP Server as list of  string
 func GetOCSPFromX509Cert(cert *x509.Certificate) string {
 	return strings.Join(cert.OCSPServer, "|")
@@ -86,6 +196,158 @@ func GetIsCAFromX509Cert(cert *x509.Certificate) bool {
 	return cert.IsCA
 }
 
+// keyUsageNames the names of the KeyUsage bits in bit order (RFC 5280)
+var keyUsageNames = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "digitalSignature"},
+	{x509.KeyUsageContentCommitment, "contentCommitment"},
+	{x509.KeyUsageKeyEncipherment, "keyEncipherment"},
+	{x509.KeyUsageDataEncipherment, "dataEncipherment"},
+	{x509.KeyUsageKeyAgreement, "keyAgreement"},
+	{x509.KeyUsageCertSign, "keyCertSign"},
+	{x509.KeyUsageCRLSign, "cRLSign"},
+	{x509.KeyUsageEncipherOnly, "encipherOnly"},
+	{x509.KeyUsageDecipherOnly, "decipherOnly"},
+}
+
+// extKeyUsageNames the names of the known Extended Key Usages
+var extKeyUsageNames = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                            "any",
+	x509.ExtKeyUsageServerAuth:                     "serverAuth",
+	x509.ExtKeyUsageClientAuth:                     "clientAuth",
+	x509.ExtKeyUsageCodeSigning:                    "codeSigning",
+	x509.ExtKeyUsageEmailProtection:                "emailProtection",
+	x509.ExtKeyUsageIPSECEndSystem:                 "ipsecEndSystem",
+	x509.ExtKeyUsageIPSECTunnel:                    "ipsecTunnel",
+	x509.ExtKeyUsageIPSECUser:                      "ipsecUser",
+	x509.ExtKeyUsageTimeStamping:                   "timeStamping",
+	x509.ExtKeyUsageOCSPSigning:                    "OCSPSigning",
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto:     "msSGC",
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:      "nsSGC",
+	x509.ExtKeyUsageMicrosoftCommercialCodeSigning: "msCodeCom",
+	x509.ExtKeyUsageMicrosoftKernelCodeSigning:     "msKernelCodeSigning",
+}
+
+// oidPattern matches a dotted OID
+var oidPattern = regexp.MustCompile(`^[0-2](\.[0-9]+)+$`)
+
+// IsKeyUsageName return true if name is the name of a KeyUsage bit
+func IsKeyUsageName(name string) bool {
+	for _, ku := range keyUsageNames {
+		if ku.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsExtKeyUsageName return true if name is the name of a known Extended Key Usage or an OID
+func IsExtKeyUsageName(name string) bool {
+	for _, eku := range extKeyUsageNames {
+		if eku == name {
+			return true
+		}
+	}
+
+	return oidPattern.MatchString(name)
+}
+
+// GetKeyUsageFromX509Cert return X509 Cert KeyUsage bits as list of string
+func GetKeyUsageFromX509Cert(cert *x509.Certificate) string {
+
+	var usages []string
+	for _, ku := range keyUsageNames {
+		if cert.KeyUsage&ku.usage != 0 {
+			usages = append(usages, ku.name)
+		}
+	}
+
+	return strings.Join(usages, "|")
+}
+
+// GetExtKeyUsageFromX509Cert return X509 Cert Extended Key Usages as list of string
+// unknown usages are returned as their OID
+func GetExtKeyUsageFromX509Cert(cert *x509.Certificate) string {
+
+	var usages []string
+	for _, eku := range cert.ExtKeyUsage {
+		name, ok := extKeyUsageNames[eku]
+		if !ok {
+			name = fmt.Sprintf("unknown(%d)", eku)
+		}
+		usages = append(usages, name)
+	}
+
+	for _, oid := range cert.UnknownExtKeyUsage {
+		usages = append(usages, oid.String())
+	}
+
+	return strings.Join(usages, "|")
+}
+
+// GetMaxPathLenFromX509Cert return X509 Cert basic constraints path length
+// nil if the cert is not a CA or has no path length constraint
+func GetMaxPathLenFromX509Cert(cert *x509.Certificate) *int {
+
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return nil
+	}
+
+	if cert.MaxPathLen > 0 || (cert.MaxPathLen == 0 && cert.MaxPathLenZero) {
+		maxPathLen := cert.MaxPathLen
+		return &maxPathLen
+	}
+
+	return nil
+}
+
+// GetPermittedNameConstraintsFromX509Cert return X509 Cert permitted subtrees as list of string
+// each subtree is prefixed by its type (dns:, ip:, email:, uri:)
+func GetPermittedNameConstraintsFromX509Cert(cert *x509.Certificate) string {
+	return formatNameConstraints(cert.PermittedDNSDomains, cert.PermittedIPRanges, cert.PermittedEmailAddresses, cert.PermittedURIDomains)
+}
+
+// GetExcludedNameConstraintsFromX509Cert return X509 Cert excluded subtrees as list of string
+// each subtree is prefixed by its type (dns:, ip:, email:, uri:)
+func GetExcludedNameConstraintsFromX509Cert(cert *x509.Certificate) string {
+	return formatNameConstraints(cert.ExcludedDNSDomains, cert.ExcludedIPRanges, cert.ExcludedEmailAddresses, cert.ExcludedURIDomains)
+}
+
+// formatNameConstraints join name constraints subtrees prefixed by their type
+func formatNameConstraints(dns []string, ips []*net.IPNet, emails []string, uris []string) string {
+
+	var subtrees []string
+	for _, d := range dns {
+		subtrees = append(subtrees, SANTypeDNS+":"+d)
+	}
+
+	for _, ip := range ips {
+		subtrees = append(subtrees, SANTypeIP+":"+ip.String())
+	}
+
+	for _, e := range emails {
+		subtrees = append(subtrees, SANTypeEmail+":"+e)
+	}
+
+	for _, u := range uris {
+		subtrees = append(subtrees, SANTypeURI+":"+u)
+	}
+
+	return strings.Join(subtrees, "|")
+}
+
+// GetIsSelfSignedFromX509Cert return if the x509 cert is signed by its own key
+func GetIsSelfSignedFromX509Cert(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		return false
+	}
+
+	return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
 // GetCRLFromX509Cert return X509 CRL Server as list of  string
 func GetCRLFromX509Cert(cert *x509.Certificate) string {
 	return strings.Join(cert.CRLDistributionPoints, "|")
@@ -110,6 +372,12 @@ func formatKeyID(id []byte) string {
 	return s
 }
 
+// toHexInt return the lower case hex of n with an even number of digits (as its DER bytes)
 func toHexInt(n *big.Int) string {
-	return fmt.Sprintf("%x", n) // or %X or upper case
+	h := fmt.Sprintf("%x", n)
+	if n.Sign() >= 0 && len(h)%2 != 0 {
+		h = "0" + h
+	}
+
+	return h
 }
@@ -0,0 +1,122 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChainOptions options for building and validating a certificate chain
+type ChainOptions struct {
+	// the time at which the chain is validated (now if zero)
+	Time time.Time
+	// the hostname the leaf must be valid for (not checked if empty)
+	Hostname string
+	// only the certificates with this tag are trust anchors
+	// (all stored self-signed certificates if empty)
+	TrustAnchorTag string
+}
+
+// ChainHop a certificate of a chain
+// swagger:model
+type ChainHop struct {
+	// the id of the certificate
+	//
+	// required: false
+	ID uuid.UUID `json:"id"`
+	// the sha256 of the certificate
+	//
+	// required: false
+	SHA256 string `json:"sha256"`
+	// the Subject of the certificate
+	//
+	// required: false
+	Subject string `json:"subject"`
+	// the Issuer of the certificate
+	//
+	// required: false
+	Issuer string `json:"issuer"`
+	// the Not Before validity of the certificate
+	//
+	// required: false
+	NotBefore time.Time `json:"not_before"`
+	// the Not After validity of the certificate
+	//
+	// required: false
+	NotAfter time.Time `json:"not_after"`
+	// if the certificate is self-signed
+	//
+	// required: false
+	IsSelfSigned bool `json:"is_self_signed"`
+	// if the certificate is one of the trust anchors
+	//
+	// required: false
+	IsTrustAnchor bool `json:"is_trust_anchor"`
+}
+
+// MissingIssuer describes the issuer of the last hop when it is not stored
+// swagger:model
+type MissingIssuer struct {
+	// the Subject of the missing issuer
+	//
+	// required: false
+	Subject string `json:"subject"`
+	// the Subject Key ID of the missing issuer (Authority Key ID of the last hop)
+	//
+	// required: false
+	SKI string `json:"subject_key_id,omitempty"`
+	// the Issuing CA URLs of the last hop where the issuer may be downloaded
+	//
+	// required: false
+	IssuingCAUrl string `json:"issuing_ca_url,omitempty"`
+}
+
+// CertificateChain the path from a certificate up to a root
+// swagger:model
+type CertificateChain struct {
+	// the certificates of the chain, starting with the certificate itself
+	//
+	// required: false
+	Hops []*ChainHop `json:"hops"`
+	// if the chain ends with a self-signed root
+	//
+	// required: false
+	Complete bool `json:"complete"`
+	// the issuer not found in the DB when the chain is not complete
+	//
+	// required: false
+	MissingIssuer *MissingIssuer `json:"missing_issuer,omitempty"`
+	// if the chain validates against the trust anchors
+	//
+	// required: false
+	Valid bool `json:"valid"`
+	// the reason why the chain does not validate
+	//
+	// required: false
+	Error string `json:"error,omitempty"`
+	// the time at which the chain was validated
+	//
+	// required: false
+	VerifiedAt time.Time `json:"verified_at"`
+	// the hostname the chain was validated for
+	//
+	// required: false
+	Hostname string `json:"hostname,omitempty"`
+	// the tag of the trust anchors the chain was validated against
+	//
+	// required: false
+	TrustAnchorTag string `json:"trust_anchor_tag,omitempty"`
+}
+
+// NewChainHop create a ChainHop from a Certificate
+func NewChainHop(cert *Certificate) *ChainHop {
+	return &ChainHop{
+		ID:           cert.ID,
+		SHA256:       cert.SHA256,
+		Subject:      cert.Subject,
+		Issuer:       cert.Issuer,
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		IsSelfSigned: cert.IsSelfSigned,
+	}
+}
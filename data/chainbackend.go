@@ -0,0 +1,191 @@
+package data
+
+import (
+	"crypto/x509"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//
+// Chain functions
+// Read: BuildCertChain, getTrustAnchors
+//
+
+// maxChainLength the max number of hops followed when walking the issuer links
+const maxChainLength = 16
+
+// BuildCertChain build the best path from the cert (uuid) up to a root with the stored certs
+// and validate it at opts.Time for opts.Hostname against the trust anchors
+func (certBackend *CertBackend) BuildCertChain(uuid uuid.UUID, opts ChainOptions) (*CertificateChain, error) {
+	certBackend.logger.Debug("BuildCertChain: Building chain...", "uuid", uuid, "opts", opts)
+
+	cert, err := certBackend.GetCertByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	x509Cert, err := cert.ToX509()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Time.IsZero() {
+		opts.Time = time.Now()
+	}
+
+	anchors, err := certBackend.getTrustAnchors(opts.TrustAnchorTag)
+	if err != nil {
+		return nil, err
+	}
+
+	// all CAs are candidate intermediates
+	var cas Certificates
+	result := certBackend.db.Where("is_ca = ?", true).Find(&cas)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	byFingerprint := map[string]*Certificate{cert.SHA256: cert}
+	isAnchor := map[string]bool{}
+
+	roots := x509.NewCertPool()
+	for _, a := range anchors {
+		x509Anchor, err := a.ToX509()
+		if err != nil {
+			continue
+		}
+		roots.AddCert(x509Anchor)
+		isAnchor[a.SHA256] = true
+		byFingerprint[a.SHA256] = a
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, ca := range cas {
+		if isAnchor[ca.SHA256] {
+			continue
+		}
+		x509CA, err := ca.ToX509()
+		if err != nil {
+			continue
+		}
+		intermediates.AddCert(x509CA)
+		byFingerprint[ca.SHA256] = ca
+	}
+
+	chain := &CertificateChain{
+		Hops:           []*ChainHop{},
+		VerifiedAt:     opts.Time,
+		Hostname:       opts.Hostname,
+		TrustAnchorTag: opts.TrustAnchorTag,
+	}
+
+	chains, verifyErr := x509Cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   opts.Time,
+		DNSName:       opts.Hostname,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if verifyErr != nil {
+		certBackend.logger.Debug("BuildCertChain: chain does not validate", "uuid", uuid, "err", verifyErr)
+
+		// report the path found by following the issuer links
+		chain.Error = verifyErr.Error()
+		certBackend.walkIssuerChain(chain, cert, isAnchor)
+		return chain, nil
+	}
+
+	// the best path is the shortest one
+	best := chains[0]
+	for _, c := range chains {
+		if len(c) < len(best) {
+			best = c
+		}
+	}
+
+	for _, x := range best {
+		c, ok := byFingerprint[GetSHA256FingerprintFromX509Cert(x)]
+		if !ok {
+			// should never happen all certs of the pools are stored
+			c = NewCertificateFromX509(x)
+		}
+
+		hop := NewChainHop(c)
+		hop.IsTrustAnchor = isAnchor[c.SHA256]
+		chain.Hops = append(chain.Hops, hop)
+	}
+
+	chain.Valid = true
+	chain.Complete = chain.Hops[len(chain.Hops)-1].IsSelfSigned
+
+	return chain, nil
+}
+
+// walkIssuerChain add the hops found by following the issuer links from cert
+// and the missing issuer if the walk does not end with a self-signed root
+func (certBackend *CertBackend) walkIssuerChain(chain *CertificateChain, cert *Certificate, isAnchor map[string]bool) {
+
+	seen := map[uuid.UUID]bool{}
+	last := cert
+	for current := cert; len(chain.Hops) < maxChainLength && !seen[current.ID]; {
+		seen[current.ID] = true
+		last = current
+
+		hop := NewChainHop(current)
+		hop.IsTrustAnchor = isAnchor[current.SHA256]
+		chain.Hops = append(chain.Hops, hop)
+
+		if current.IsSelfSigned {
+			chain.Complete = true
+			return
+		}
+
+		if current.IssuerID == nil {
+			break
+		}
+
+		issuer, err := certBackend.GetCertByID(*current.IssuerID)
+		if err != nil {
+			break
+		}
+		current = issuer
+	}
+
+	chain.MissingIssuer = &MissingIssuer{
+		Subject:      last.Issuer,
+		SKI:          last.AKI,
+		IssuingCAUrl: last.IssuingCAUrl,
+	}
+}
+
+// getTrustAnchors return the certs tagged with tagName
+// or all self-signed certs if tagName is empty
+func (certBackend *CertBackend) getTrustAnchors(tagName string) (Certificates, error) {
+
+	var anchors Certificates
+
+	if tagName == "" {
+		result := certBackend.db.Where("is_self_signed = ?", true).Find(&anchors)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		return anchors, nil
+	}
+
+	tag, err := certBackend.getTagByNameWithoutPreload(tagName)
+	if err != nil {
+		return nil, &DBObjectNotFound{Err: err, ID: tagName}
+	}
+
+	result := certBackend.db.
+		Joins("JOIN tags_ref ON tags_ref.certificate_id = certificates.id").
+		Where("tags_ref.tag_id = ?", tag.ID).
+		Find(&anchors)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return anchors, nil
+}
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCertChain(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	report, err := certBakcend.CreateCertificateBundle(testLeafPEM+testIntermediatePEM+testRootPEM, nil)
+	if err != nil || len(report.Created) != 3 {
+		t.Logf("Error creating bundle %v", err)
+		t.FailNow()
+	}
+	leaf, intermediate, root := report.Created[0], report.Created[1], report.Created[2]
+
+	verifyTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	chain, err := certBakcend.BuildCertChain(leaf.ID, ChainOptions{Time: verifyTime, Hostname: "foo.api.example.com"})
+	if err != nil {
+		t.Logf("Error building chain %s", err.Error())
+		t.FailNow()
+	}
+
+	if !chain.Valid || !chain.Complete || len(chain.Hops) != 3 || chain.Hops[2].ID != root.ID || !chain.Hops[2].IsTrustAnchor {
+		t.Logf("Unexpected chain %+v", chain)
+		t.FailNow()
+	}
+
+	// wrong hostname: the path is still reported
+	chain, err = certBakcend.BuildCertChain(leaf.ID, ChainOptions{Time: verifyTime, Hostname: "www.example.org"})
+	if err != nil {
+		t.Logf("Error building chain %s", err.Error())
+		t.FailNow()
+	}
+
+	if chain.Valid || chain.Error == "" || !chain.Complete || len(chain.Hops) != 3 {
+		t.Logf("Unexpected chain %+v", chain)
+		t.FailNow()
+	}
+
+	// expired at verify time
+	chain, _ = certBakcend.BuildCertChain(leaf.ID, ChainOptions{Time: time.Date(2035, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if chain.Valid {
+		t.Logf("Expecting expired chain to be invalid")
+		t.FailNow()
+	}
+
+	// a team trusting the intermediate only
+	_, err = certBakcend.CreateTag("team_anchors")
+	if err != nil {
+		t.Logf("Error creating Tag %s", err.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.SetCertTagNameByID(intermediate.ID, "team_anchors")
+	if err != nil {
+		t.Logf("Error tagging cert %s", err.Error())
+		t.FailNow()
+	}
+
+	chain, err = certBakcend.BuildCertChain(leaf.ID, ChainOptions{Time: verifyTime, TrustAnchorTag: "team_anchors"})
+	if err != nil {
+		t.Logf("Error building chain %s", err.Error())
+		t.FailNow()
+	}
+
+	if !chain.Valid || chain.Complete || len(chain.Hops) != 2 || !chain.Hops[1].IsTrustAnchor {
+		t.Logf("Unexpected chain %+v", chain)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.BuildCertChain(leaf.ID, ChainOptions{TrustAnchorTag: "unknown_tag"})
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting not found error got %v", err)
+		t.FailNow()
+	}
+
+	// missing intermediate
+	err = certBakcend.DeleteCertByID(intermediate.ID)
+	if err != nil {
+		t.Logf("Error deleting cert %s", err.Error())
+		t.FailNow()
+	}
+
+	chain, err = certBakcend.BuildCertChain(leaf.ID, ChainOptions{Time: verifyTime})
+	if err != nil {
+		t.Logf("Error building chain %s", err.Error())
+		t.FailNow()
+	}
+
+	if chain.Valid || chain.Complete || chain.MissingIssuer == nil || chain.MissingIssuer.SKI != leaf.AKI {
+		t.Logf("Unexpected chain %+v", chain)
+		t.FailNow()
+	}
+}
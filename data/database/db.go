@@ -2,6 +2,7 @@ package database
 
 import (
 	"log"
+	"time"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -15,7 +16,10 @@ type DB struct {
 // NewSqliteDB create a sqlite db connection
 func NewSqliteDB(dbpath string) *gorm.DB {
 
-	db, err := gorm.Open(sqlite.Open(dbpath), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(dbpath), &gorm.Config{
+		// sqlite compares times as strings, store all timestamps in UTC
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -6,14 +6,53 @@ import (
 	"gorm.io/gorm"
 )
 
-// DBMigration initialize DB
-func DBMigration(db *gorm.DB, logger hclog.Logger) error {
+// DBMigration initialize DB and backfill the certificates with certBackend
+// (the backend of the server with its custom lint rules)
+func DBMigration(db *gorm.DB, certBackend *data.CertBackend, logger hclog.Logger) error {
 
-	err := db.AutoMigrate(&data.Tag{}, &data.Certificate{})
+	err := db.AutoMigrate(data.Models()...)
 	if err != nil {
 		logger.Error("Error Running DB Migration", "error", err)
 		return err
 	}
 
+	// backfill data of existing certificates
+	err = certBackend.RefreshCertificates()
+	if err != nil {
+		logger.Error("Error Refreshing Certificates", "error", err)
+		return err
+	}
+
+	err = certBackend.ResolveCertIssuers()
+	if err != nil {
+		logger.Error("Error Resolving Certificate Issuers", "error", err)
+		return err
+	}
+
+	err = certBackend.ResolveCertRenewals()
+	if err != nil {
+		logger.Error("Error Resolving Certificate Renewals", "error", err)
+		return err
+	}
+
+	err = certBackend.ResolveTagHierarchy()
+	if err != nil {
+		logger.Error("Error Resolving Tag Hierarchy", "error", err)
+		return err
+	}
+
+	// only the certs linted by another version of the rules
+	_, err = certBackend.LintOutdatedCertificates()
+	if err != nil {
+		logger.Error("Error Linting Certificates", "error", err)
+		return err
+	}
+
+	err = certBackend.ChainAuditEvents()
+	if err != nil {
+		logger.Error("Error Chaining Audit Events", "error", err)
+		return err
+	}
+
 	return nil
 }
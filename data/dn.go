@@ -0,0 +1,293 @@
+package data
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// oids of the distinguished name attributes stored in their own field
+var (
+	oidCommonName         = asn1.ObjectIdentifier{2, 5, 4, 3}
+	oidSerialNumber       = asn1.ObjectIdentifier{2, 5, 4, 5}
+	oidCountry            = asn1.ObjectIdentifier{2, 5, 4, 6}
+	oidLocality           = asn1.ObjectIdentifier{2, 5, 4, 7}
+	oidProvince           = asn1.ObjectIdentifier{2, 5, 4, 8}
+	oidOrganization       = asn1.ObjectIdentifier{2, 5, 4, 10}
+	oidOrganizationalUnit = asn1.ObjectIdentifier{2, 5, 4, 11}
+)
+
+// dnAttributeOIDs the oids of the attribute names accepted by ParseDistinguishedName
+var dnAttributeOIDs = map[string]string{
+	"CN":           "2.5.4.3",
+	"SERIALNUMBER": "2.5.4.5",
+	"C":            "2.5.4.6",
+	"L":            "2.5.4.7",
+	"ST":           "2.5.4.8",
+	"S":            "2.5.4.8",
+	"STREET":       "2.5.4.9",
+	"O":            "2.5.4.10",
+	"OU":           "2.5.4.11",
+	"POSTALCODE":   "2.5.4.17",
+	"DC":           "0.9.2342.19200300.100.1.25",
+	"UID":          "0.9.2342.19200300.100.1.1",
+	"EMAILADDRESS": "1.2.840.113549.1.9.1",
+	"E":            "1.2.840.113549.1.9.1",
+}
+
+// DistinguishedName defines the parsed attributes of a X509 Subject or Issuer
+// multi-valued attributes are '|' separated
+// swagger:model
+type DistinguishedName struct {
+	// the Common Name (CN)
+	//
+	// required: false
+	CommonName string `json:"cn,omitempty"`
+	// the Organization (O)
+	//
+	// required: false
+	Organization string `json:"o,omitempty"`
+	// the Organizational Unit (OU)
+	//
+	// required: false
+	OrganizationalUnit string `json:"ou,omitempty"`
+	// the Country (C)
+	//
+	// required: false
+	Country string `json:"c,omitempty"`
+	// the Locality (L)
+	//
+	// required: false
+	Locality string `json:"l,omitempty"`
+	// the State or Province (ST)
+	//
+	// required: false
+	Province string `json:"st,omitempty"`
+	// the serialNumber attribute (not the serial number of the cert)
+	//
+	// required: false
+	SerialNumber string `json:"serial_number,omitempty"`
+	// the other attributes as oid=value (e.g. 2.5.4.17=1000)
+	//
+	// required: false
+	ExtraNames string `json:"extra_names,omitempty"`
+}
+
+// NewDistinguishedName create a DistinguishedName from a X509 Name
+func NewDistinguishedName(name pkix.Name) DistinguishedName {
+
+	var extraNames []string
+	for _, atv := range name.Names {
+		switch {
+		case atv.Type.Equal(oidCommonName), atv.Type.Equal(oidSerialNumber),
+			atv.Type.Equal(oidCountry), atv.Type.Equal(oidLocality), atv.Type.Equal(oidProvince),
+			atv.Type.Equal(oidOrganization), atv.Type.Equal(oidOrganizationalUnit):
+			continue
+		}
+		extraNames = append(extraNames, fmt.Sprintf("%s=%v", atv.Type.String(), atv.Value))
+	}
+
+	return DistinguishedName{
+		CommonName:         name.CommonName,
+		Organization:       strings.Join(name.Organization, "|"),
+		OrganizationalUnit: strings.Join(name.OrganizationalUnit, "|"),
+		Country:            strings.Join(name.Country, "|"),
+		Locality:           strings.Join(name.Locality, "|"),
+		Province:           strings.Join(name.Province, "|"),
+		SerialNumber:       name.SerialNumber,
+		ExtraNames:         strings.Join(extraNames, "|"),
+	}
+}
+
+// apply add the conditions matching the non empty attributes of dn (as patterns where '*'
+// matches any sequence of characters) to the query on the columns prefixed by prefix
+func (dn *DistinguishedName) apply(query *gorm.DB, prefix string) *gorm.DB {
+
+	attributes := []struct {
+		column  string
+		pattern string
+	}{
+		{"common_name", dn.CommonName},
+		{"organization", dn.Organization},
+		{"organizational_unit", dn.OrganizationalUnit},
+		{"country", dn.Country},
+		{"locality", dn.Locality},
+		{"province", dn.Province},
+		{"serial_number", dn.SerialNumber},
+		{"extra_names", dn.ExtraNames},
+	}
+
+	for _, a := range attributes {
+		if a.pattern != "" {
+			query = query.Where(listContainsClause("certificates."+prefix+a.column), listContainsPattern(a.pattern))
+		}
+	}
+
+	return query
+}
+
+// ParseDistinguishedName parse a DN written as RFC 4514 (e.g. CN=Example CA,O=Example,C=BE
+// in any order) or as OpenSSL oneline (e.g. /C=BE/O=Example/CN=Example CA)
+func ParseDistinguishedName(s string) (DistinguishedName, error) {
+
+	var dn DistinguishedName
+
+	s = strings.TrimSpace(s)
+	separators := ",+;"
+	if strings.HasPrefix(s, "/") {
+		s = s[1:]
+		separators = "/"
+	}
+
+	var attributes [][]string
+	for _, rdn := range splitEscaped(s, separators) {
+		kv := strings.SplitN(rdn, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return dn, &DBObjectValidationError{Msg: fmt.Sprintf("invalid distinguished name attribute '%s'", rdn)}
+		}
+
+		oid := strings.TrimSpace(kv[0])
+		if known, ok := dnAttributeOIDs[strings.ToUpper(oid)]; ok {
+			oid = known
+		}
+
+		value, err := unescapeDNValue(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return dn, &DBObjectValidationError{Err: err, Msg: fmt.Sprintf("invalid distinguished name value '%s'", kv[1])}
+		}
+
+		attributes = append(attributes, []string{oid, value})
+	}
+
+	var extraNames []string
+	for _, a := range attributes {
+		oid, value := a[0], a[1]
+		switch oid {
+		case oidCommonName.String():
+			dn.CommonName = value
+		case oidSerialNumber.String():
+			dn.SerialNumber = value
+		case oidCountry.String():
+			dn.Country = appendListValue(dn.Country, value)
+		case oidLocality.String():
+			dn.Locality = appendListValue(dn.Locality, value)
+		case oidProvince.String():
+			dn.Province = appendListValue(dn.Province, value)
+		case oidOrganization.String():
+			dn.Organization = appendListValue(dn.Organization, value)
+		case oidOrganizationalUnit.String():
+			dn.OrganizationalUnit = appendListValue(dn.OrganizationalUnit, value)
+		default:
+			extraNames = append(extraNames, oid+"="+value)
+		}
+	}
+	dn.ExtraNames = strings.Join(extraNames, "|")
+
+	return dn, nil
+}
+
+// EqualFold return true if both DNs have the same attributes, ignoring the order of the
+// values, the case and the repeated spaces (as the comparison of RFC 5280 section 7.1)
+func (dn *DistinguishedName) EqualFold(other *DistinguishedName) bool {
+
+	pairs := [][2]string{
+		{dn.CommonName, other.CommonName},
+		{dn.Organization, other.Organization},
+		{dn.OrganizationalUnit, other.OrganizationalUnit},
+		{dn.Country, other.Country},
+		{dn.Locality, other.Locality},
+		{dn.Province, other.Province},
+		{dn.SerialNumber, other.SerialNumber},
+		{dn.ExtraNames, other.ExtraNames},
+	}
+
+	for _, p := range pairs {
+		if normalizeDNList(p[0]) != normalizeDNList(p[1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeDNList return the '|' separated list sorted, lower case and with single spaces
+func normalizeDNList(list string) string {
+
+	values := strings.Split(list, "|")
+	for i, v := range values {
+		values[i] = strings.ToLower(strings.Join(strings.Fields(v), " "))
+	}
+	sort.Strings(values)
+
+	return strings.Join(values, "|")
+}
+
+// appendListValue append value to the '|' separated list
+func appendListValue(list string, value string) string {
+	if list == "" {
+		return value
+	}
+
+	return list + "|" + value
+}
+
+// splitEscaped split s on the separators not escaped by a '\'
+func splitEscaped(s string, separators string) []string {
+
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+
+		if strings.IndexByte(separators, s[i]) >= 0 {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// unescapeDNValue return the value of a RFC 4514 attribute, '#' prefixes the hex DER of the value
+func unescapeDNValue(value string) (string, error) {
+
+	if strings.HasPrefix(value, "#") {
+		raw, err := hex.DecodeString(value[1:])
+		if err != nil {
+			return "", err
+		}
+
+		var decoded string
+		_, err = asn1.Unmarshal(raw, &decoded)
+		return decoded, err
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+
+		i++
+		// \XX is the hex code of a byte
+		if i+1 < len(value) {
+			if h, err := hex.DecodeString(value[i : i+2]); err == nil {
+				b.Write(h)
+				i++
+				continue
+			}
+		}
+		b.WriteByte(value[i])
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,103 @@
+package data
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+)
+
+func TestNewDistinguishedName(t *testing.T) {
+
+	cert, err := ParseX509FromPEM(testLeafPEM)
+	if err != nil {
+		t.Logf("Error parsing PEM %s", err.Error())
+		t.FailNow()
+	}
+
+	subject := NewDistinguishedName(cert.Subject)
+	expect := DistinguishedName{
+		CommonName:         "www.example.com",
+		Organization:       "Acme",
+		OrganizationalUnit: "Web",
+		Country:            "BE",
+		Locality:           "Brussels",
+		Province:           "Brussels",
+	}
+	if subject != expect {
+		t.Logf("Expecting '%+v', but got '%+v'", expect, subject)
+		t.FailNow()
+	}
+
+	issuer := NewDistinguishedName(cert.Issuer)
+	if issuer.CommonName != "Acme Test Issuing CA" || issuer.OrganizationalUnit != "PKI" {
+		t.Logf("Unexpected issuer '%+v'", issuer)
+		t.FailNow()
+	}
+
+	// multi-valued and extra attributes
+	name := pkix.Name{}
+	name.FillFromRDNSequence(&pkix.RDNSequence{
+		{{Type: oidOrganizationalUnit, Value: "Web"}},
+		{{Type: oidOrganizationalUnit, Value: "Ops"}},
+		{{Type: asn1.ObjectIdentifier{2, 5, 4, 17}, Value: "1000"}},
+		{{Type: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}, Value: "pki@example.com"}},
+	})
+
+	dn := NewDistinguishedName(name)
+	if dn.OrganizationalUnit != "Web|Ops" {
+		t.Logf("Expecting 'Web|Ops', but got '%s'", dn.OrganizationalUnit)
+		t.FailNow()
+	}
+
+	if dn.ExtraNames != "2.5.4.17=1000|1.2.840.113549.1.9.1=pki@example.com" {
+		t.Logf("Unexpected extra names '%s'", dn.ExtraNames)
+		t.FailNow()
+	}
+}
+
+func TestParseDistinguishedName(t *testing.T) {
+
+	cert, err := ParseX509FromPEM(testLeafPEM)
+	if err != nil {
+		t.Logf("Error parsing PEM %s", err.Error())
+		t.FailNow()
+	}
+	subject := NewDistinguishedName(cert.Subject)
+
+	for _, s := range []string{
+		cert.Subject.String(),
+		"/C=BE/ST=Brussels/L=Brussels/O=Acme/OU=Web/CN=www.example.com",
+		"cn = WWW.example.com, ou=Web, o=Acme, l=Brussels, st=Brussels, c=BE",
+	} {
+		dn, err := ParseDistinguishedName(s)
+		if err != nil {
+			t.Logf("Error parsing '%s' %s", s, err.Error())
+			t.FailNow()
+		}
+
+		if !dn.EqualFold(&subject) {
+			t.Logf("Expecting '%s' to match '%+v', but got '%+v'", s, subject, dn)
+			t.FailNow()
+		}
+	}
+
+	// escaped values and extra attributes in their hex form
+	name := pkix.Name{}
+	name.FillFromRDNSequence(&pkix.RDNSequence{
+		{{Type: oidOrganization, Value: "Acme, Inc."}},
+		{{Type: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}, Value: "pki@example.com"}},
+	})
+	expect := NewDistinguishedName(name)
+
+	dn, err := ParseDistinguishedName(name.String())
+	if err != nil || !dn.EqualFold(&expect) {
+		t.Logf("Expecting '%s' to match '%+v', but got '%+v' %v", name.String(), expect, dn, err)
+		t.FailNow()
+	}
+
+	_, err = ParseDistinguishedName("CN=Acme,Acme")
+	if _, ok := err.(*DBObjectValidationError); !ok {
+		t.Logf("Expecting validation error got %v", err)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,63 @@
+package data
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FingerprintAlgorithm the hash algorithm of a certificate fingerprint
+type FingerprintAlgorithm string
+
+const (
+	// FingerprintSHA256 sha256 of the DER certificate
+	FingerprintSHA256 FingerprintAlgorithm = "sha256"
+	// FingerprintSHA1 sha1 of the DER certificate (e.g. Windows thumbprint)
+	FingerprintSHA1 FingerprintAlgorithm = "sha1"
+	// FingerprintMD5 md5 of the DER certificate (legacy inventories)
+	FingerprintMD5 FingerprintAlgorithm = "md5"
+)
+
+// column return the Certificate column storing the fingerprint of algorithm
+func (algorithm FingerprintAlgorithm) column() (string, error) {
+
+	switch algorithm {
+	case FingerprintSHA256, FingerprintSHA1, FingerprintMD5:
+		return string(algorithm), nil
+	}
+
+	return "", &DBObjectValidationError{Msg: fmt.Sprintf("invalid fingerprint algorithm '%s'", algorithm)}
+}
+
+// DetectFingerprintAlgorithm return the algorithm of the hex fingerprint from its length
+func DetectFingerprintAlgorithm(fingerprint string) (FingerprintAlgorithm, error) {
+
+	_, err := hex.DecodeString(fingerprint)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(fingerprint) {
+	case 64:
+		return FingerprintSHA256, nil
+	case 40:
+		return FingerprintSHA1, nil
+	case 32:
+		return FingerprintMD5, nil
+	}
+
+	return "", fmt.Errorf("length must be 64 (sha256), 40 (sha1) or 32 (md5) found %d", len(fingerprint))
+}
+
+// NormalizeSerialNumber return the hex serial number in the format of Certificate.SerialNumber
+// (lower case, without ':' and with an even number of digits)
+func NormalizeSerialNumber(serial string) (string, error) {
+
+	n, ok := new(big.Int).SetString(strings.ToLower(strings.ReplaceAll(serial, ":", "")), 16)
+	if !ok || n.Sign() < 0 {
+		return "", &DBObjectValidationError{Msg: fmt.Sprintf("invalid hex serial number '%s'", serial)}
+	}
+
+	return toHexInt(n), nil
+}
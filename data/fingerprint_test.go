@@ -0,0 +1,55 @@
+package data
+
+import "testing"
+
+func TestDetectFingerprintAlgorithm(t *testing.T) {
+
+	tests := map[string]FingerprintAlgorithm{
+		"ca42dd41745fd0b81eb902362cf9d8bf719da1bd1b1efc946f5b4c99f42c1b9e": FingerprintSHA256,
+		"4cb6e36269f2263457c84d6e9506a7aefe824090":                         FingerprintSHA1,
+		"2d11a9a37e06e964fab13b4b8a352a56":                                 FingerprintMD5,
+	}
+
+	for fingerprint, expect := range tests {
+		algorithm, err := DetectFingerprintAlgorithm(fingerprint)
+		if err != nil || algorithm != expect {
+			t.Logf("Expecting '%s', but got '%s' (%v)", expect, algorithm, err)
+			t.FailNow()
+		}
+	}
+
+	for _, fingerprint := range []string{"", "4cb6e36269f2263457c84d6e9506a7aefe8240", "zz11a9a37e06e964fab13b4b8a352a56"} {
+		_, err := DetectFingerprintAlgorithm(fingerprint)
+		if err == nil {
+			t.Logf("Expecting error for '%s'", fingerprint)
+			t.FailNow()
+		}
+	}
+}
+
+func TestNormalizeSerialNumber(t *testing.T) {
+
+	tests := map[string]string{
+		"0BBB":     "0bbb",
+		"bbb":      "0bbb",
+		"0b:bb":    "0bbb",
+		"000bbb":   "0bbb",
+		"04:00:00": "040000",
+	}
+
+	for serial, expect := range tests {
+		normalized, err := NormalizeSerialNumber(serial)
+		if err != nil || normalized != expect {
+			t.Logf("Expecting '%s', but got '%s' (%v)", expect, normalized, err)
+			t.FailNow()
+		}
+	}
+
+	for _, serial := range []string{"", "xyz", "-bbb"} {
+		_, err := NormalizeSerialNumber(serial)
+		if _, ok := err.(*DBObjectValidationError); !ok {
+			t.Logf("Expecting validation error for '%s' got %v", serial, err)
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package data
+
+// Test PKI used across the tests
+// testRootPEM (RSA 2048, self-signed)
+//  -> testIntermediatePEM (ECDSA P-256, name constraints .example.com)
+//      -> testLeafPEM (RSA 2048, SANs dns/ip/email/uri, serverAuth+clientAuth)
+//      -> testRenewedLeafPEM (renewal of testLeafPEM with the same key, valid 2031-05-01 to 2041-01-01)
+//
+// testChainPKCS7PEM the leaf, intermediate and root as a PEM encoded PKCS#7 (.p7b)
+// testLeafPKCS12Base64 the leaf with its private key and the intermediate as PKCS#12 (.pfx)
+// protected by testPKCS12Password
+
+const testRootPEM = `
+-----BEGIN CERTIFICATE-----
+MIIDMTCCAhmgAwIBAgICA+kwDQYJKoZIhvcNAQELBQAwODELMAkGA1UEBhMCQkUx
+DTALBgNVBAoTBEFjbWUxGjAYBgNVBAMTEUFjbWUgVGVzdCBSb290IENBMB4XDTIx
+MDEwMTAwMDAwMFoXDTQxMDEwMTAwMDAwMFowODELMAkGA1UEBhMCQkUxDTALBgNV
+BAoTBEFjbWUxGjAYBgNVBAMTEUFjbWUgVGVzdCBSb290IENBMIIBIjANBgkqhkiG
+9w0BAQEFAAOCAQ8AMIIBCgKCAQEAt+oMc4rIlbSy+F36I4ry8nmwGcb/TGc8LPX2
+ZEnvA4DH9LKqfNZMXFKypPu+JW7BhORdBNPXgmiAgvn3WNlq7tupjP6RiRBBhbQo
+nwmd7npY6ClyClcgrcPIZk2GrCvwr/fax13JbYL79KrTa1E9AOyt/iYLAcI00ilA
+15WpDmfKisbxHzrDF/Q/NHKPBdXHdADNpnalSRlox8xvH3uEHDXmm/UuY2dZYJVF
+3hL2X72oIO98rMEamDcpSOCXe7T/mnaLnOZ2o7ZRlg0GQMZ76/g9qACfSCfubCyv
+24Jl7eKd7yTdh9ct8RXdIE8F+ML6S6vld/lWTIVLIb7phyHu6QIDAQABo0UwQzAO
+BgNVHQ8BAf8EBAMCAQYwEgYDVR0TAQH/BAgwBgEB/wIBATAdBgNVHQ4EFgQUJk4+
+oCSqV4muS3QM5llNlXDQJrowDQYJKoZIhvcNAQELBQADggEBAADXANvCuq557kyP
+kBEOkJ+fNcw7HNcPQCZ/jiSRtrn6UFfl2SKG0DEKmDYYejw4ZmAgspjeBN9wcgSb
+DOKjf7EEeWEwF+5kIQQhkxzumIlm9XdvlN0aZWVQEiK7VmGDGwGv4g1CciNfktnM
+4otyDfF+esclL3J7zz9B2EtQmCmnPqfvMrnvttQKMRzt+X/ztI00Dddrsdy4SSxL
+IImj4Am3F6UbMBpxVuFkB1BrPVddAmelF5iM8OuQT9lqOOPAwITfs5EDCXAkUxOu
+N65T9Z5aZYeGtuCGLhIi6jgaK4yonts/sg8urXWpAW7s4xMGXxMou4/JtQbOQLKy
+muel7W0=
+-----END CERTIFICATE-----`
+
+const testIntermediatePEM = `
+-----BEGIN CERTIFICATE-----
+MIIDWzCCAkOgAwIBAgICB9IwDQYJKoZIhvcNAQELBQAwODELMAkGA1UEBhMCQkUx
+DTALBgNVBAoTBEFjbWUxGjAYBgNVBAMTEUFjbWUgVGVzdCBSb290IENBMB4XDTIx
+MDEwMTAwMDAwMFoXDTM2MDEwMTAwMDAwMFowSTELMAkGA1UEBhMCQkUxDTALBgNV
+BAoTBEFjbWUxDDAKBgNVBAsTA1BLSTEdMBsGA1UEAxMUQWNtZSBUZXN0IElzc3Vp
+bmcgQ0EwWTATBgcqhkjOPQIBBggqhkjOPQMBBwNCAASrFBMS5xAiBROqz8c5NQia
+U+BvuHfLuIWp1ToiXJNPmV1SsKeoi7xiabMRu8Z/mrwklzBPiBYLQ1pGeQOgmcz6
+o4IBJzCCASMwDgYDVR0PAQH/BAQDAgEGMBIGA1UdEwEB/wQIMAYBAf8CAQAwHQYD
+VR0OBBYEFIpHPj6QR37ZZaB6dgfM3KFBpUUYMB8GA1UdIwQYMBaAFCZOPqAkqleJ
+rkt0DOZZTZVw0Ca6MF8GCCsGAQUFBwEBBFMwUTAjBggrBgEFBQcwAYYXaHR0cDov
+L29jc3AuZXhhbXBsZS5jb20wKgYIKwYBBQUHMAKGHmh0dHA6Ly9jYS5leGFtcGxl
+LmNvbS9yb290LmNydDAqBgNVHR4EIzAhoB8wDoIMLmV4YW1wbGUuY29tMA2CC2V4
+YW1wbGUuY29tMDAGA1UdHwQpMCcwJaAjoCGGH2h0dHA6Ly9jcmwuZXhhbXBsZS5j
+b20vcm9vdC5jcmwwDQYJKoZIhvcNAQELBQADggEBAFmwYgF1dL5j8iRVqqWYxiTq
+CTSRkNyQBjF1Rn7LJq4goGDXhYymjJKNZko8vPmZtGWHP+1Ayvf2adOdeoQMkA0g
+I6eaekDDVoGyuf3jo18f8apMxUgxpaPjDibghH5N032F/9KeOi6p/kxjKzJbQ+iC
+9KVfTSJ0kYtYqmPUxQ3BMgx62XNX+VI28qYFOPBTcVq9NFB9avO9aAs7qCBx2Fmh
+MocI1oAJcl++wPLtHpsu0i0AOomzju9ZgXwAFn1KBhcn5HFxhhoeAvSY2IzZNYgQ
+vQFTuWo5Gdzrypyra/A8V9nJJH06BJ+HDRjUHicT9UpiypcjpjUP02T4aSTY+R4=
+-----END CERTIFICATE-----`
+
+const testLeafPEM = `
+-----BEGIN CERTIFICATE-----
+MIIDaDCCAw2gAwIBAgICC7swCgYIKoZIzj0EAwIwSTELMAkGA1UEBhMCQkUxDTAL
+BgNVBAoTBEFjbWUxDDAKBgNVBAsTA1BLSTEdMBsGA1UEAxMUQWNtZSBUZXN0IElz
+c3VpbmcgQ0EwHhcNMjEwNjAxMDAwMDAwWhcNMzEwNjAxMDAwMDAwWjBqMQswCQYD
+VQQGEwJCRTERMA8GA1UECBMIQnJ1c3NlbHMxETAPBgNVBAcTCEJydXNzZWxzMQ0w
+CwYDVQQKEwRBY21lMQwwCgYDVQQLEwNXZWIxGDAWBgNVBAMTD3d3dy5leGFtcGxl
+LmNvbTCCASIwDQYJKoZIhvcNAQEBBQADggEPADCCAQoCggEBAMXfUKfN3CfOi/rY
+bUqQtt0uKJGx1/P1z5uAxBw3XiNBmz2YXw0G9MgZmkguBIiFOpzMbR99AjQGIVqS
+CU3OaaNCU8nlUnV9obwjIrogG3T0L5NTs2/JLg1o8hpK0GxZvXVLKj7VOTVay8F6
+MNupPZ7NaEZQuDupnSgoxL2tA2EekRcyzSn6wCJHLztRFVfipLfbtqulxu7JVkDN
+BuPqdEHbW2fpO+8YMq+FzR4msQsZHFZr6BJ3QThTHJKtkDCpkwhzU36o1MFS3l3D
+s3EPHrdacn1TvSmLSe37PC4XDpqvQ/QPpznpcTWBB4uHhhZM+kFPm9QWt8XPeR84
+aGR/BekCAwEAAaOB+DCB9TAOBgNVHQ8BAf8EBAMCBaAwHQYDVR0lBBYwFAYIKwYB
+BQUHAwEGCCsGAQUFBwMCMAwGA1UdEwEB/wQCMAAwHwYDVR0jBBgwFoAUikc+PpBH
+ftlloHp2B8zcoUGlRRgwMwYIKwYBBQUHAQEEJzAlMCMGCCsGAQUFBzABhhdodHRw
+Oi8vb2NzcC5leGFtcGxlLmNvbTBgBgNVHREEWTBXgg93d3cuZXhhbXBsZS5jb22C
+ESouYXBpLmV4YW1wbGUuY29tgRFhZG1pbkBleGFtcGxlLmNvbYcECgAAAYYYc3Bp
+ZmZlOi8vZXhhbXBsZS5jb20vd2ViMAoGCCqGSM49BAMCA0kAMEYCIQCMqxGx5Bu4
+zCuDJkshkgcpPAphTKrOxkRonv0TMqGXNQIhAIrt3IaPEol4j+1kkN7qBN8U9/FQ
+hlJ7dqQY9fpvjAiK
+-----END CERTIFICATE-----`
+
+const testRenewedLeafPEM = `
+-----BEGIN CERTIFICATE-----
+MIIDaDCCAw2gAwIBAgICC7wwCgYIKoZIzj0EAwIwSTELMAkGA1UEBhMCQkUxDTAL
+BgNVBAoTBEFjbWUxDDAKBgNVBAsTA1BLSTEdMBsGA1UEAxMUQWNtZSBUZXN0IElz
+c3VpbmcgQ0EwHhcNMzEwNTAxMDAwMDAwWhcNNDEwMTAxMDAwMDAwWjBqMQswCQYD
+VQQGEwJCRTERMA8GA1UECBMIQnJ1c3NlbHMxETAPBgNVBAcTCEJydXNzZWxzMQ0w
+CwYDVQQKEwRBY21lMQwwCgYDVQQLEwNXZWIxGDAWBgNVBAMTD3d3dy5leGFtcGxl
+LmNvbTCCASIwDQYJKoZIhvcNAQEBBQADggEPADCCAQoCggEBAMXfUKfN3CfOi/rY
+bUqQtt0uKJGx1/P1z5uAxBw3XiNBmz2YXw0G9MgZmkguBIiFOpzMbR99AjQGIVqS
+CU3OaaNCU8nlUnV9obwjIrogG3T0L5NTs2/JLg1o8hpK0GxZvXVLKj7VOTVay8F6
+MNupPZ7NaEZQuDupnSgoxL2tA2EekRcyzSn6wCJHLztRFVfipLfbtqulxu7JVkDN
+BuPqdEHbW2fpO+8YMq+FzR4msQsZHFZr6BJ3QThTHJKtkDCpkwhzU36o1MFS3l3D
+s3EPHrdacn1TvSmLSe37PC4XDpqvQ/QPpznpcTWBB4uHhhZM+kFPm9QWt8XPeR84
+aGR/BekCAwEAAaOB+DCB9TAOBgNVHQ8BAf8EBAMCBaAwHQYDVR0lBBYwFAYIKwYB
+BQUHAwEGCCsGAQUFBwMCMAwGA1UdEwEB/wQCMAAwHwYDVR0jBBgwFoAUikc+PpBH
+ftlloHp2B8zcoUGlRRgwMwYIKwYBBQUHAQEEJzAlMCMGCCsGAQUFBzABhhdodHRw
+Oi8vb2NzcC5leGFtcGxlLmNvbTBgBgNVHREEWTBXgg93d3cuZXhhbXBsZS5jb22C
+ESouYXBpLmV4YW1wbGUuY29tgRFhZG1pbkBleGFtcGxlLmNvbYcECgAAAYYYc3Bp
+ZmZlOi8vZXhhbXBsZS5jb20vd2ViMAoGCCqGSM49BAMCA0kAMEYCIQCgE+aEvKND
+DUu9QeyHYFJ1B8qiKEaHqcE7TwtOTdBYZgIhAL9rpFQGucOrhoMTPbI0RWgRw62U
+ZYIOuO45a+bKnrC8
+-----END CERTIFICATE-----`
+
+const testChainPKCS7PEM = `
+-----BEGIN PKCS7-----
+MIIKKwYJKoZIhvcNAQcCoIIKHDCCChgCAQExADALBgkqhkiG9w0BBwGgggoAMIID
+aDCCAw2gAwIBAgICC7swCgYIKoZIzj0EAwIwSTELMAkGA1UEBhMCQkUxDTALBgNV
+BAoTBEFjbWUxDDAKBgNVBAsTA1BLSTEdMBsGA1UEAxMUQWNtZSBUZXN0IElzc3Vp
+bmcgQ0EwHhcNMjEwNjAxMDAwMDAwWhcNMzEwNjAxMDAwMDAwWjBqMQswCQYDVQQG
+EwJCRTERMA8GA1UECBMIQnJ1c3NlbHMxETAPBgNVBAcTCEJydXNzZWxzMQ0wCwYD
+VQQKEwRBY21lMQwwCgYDVQQLEwNXZWIxGDAWBgNVBAMTD3d3dy5leGFtcGxlLmNv
+bTCCASIwDQYJKoZIhvcNAQEBBQADggEPADCCAQoCggEBAMXfUKfN3CfOi/rYbUqQ
+tt0uKJGx1/P1z5uAxBw3XiNBmz2YXw0G9MgZmkguBIiFOpzMbR99AjQGIVqSCU3O
+aaNCU8nlUnV9obwjIrogG3T0L5NTs2/JLg1o8hpK0GxZvXVLKj7VOTVay8F6MNup
+PZ7NaEZQuDupnSgoxL2tA2EekRcyzSn6wCJHLztRFVfipLfbtqulxu7JVkDNBuPq
+dEHbW2fpO+8YMq+FzR4msQsZHFZr6BJ3QThTHJKtkDCpkwhzU36o1MFS3l3Ds3EP
+Hrdacn1TvSmLSe37PC4XDpqvQ/QPpznpcTWBB4uHhhZM+kFPm9QWt8XPeR84aGR/
+BekCAwEAAaOB+DCB9TAOBgNVHQ8BAf8EBAMCBaAwHQYDVR0lBBYwFAYIKwYBBQUH
+AwEGCCsGAQUFBwMCMAwGA1UdEwEB/wQCMAAwHwYDVR0jBBgwFoAUikc+PpBHftll
+oHp2B8zcoUGlRRgwMwYIKwYBBQUHAQEEJzAlMCMGCCsGAQUFBzABhhdodHRwOi8v
+b2NzcC5leGFtcGxlLmNvbTBgBgNVHREEWTBXgg93d3cuZXhhbXBsZS5jb22CESou
+YXBpLmV4YW1wbGUuY29tgRFhZG1pbkBleGFtcGxlLmNvbYcECgAAAYYYc3BpZmZl
+Oi8vZXhhbXBsZS5jb20vd2ViMAoGCCqGSM49BAMCA0kAMEYCIQCMqxGx5Bu4zCuD
+JkshkgcpPAphTKrOxkRonv0TMqGXNQIhAIrt3IaPEol4j+1kkN7qBN8U9/FQhlJ7
+dqQY9fpvjAiKMIIDWzCCAkOgAwIBAgICB9IwDQYJKoZIhvcNAQELBQAwODELMAkG
+A1UEBhMCQkUxDTALBgNVBAoTBEFjbWUxGjAYBgNVBAMTEUFjbWUgVGVzdCBSb290
+IENBMB4XDTIxMDEwMTAwMDAwMFoXDTM2MDEwMTAwMDAwMFowSTELMAkGA1UEBhMC
+QkUxDTALBgNVBAoTBEFjbWUxDDAKBgNVBAsTA1BLSTEdMBsGA1UEAxMUQWNtZSBU
+ZXN0IElzc3VpbmcgQ0EwWTATBgcqhkjOPQIBBggqhkjOPQMBBwNCAASrFBMS5xAi
+BROqz8c5NQiaU+BvuHfLuIWp1ToiXJNPmV1SsKeoi7xiabMRu8Z/mrwklzBPiBYL
+Q1pGeQOgmcz6o4IBJzCCASMwDgYDVR0PAQH/BAQDAgEGMBIGA1UdEwEB/wQIMAYB
+Af8CAQAwHQYDVR0OBBYEFIpHPj6QR37ZZaB6dgfM3KFBpUUYMB8GA1UdIwQYMBaA
+FCZOPqAkqleJrkt0DOZZTZVw0Ca6MF8GCCsGAQUFBwEBBFMwUTAjBggrBgEFBQcw
+AYYXaHR0cDovL29jc3AuZXhhbXBsZS5jb20wKgYIKwYBBQUHMAKGHmh0dHA6Ly9j
+YS5leGFtcGxlLmNvbS9yb290LmNydDAqBgNVHR4EIzAhoB8wDoIMLmV4YW1wbGUu
+Y29tMA2CC2V4YW1wbGUuY29tMDAGA1UdHwQpMCcwJaAjoCGGH2h0dHA6Ly9jcmwu
+ZXhhbXBsZS5jb20vcm9vdC5jcmwwDQYJKoZIhvcNAQELBQADggEBAFmwYgF1dL5j
+8iRVqqWYxiTqCTSRkNyQBjF1Rn7LJq4goGDXhYymjJKNZko8vPmZtGWHP+1Ayvf2
+adOdeoQMkA0gI6eaekDDVoGyuf3jo18f8apMxUgxpaPjDibghH5N032F/9KeOi6p
+/kxjKzJbQ+iC9KVfTSJ0kYtYqmPUxQ3BMgx62XNX+VI28qYFOPBTcVq9NFB9avO9
+aAs7qCBx2FmhMocI1oAJcl++wPLtHpsu0i0AOomzju9ZgXwAFn1KBhcn5HFxhhoe
+AvSY2IzZNYgQvQFTuWo5Gdzrypyra/A8V9nJJH06BJ+HDRjUHicT9UpiypcjpjUP
+02T4aSTY+R4wggMxMIICGaADAgECAgID6TANBgkqhkiG9w0BAQsFADA4MQswCQYD
+VQQGEwJCRTENMAsGA1UEChMEQWNtZTEaMBgGA1UEAxMRQWNtZSBUZXN0IFJvb3Qg
+Q0EwHhcNMjEwMTAxMDAwMDAwWhcNNDEwMTAxMDAwMDAwWjA4MQswCQYDVQQGEwJC
+RTENMAsGA1UEChMEQWNtZTEaMBgGA1UEAxMRQWNtZSBUZXN0IFJvb3QgQ0EwggEi
+MA0GCSqGSIb3DQEBAQUAA4IBDwAwggEKAoIBAQC36gxzisiVtLL4XfojivLyebAZ
+xv9MZzws9fZkSe8DgMf0sqp81kxcUrKk+74lbsGE5F0E09eCaICC+fdY2Wru26mM
+/pGJEEGFtCifCZ3ueljoKXIKVyCtw8hmTYasK/Cv99rHXcltgvv0qtNrUT0A7K3+
+JgsBwjTSKUDXlakOZ8qKxvEfOsMX9D80co8F1cd0AM2mdqVJGWjHzG8fe4QcNeab
+9S5jZ1lglUXeEvZfvagg73yswRqYNylI4Jd7tP+adouc5najtlGWDQZAxnvr+D2o
+AJ9IJ+5sLK/bgmXt4p3vJN2H1y3xFd0gTwX4wvpLq+V3+VZMhUshvumHIe7pAgMB
+AAGjRTBDMA4GA1UdDwEB/wQEAwIBBjASBgNVHRMBAf8ECDAGAQH/AgEBMB0GA1Ud
+DgQWBBQmTj6gJKpXia5LdAzmWU2VcNAmujANBgkqhkiG9w0BAQsFAAOCAQEAANcA
+28K6rnnuTI+QEQ6Qn581zDsc1w9AJn+OJJG2ufpQV+XZIobQMQqYNhh6PDhmYCCy
+mN4E33ByBJsM4qN/sQR5YTAX7mQhBCGTHO6YiWb1d2+U3RplZVASIrtWYYMbAa/i
+DUJyI1+S2czii3IN8X56xyUvcnvPP0HYS1CYKac+p+8yue+21AoxHO35f/O0jTQN
+12ux3LhJLEsgiaPgCbcXpRswGnFW4WQHUGs9V10CZ6UXmIzw65BP2Wo448DAhN+z
+kQMJcCRTE643rlP1nlplh4a24IYuEiLqOBorjKie2z+yDy6tdakBbuzjEwZfEyi7
+j8m1Bs5AsrKa56XtbTEA
+-----END PKCS7-----`
+
+const testPKCS12Password = "changeit"
+
+const testLeafPKCS12Base64 = `
+MIINzwIBAzCCDYUGCSqGSIb3DQEHAaCCDXYEgg1yMIINbjCCB+IGCSqGSIb3DQEH
+BqCCB9MwggfPAgEAMIIHyAYJKoZIhvcNAQcBMFcGCSqGSIb3DQEFDTBKMCkGCSqG
+SIb3DQEFDDAcBAgMSgoZ2f8x8gICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQME
+ASoEEA4S3n0XeigGD3vR/SyP5vqAggdgKI6ud8ac2mMmTUpkCCFZB20dYMKjkT8E
+ucTfl1iT2/TWq5YPKuDkLnxvajYdNwGJLfLKpQlwJPWJJ4YQ95LTNnB41By9gd/r
+7Gt1/v/nHgMhCDgcJpzQ8i/doLGGcC4pZeq517cA3743b5ZnCS4HFGPHxSVvKBsa
+DlH2SaF51vSs0OwIj1ne4JYf52erXpSWFmkqBQNX0dKr8yUjYvvYYXp1osV+a9HV
+Oty48dpsgvYVZd5abmf6KayqgMZ+eggOUQdZQUHvvNFMGW/kLCcMSbtdK1+EDg5p
+1E/dB3lx7AQ9gDRoKcvjDpDiM9J/7TXHQMB4hhBadQsSmLt2X2M08NMjfW7T2aLV
+UwFM7bS/VUQ31kJwFFLxB2Gch+XrmWpwi0/kmMggSzL21xJS/fcf0+2INTp4EMOi
+6uQH2ExzAxV77JutMy8TIZ5xbcTvtJUrkRkutQJhybPhgV3Acib0HbRxsBzmq8NT
+sIMgw1wumv0ON6ZlMMwBorgdWemt7YId36dGmdyBUqRzDjXstz1sI6M6YElNe3qg
+Y9P2PsgF8qxyTVrkYxB3dXEFouwCvL0ootvb97g1Fh3kLcoBoV3+o05H67BFA3pR
+RqbZckgj3PeDMYBIGIgkbkKq6qdGQbt6ys1ad0gwK2jgOT+imR+K5yjYOnArlbYc
+N66YyUPQ0QPiU2fh41JchtTKeVF/4RoiyCRP6hmzQkEVSt+53K2L7o30LUVLmA5B
+B4GUzETMGbziR9JaOHsHDuI8wUFNGfN4SFrpdEB817izyA3ERFkZyAewxuSPNrT+
+RPxMqRAxiYZNTdlUSvhBCmxcX1ZphXEGmRg+QyIEOreIyzzllGfcviFdbU2KczJi
+b7Nz9VwFUec0n+AuV+Qtvcv6A4TDbEFNwq4WF+RlDCKjU5lxD4tf6immHflEarUW
+MGZ45mUy7AqpJXqlu2DLv7A7TlkMeYY2O81MH+Q68eczDDVz/iHsOV3RG7shgrPy
+QZ6GTxvn+Bz+sOZNwd8rIIxnIClfgYoURYDvwbZxpE6AJH3QT5brKmO4LWy18RHd
+bOrhsEExmHzVJJMFpOm+n2j78q1pT6cTvt9icSJ9caqSkZqEPzrBUEubnslkiF2l
+6ece6KFBs8Jgfg8eqRCyXLlnwoKahSvBrSpSg6mrgmDn/smddin+6yFmS4bo0H14
+TpNJ+fUDGNHG/sfKmLUCwsYQ9WB0y9qzlpXyjRru4Iw21MMDFnGZ+CYxxWit5yGg
+74mNekgeu4L6CR2pUHRMFDRIld5Yj/ZXQW7ShKISsrPC4T/AsRr7VQECkyLFHpWZ
+0qs/8BQ++wjbuVIeUHIRxtOVarA+7MAGBLcLlNGfJPG5SNHu995a4HDRLor5sf3B
+dOeQtXBojiQszqnga+QmGHCGKIfzbIczbhm18qeaiDzv7VUxK95vscCJcfKbwMov
+AKFv9KtrwhsO1G5akTKb7bNVvXCRDGgOsUJlcsfxK1NEx2Rq/odUzzFM4CBfbhn1
+YulSOOdBCfIj9rLTxU9NRADXxdAqU92hdRKIct6f8xtPKOOEq/U0muum73p7dmjA
+UxfmaMhW7i4B6FOSzOKRtk2UcaeBGNtQZ/HkLmmq0pBY/KLU1zsU0mmzIShvVzt/
+kVsoTqRg+9yaanmnFlPjid29WmMFi15APO1C5q/8VyddIpDT7IBGFys4UQZlMWdB
+rJDEy9Te6bJm6XD0Px8VkvYFOsdPq1b60svL8302xT5O7W2bHD97JPGt/h6Dhfz0
+EQgk/Ua0sNeG/w/qEJhGMkyIJCUFxGZeYSwC0Zo3xsOVVAcdQepgDQztWPfizBYr
+e6CjnS5pySQOmfZzUdAIrvEEHuUNPx06CHUJ8WZFLKCxCv1smElLKMO1avlFqIUf
+etkL1jeLmVTzpnPJR0oss4pcQygH8G3f8uJ+lWo5clX/U3F8pw4ex2/FcXvkAdVA
+fEHMJd1PI93aj6RlqQvsnoanwvuwHOzKRZQG7YD+P4m+xnb/eGaGpWO0942E8isv
+tPCHspmcyZrKDjUBrh0fhVAbgy2Nw90a3z63/WNlGlfs/iPOuCwoPgA2ZsI5yxw3
+SUef6gZmAaHRxV7GQe58rw1Uh1fuNEGVRnXPmkMylaXNMqtNc1Own4YeUjCrk8MQ
+d0PkZURVk9UYl+/EZl6SU9zs2ew9+yz+yRRtypkOGhBeXtBFewlJTOZLCU9r64+y
+z946BACZ+2Zolb39MqvbUaH4sKOlk06BwRzsoYE8cGL+wwfTIb6X2ufWn4zSKQTW
+5ab3M+UCyDVWbhq3JP/aDEuUhVyjiJQOcsirn7RT6wJk9JtvsnnppWG89xgYd57A
+MCwABkwJsYkuJSzzDr/YNszhmG4qgg8zRG9GyUi/kv76mn+spafklDmSso5jxKV9
+MS8dzX++X6X61YtxkZObALClDtlnPIuKJF8NwBu6ILhEg86lijrG2jALV5BDT/95
+lohDM9lGSOVyspLsKMAj3ve0gXhBGuJxwvNMCQnbJOa1AOImiJq5bzCCBYQGCSqG
+SIb3DQEHAaCCBXUEggVxMIIFbTCCBWkGCyqGSIb3DQEMCgECoIIFMTCCBS0wVwYJ
+KoZIhvcNAQUNMEowKQYJKoZIhvcNAQUMMBwECO5FOXAMHpBVAgIIADAMBggqhkiG
+9w0CCQUAMB0GCWCGSAFlAwQBKgQQVA6Zhn0y2RSi31z5y7ZXDwSCBNCNys8gKan1
+hQOA+EVdF+TScwd4+lc+Dw50hSPwH/jGhi0QAXI4aoYu5wP8AVKunU4oqn9+bZEs
+9XWidPpA5TXLFzdDtOmfPq8yQjHarBv2sTZGy11KEAj5ZZKMGH78Ocy2sFkFfI/h
+/JgqMGM/5RXyfihEUTI/wfp6MbZHB5WEYwvkBDNDWaFqZ52rKSu9wB8KWUMoQb4N
+i5zGGH+J5rxKUOF/7BcnyuMdkhtTpkC0uKGrjl9Oj6Qui9W9CUgoKltDSuZBrFmJ
+lYPBtiUcmFnaHKuc2vXyMT54wvRJ3swjRR9tC6cWiYErGfgStW8rKjVJ9yDCuqsp
+s3+pJwnpfFpCSfc7CfbZ0b2GIWdUx83Wr9jY+O3pi+3JkBm2nR6/CBQKRwZrKS3p
+LjJAIYy7PZhPMCqpPEeytglrTBVEGTCi+aKCjFy2wvyn7Ly1XMX9O/GBQncgMyXc
+9MuLODouWnS/u8R8mOwqHcnajWBt7qdVzH02bGFMjkoYj+tjtaQ2HKrlxjFU6uXW
+yYVqm+9PYXTazIXkxHAro5Azfzkjxf2vAxeA23HRTZ1rN1VnYskJ5F+8sjK5vEDu
+SnZaJ9VRlmIBk1WBHciu4TFN8PJxfPm2W/IuzeSgP3zIYEFhtYuRh44FMouP/36/
+yLRXxDbSuDA5gx6ZUGAMLg88hcgFhroA6lLNtVjMnNvk48YI9ktKYmo9z/Xp31ai
+BzcLZBfjJXYRQ7JhQk8fOXPc6xYvvavQS7qjDhHBEmOZKr/kc4PWyfziOTyNm1+C
+xwpSqUJtgUQ39tFonRGrJSSghry2aZvDAf8O0w7stXIqUcE8P8kTno+Y/WVs9E0l
+dv2Nf/ZwdVq3/G7kGWwnvbwKFQoWItdK9XKpai4Jf8mbIxIKvfNbAcrA5HmDI6Hx
+YCrFV9FEFXMYZAo0/joeO3UHLRD2qPmNrELSWF4RBy3LkMwhhusxOm0nBLszcnpQ
+fAY7S0CcZfOohGXinhzCB8BrLuzoYfS8u0yMwGIkEtRbZfLWdzQK0jfV2trbvgKG
+iLMwtDsg8DzQOA6p2CYZvZVdB4hqwDHEmUECTMJ91AdFzAzXsO89KbL9Dgha8BbO
+ZtZt5hy8vDAm5oWaPSPihFOcRJ92CweAjD1AeDKdxIjqk+hhJLZHvACunLbV6Y6E
+m22PEZITRpCrhFUBtwN82Rnrh3a2bTXGmxKZ48ezjxGJuSH6Yv+5+FIHIY/ostBo
+5KqaMTXAi5y0BdjBvQi1OgZSc+llGE45JaVa8Mwb7yt2WASEKLYLknyDN68S2NuM
+MuAWc1upTh2JV2f6sqyPLlt9ckOCEzt9C8XlpLkln6ZTd7F/fOhzJAp2uMCwkRt6
+LrlBdQvKcIcokYuUGbD3wsyDWjhWilPKN67LKksKk3JK+s2Vp+Mf5ru75aBWNncC
+KBXNhkVd0ITtuybOXTQLKCvuSsGN1GgAx9c5qs2ipegReMb/qsOILxoGJGE4Ccs8
+vzEfEtE4rN3CT03Emfy8q/SdpF3OLxqIF1QW+Mg719Jh7cHh4aMhLRjbKkYNsOO6
+7as2wPuH5kNZspQpHM5rgDmfGuRKt/lxgIvUVSz+p83mk+Ltz0giSg/KltB29H00
+oXpzFthpkMeTXNMxk/TcYV19eed9G1jH+zElMCMGCSqGSIb3DQEJFTEWBBRMtuNi
+afImNFfITW6VBqeu/oJAkDBBMDEwDQYJYIZIAWUDBAIBBQAEINwFLOgL7HjIAiLW
+RbExlKoxxX6fbcwyMN1Vbx5Ja5oCBAj9sgZs/lqBxAICCAA=`
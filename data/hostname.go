@@ -0,0 +1,149 @@
+package data
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// maxHostnameLength the max length of a DNS name (RFC 1035)
+const maxHostnameLength = 253
+
+// HostnameLookupOptions options for looking up the certificates valid for a hostname
+type HostnameLookupOptions struct {
+	// the time at which the certificates are valid (now if zero)
+	Time time.Time
+	// also return the certificates not valid at Time (expired or not yet valid)
+	IncludeInvalid bool
+}
+
+// HostnameMatch a certificate with a SAN matching a hostname
+// swagger:model
+type HostnameMatch struct {
+	// the SAN of the certificate matching the hostname
+	//
+	// required: false
+	MatchedSAN string `json:"matched_san"`
+	// if the matching SAN is a wildcard
+	//
+	// required: false
+	Wildcard bool `json:"wildcard"`
+	// the remaining validity in seconds at the lookup time (negative if expired)
+	//
+	// required: false
+	RemainingValidity int64 `json:"remaining_validity"`
+	// the certificate
+	//
+	// required: false
+	Certificate *Certificate `json:"certificate"`
+}
+
+// normalizeHostname return the SAN type (dns or ip) and the value of hostname as stored in the SANs
+// (lower case DNS name without the trailing dot, canonical IP), a DBObjectValidationError if invalid
+func normalizeHostname(hostname string) (string, string, error) {
+
+	host := strings.TrimSpace(hostname)
+
+	// IPv6 may be given as an URL host
+	ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+	if ip != nil {
+		return SANTypeIP, ip.String(), nil
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		return "", "", &DBObjectValidationError{Msg: "invalid hostname: empty hostname"}
+	}
+
+	if len(host) > maxHostnameLength {
+		return "", "", &DBObjectValidationError{Msg: fmt.Sprintf("invalid hostname: longer than %d characters", maxHostnameLength)}
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" {
+			return "", "", &DBObjectValidationError{Msg: fmt.Sprintf("invalid hostname '%s': empty label", hostname)}
+		}
+
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+				// internationalized names must be given as A-labels (xn--)
+				return "", "", &DBObjectValidationError{Msg: fmt.Sprintf("invalid hostname '%s': invalid character '%c'", hostname, c)}
+			}
+		}
+	}
+
+	return SANTypeDNS, host, nil
+}
+
+// hostnameCandidates return the SAN values that can match the normalized DNS name host:
+// the name itself and the wildcard of its parent domain (RFC 6125), a wildcard
+// matches a single left-most label and never a top level domain (e.g. *.com)
+func hostnameCandidates(host string) []string {
+
+	candidates := []string{host}
+
+	i := strings.Index(host, ".")
+	if i > 0 && strings.Contains(host[i+1:], ".") {
+		candidates = append(candidates, "*"+host[i:])
+	}
+
+	return candidates
+}
+
+// matchHostname return true if the DNS SAN pattern matches the normalized DNS name host
+// following the RFC 6125 rules: the wildcard '*' is the complete left-most label
+// of the pattern and matches exactly one label of host
+func matchHostname(pattern string, host string) bool {
+
+	pattern = strings.ToLower(strings.TrimSuffix(pattern, "."))
+
+	if !strings.Contains(pattern, "*") {
+		return pattern == host
+	}
+
+	if !strings.HasPrefix(pattern, "*.") || strings.Contains(pattern[1:], "*") {
+		return false
+	}
+
+	for _, candidate := range hostnameCandidates(host)[1:] {
+		if candidate == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newHostnameMatch return the match of the cert for the normalized hostname of type sanType
+// at t, an exact SAN is preferred to a wildcard, nil if no SAN of the cert matches
+func newHostnameMatch(cert *Certificate, sanType string, hostname string, t time.Time) *HostnameMatch {
+
+	var match *HostnameMatch
+	for _, san := range cert.SubjectAltNames {
+		if san.Type != sanType {
+			continue
+		}
+
+		if sanType == SANTypeIP {
+			ip := net.ParseIP(san.Value)
+			if ip == nil || ip.String() != hostname {
+				continue
+			}
+		} else if !matchHostname(san.Value, hostname) {
+			continue
+		}
+
+		wildcard := strings.HasPrefix(san.Value, "*")
+		if match == nil || (match.Wildcard && !wildcard) {
+			match = &HostnameMatch{
+				MatchedSAN:        san.Value,
+				Wildcard:          wildcard,
+				RemainingValidity: int64(cert.NotAfter.Sub(t) / time.Second),
+				Certificate:       cert,
+			}
+		}
+	}
+
+	return match
+}
@@ -0,0 +1,58 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+//
+// Hostname functions
+// Read: ListCertsForHostname
+//
+
+// ListCertsForHostname returns the certs with a SAN matching the hostname or IP (RFC 6125 wildcards)
+// valid at opts.Time (unless opts.IncludeInvalid) ranked by remaining validity (the longest first)
+// an invalid hostname returns a DBObjectValidationError
+func (certBackend *CertBackend) ListCertsForHostname(hostname string, opts HostnameLookupOptions) ([]HostnameMatch, error) {
+	certBackend.logger.Debug("ListCertsForHostname: ", "hostname", hostname, "opts", opts)
+
+	sanType, host, err := normalizeHostname(hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []string{host}
+	if sanType == SANTypeDNS {
+		candidates = hostnameCandidates(host)
+	}
+
+	t := opts.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	query := certBackend.db.Preload(clause.Associations).
+		Where("certificates.id IN (SELECT certificate_id FROM subject_alt_names WHERE type = ? AND value IN ?)", sanType, candidates)
+
+	if !opts.IncludeInvalid {
+		// the validity of the certs is stored in UTC
+		query = query.Where("certificates.not_before <= ? AND certificates.not_after > ?", t.UTC(), t.UTC())
+	}
+
+	var certList Certificates
+	result := query.Order("certificates.not_after DESC").Order("certificates.id").Find(&certList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	matches := []HostnameMatch{}
+	for _, cert := range certList {
+		// the SANs are matched again to report the matching SAN
+		if match := newHostnameMatch(cert, sanType, host, t); match != nil {
+			matches = append(matches, *match)
+		}
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,97 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchHostname(t *testing.T) {
+
+	tests := []struct {
+		pattern string
+		host    string
+		match   bool
+	}{
+		{"www.example.com", "www.example.com", true},
+		{"WWW.Example.com.", "www.example.com", true},
+		{"*.example.com", "api.example.com", true},
+		{"*.example.com", "eu.api.example.com", false},
+		{"*.example.com", "example.com", false},
+		{"*.com", "example.com", false},
+		{"api*.example.com", "api1.example.com", false},
+		{"*.*.example.com", "eu.api.example.com", false},
+		{"www.*.com", "www.example.com", false},
+	}
+
+	for _, test := range tests {
+		if matchHostname(test.pattern, test.host) != test.match {
+			t.Logf("Expecting match %v for '%s' and '%s'", test.match, test.pattern, test.host)
+			t.FailNow()
+		}
+	}
+}
+
+func TestListCertsForHostname(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	for _, pem := range []string{testRootPEM, testIntermediatePEM, testLeafPEM, testRenewedLeafPEM} {
+		_, err := certBakcend.CreateCertificate(pem)
+		if err != nil {
+			t.Logf("Error creating cert %s", err.Error())
+			t.FailNow()
+		}
+	}
+
+	// the leaf is valid, the renewed leaf is not yet valid
+	at := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		hostname string
+		count    int
+		san      string
+	}{
+		{"www.example.com", 1, "www.example.com"},
+		{"WWW.Example.COM.", 1, "www.example.com"},
+		{"eu.api.example.com", 1, "*.api.example.com"},
+		{"api.example.com", 0, ""},
+		{"a.eu.api.example.com", 0, ""},
+		{"10.0.0.1", 1, "10.0.0.1"},
+		{"[::1]", 0, ""},
+	}
+
+	for _, test := range tests {
+		matches, err := certBakcend.ListCertsForHostname(test.hostname, HostnameLookupOptions{Time: at})
+		if err != nil || len(matches) != test.count || (test.count > 0 && matches[0].MatchedSAN != test.san) {
+			t.Logf("Expecting %d certs matching %s for '%s' got %v (%v)", test.count, test.san, test.hostname, matches, err)
+			t.FailNow()
+		}
+	}
+
+	// the invalid certs are ranked by remaining validity
+	matches, err := certBakcend.ListCertsForHostname("eu.api.example.com", HostnameLookupOptions{Time: at, IncludeInvalid: true})
+	if err != nil || len(matches) != 2 || matches[0].Certificate.NotAfter.Year() != 2041 || !matches[0].Wildcard {
+		t.Logf("Expecting the renewed leaf first of 2 got %v (%v)", matches, err)
+		t.FailNow()
+	}
+
+	if matches[1].RemainingValidity != int64(matches[1].Certificate.NotAfter.Sub(at)/time.Second) {
+		t.Logf("Expecting remaining validity until the not after got %d", matches[1].RemainingValidity)
+		t.FailNow()
+	}
+
+	// no cert is valid after the expiry of the leaves
+	matches, err = certBakcend.ListCertsForHostname("www.example.com", HostnameLookupOptions{Time: at.AddDate(20, 0, 0)})
+	if err != nil || len(matches) != 0 {
+		t.Logf("Expecting no valid certs got %v (%v)", matches, err)
+		t.FailNow()
+	}
+
+	for _, invalid := range []string{"", "*.example.com", "www..example.com", "bücher.example.com"} {
+		_, err := certBakcend.ListCertsForHostname(invalid, HostnameLookupOptions{})
+		if _, ok := err.(*DBObjectValidationError); !ok {
+			t.Logf("Expecting validation error for '%s' got %v", invalid, err)
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package data
+
+import (
+	"crypto/x509"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+//
+// Issuer functions
+// Read:    GetCertIssuerByID, ListCertsIssuedByID
+// Resolve: ResolveCertIssuers, resolveCertIssuer, resolveIssuedCerts
+//
+
+// GetCertIssuerByID return the Certificate that issued the cert (uuid)
+func (certBackend *CertBackend) GetCertIssuerByID(uuid uuid.UUID) (*Certificate, error) {
+	certBackend.logger.Debug("GetCertIssuerByID: Getting issuer...", "uuid", uuid)
+
+	cert, err := certBackend.GetCertByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if cert.IssuerID == nil {
+		return nil, &DBObjectNotFound{ID: "issuer of " + uuid.String()}
+	}
+
+	return certBackend.GetCertByID(*cert.IssuerID)
+}
+
+// ListCertsIssuedByID return all certs issued by the CA (uuid)
+func (certBackend *CertBackend) ListCertsIssuedByID(uuid uuid.UUID) (Certificates, error) {
+	certBackend.logger.Debug("ListCertsIssuedByID: Listing issued certs...", "uuid", uuid)
+
+	// check the CA exists
+	_, err := certBackend.GetCertByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	var certList Certificates
+	result := certBackend.db.Preload(clause.Associations).Where("issuer_id = ?", uuid).Find(&certList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return certList, nil
+}
+
+// ResolveCertIssuers resolve the issuer of all certs without a known issuer
+func (certBackend *CertBackend) ResolveCertIssuers() error {
+	certBackend.logger.Debug("ResolveCertIssuers: Resolving issuers...")
+
+	var certList Certificates
+	result := certBackend.db.Where("issuer_id IS NULL AND is_self_signed = ?", false).Find(&certList)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, c := range certList {
+		err := certBackend.resolveCertIssuer(c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveCertIssuer lookup the CA that signed cert and store the link
+// candidates are CAs whose subject is the cert issuer (and whose SKI is the cert AKI),
+// the link is only kept if the signature of the cert verifies with the CA key
+func (certBackend *CertBackend) resolveCertIssuer(cert *Certificate) error {
+
+	// a root is its own issuer
+	if cert.IsSelfSigned {
+		return nil
+	}
+
+	x509Cert, err := cert.ToX509()
+	if err != nil {
+		return err
+	}
+
+	// a CA without SubjectKeyId can still be the issuer, the signature decides
+	query := certBackend.db.Where("is_ca = ? AND subject = ? AND id <> ?", true, cert.Issuer, cert.ID)
+	if cert.AKI != "" {
+		query = query.Where("(ski = ? OR ski = '')", cert.AKI)
+	}
+
+	var candidates Certificates
+	result := query.Find(&candidates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, ca := range candidates {
+		if !isSignedBy(x509Cert, ca) {
+			continue
+		}
+
+		certBackend.logger.Debug("resolveCertIssuer: found issuer", "cert", cert.ID, "issuer", ca.ID)
+		result := certBackend.db.Model(&Certificate{}).Where("id = ?", cert.ID).Update("issuer_id", ca.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		cert.IssuerID = &ca.ID
+		return nil
+	}
+
+	return nil
+}
+
+// resolveIssuedCerts link the certs without a known issuer that were signed by ca
+func (certBackend *CertBackend) resolveIssuedCerts(ca *Certificate) error {
+
+	if !ca.IsCA {
+		return nil
+	}
+
+	x509CA, err := ca.ToX509()
+	if err != nil {
+		return err
+	}
+
+	query := certBackend.db.Where("issuer_id IS NULL AND is_self_signed = ? AND issuer = ? AND id <> ?", false, ca.Subject, ca.ID)
+	if ca.SKI != "" {
+		query = query.Where("(aki = ? OR aki = '')", ca.SKI)
+	}
+
+	var orphans Certificates
+	result := query.Find(&orphans)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, c := range orphans {
+		x509Cert, err := c.ToX509()
+		if err != nil {
+			continue
+		}
+
+		if x509Cert.CheckSignatureFrom(x509CA) != nil {
+			continue
+		}
+
+		certBackend.logger.Debug("resolveIssuedCerts: found issued cert", "cert", c.ID, "issuer", ca.ID)
+		result := certBackend.db.Model(&Certificate{}).Where("id = ?", c.ID).Update("issuer_id", ca.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	return nil
+}
+
+// isSignedBy return true if the signature of x509Cert verifies with the key of ca
+func isSignedBy(x509Cert *x509.Certificate, ca *Certificate) bool {
+
+	x509CA, err := ca.ToX509()
+	if err != nil {
+		return false
+	}
+
+	return x509Cert.CheckSignatureFrom(x509CA) == nil
+}
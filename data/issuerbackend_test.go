@@ -0,0 +1,161 @@
+package data
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestCertIssuer(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	// create the leaf before its issuer
+	leaf, err := certBakcend.CreateCertificate(testLeafPEM)
+	if err != nil {
+		t.Logf("Error creating Cert %s", err.Error())
+		t.FailNow()
+	}
+
+	if leaf.IssuerID != nil {
+		t.Logf("Expecting no issuer for leaf")
+		t.FailNow()
+	}
+
+	// creating the intermediate links the leaf
+	intermediate, err := certBakcend.CreateCertificate(testIntermediatePEM)
+	if err != nil {
+		t.Logf("Error creating Cert %s", err.Error())
+		t.FailNow()
+	}
+
+	issuer, err := certBakcend.GetCertIssuerByID(leaf.ID)
+	if err != nil {
+		t.Logf("Error getting issuer %s", err.Error())
+		t.FailNow()
+	}
+
+	if issuer.ID != intermediate.ID {
+		t.Logf("Expecting issuer '%s', but got '%s'", intermediate.ID, issuer.ID)
+		t.FailNow()
+	}
+
+	// creating the root resolves the intermediate issuer
+	root, err := certBakcend.CreateCertificate(testRootPEM)
+	if err != nil {
+		t.Logf("Error creating Cert %s", err.Error())
+		t.FailNow()
+	}
+
+	if !root.IsSelfSigned || root.IssuerID != nil {
+		t.Logf("Expecting root to be self-signed without issuer")
+		t.FailNow()
+	}
+
+	issued, err := certBakcend.ListCertsIssuedByID(root.ID)
+	if err != nil {
+		t.Logf("Error listing issued certs %s", err.Error())
+		t.FailNow()
+	}
+
+	if len(issued) != 1 || issued[0].ID != intermediate.ID {
+		t.Logf("Expecting intermediate to be issued by root %v", issued)
+		t.FailNow()
+	}
+
+	// a root has no stored issuer
+	_, err = certBakcend.GetCertIssuerByID(root.ID)
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting not found error got %v", err)
+		t.FailNow()
+	}
+
+	// deleting the intermediate unlinks the leaf
+	err = certBakcend.DeleteCertByID(intermediate.ID)
+	if err != nil {
+		t.Logf("Error deleting cert %s", err.Error())
+		t.FailNow()
+	}
+
+	leaf, err = certBakcend.GetCertByID(leaf.ID)
+	if err != nil || leaf.IssuerID != nil {
+		t.Logf("Expecting leaf without issuer %v", err)
+		t.FailNow()
+	}
+}
+
+func TestCertIssuerWithoutSKI(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Logf("Error generating key %s", err.Error())
+		t.FailNow()
+	}
+
+	// a CA with an empty SubjectKeyId (crypto/x509 generates one for a CA otherwise)
+	emptySKI, _ := asn1.Marshal([]byte{})
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "No SKI CA"},
+		NotBefore:             time.Now().AddDate(0, -1, 0),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		ExtraExtensions:       []pkix.Extension{{Id: asn1.ObjectIdentifier{2, 5, 29, 14}, Value: emptySKI}},
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &key.PublicKey, key)
+	if err != nil {
+		t.Logf("Error creating CA %s", err.Error())
+		t.FailNow()
+	}
+
+	x509CA, err := x509.ParseCertificate(caDER)
+	if err != nil || len(x509CA.SubjectKeyId) != 0 {
+		t.Logf("Expecting CA without SKI got %v (%v)", x509CA, err)
+		t.FailNow()
+	}
+
+	// a leaf with an AuthorityKeyId signed by the CA
+	leafTemplate := &x509.Certificate{
+		SerialNumber:   big.NewInt(2),
+		Subject:        pkix.Name{CommonName: "leaf.example.com"},
+		NotBefore:      time.Now().AddDate(0, -1, 0),
+		NotAfter:       time.Now().AddDate(1, 0, 0),
+		AuthorityKeyId: []byte{1, 2, 3, 4},
+	}
+
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, x509CA, &key.PublicKey, key)
+	if err != nil {
+		t.Logf("Error creating leaf %s", err.Error())
+		t.FailNow()
+	}
+
+	ca, err := certBakcend.CreateCertificate(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})))
+	if err != nil || ca.SKI != "" {
+		t.Logf("Expecting CA stored without SKI got %v (%v)", ca, err)
+		t.FailNow()
+	}
+
+	leaf, err := certBakcend.CreateCertificate(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})))
+	if err != nil || leaf.AKI == "" {
+		t.Logf("Expecting leaf stored with AKI got %v (%v)", leaf, err)
+		t.FailNow()
+	}
+
+	// the signature decides
+	if leaf.IssuerID == nil || *leaf.IssuerID != ca.ID {
+		t.Logf("Expecting issuer '%s', but got %v", ca.ID, leaf.IssuerID)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,290 @@
+package data
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxLabelNameLength the max length of the name of a label key and of a label value
+	maxLabelNameLength = 63
+	// maxLabelPrefixLength the max length of the DNS subdomain prefix of a label key
+	maxLabelPrefixLength = 253
+)
+
+var (
+	// labelNameRegexp the name of a label key or a label value (e.g. app.kubernetes_io-name)
+	labelNameRegexp = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	// labelPrefixRegexp the DNS subdomain prefix of a label key (e.g. example.com)
+	labelPrefixRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// Label defines a key/value Label of a X509 Cert (e.g. env=prod)
+// swagger:model
+type Label struct {
+	// the id for the Label
+	//
+	// required: false
+	ID uuid.UUID `json:"-" gorm:"type:uuid;primary_key;"`
+
+	// the id of the certificate of the Label
+	//
+	// required: false
+	CertificateID uuid.UUID `json:"-" gorm:"type:uuid;uniqueIndex:idx_label_certificate_key"`
+
+	// the key of the Label: an optional DNS subdomain prefix and a name (e.g. example.com/owner)
+	//
+	// required: true
+	Key string `json:"key" gorm:"uniqueIndex:idx_label_certificate_key;index:idx_label_key_value" validate:"label_key"`
+
+	// the value of the Label (may be empty)
+	//
+	// required: false
+	Value string `json:"value" gorm:"index:idx_label_key_value" validate:"label_value"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (label *Label) BeforeCreate(tx *gorm.DB) (err error) {
+	if label.ID == uuid.Nil {
+		uuid := uuid.New()
+		label.ID = uuid
+	}
+
+	return
+}
+
+// IsLabelKey return true if key is a valid label key: a name of at most 63 characters
+// (alphanumeric, '-', '_' or '.' starting and ending with an alphanumeric) with an optional
+// DNS subdomain prefix of at most 253 characters separated by a '/' (e.g. example.com/owner)
+func IsLabelKey(key string) bool {
+
+	name := key
+	if i := strings.Index(key, "/"); i >= 0 {
+		prefix := key[:i]
+		if len(prefix) > maxLabelPrefixLength || !labelPrefixRegexp.MatchString(prefix) {
+			return false
+		}
+		name = key[i+1:]
+	}
+
+	return len(name) <= maxLabelNameLength && labelNameRegexp.MatchString(name)
+}
+
+// IsLabelValue return true if value is a valid label value: empty or a name of at most 63 characters
+// (alphanumeric, '-', '_' or '.' starting and ending with an alphanumeric)
+func IsLabelValue(value string) bool {
+	return value == "" || (len(value) <= maxLabelNameLength && labelNameRegexp.MatchString(value))
+}
+
+// label selector operators
+const (
+	labelSelectorEquals    = "="
+	labelSelectorNotEquals = "!="
+	labelSelectorIn        = "in"
+	labelSelectorNotIn     = "notin"
+	labelSelectorExists    = "exists"
+	labelSelectorNotExists = "!"
+)
+
+// labelRequirement a requirement of a label selector on the labels with key
+type labelRequirement struct {
+	key      string
+	operator string
+	values   []string
+}
+
+// labelSelectorParser parse a label selector
+type labelSelectorParser struct {
+	selector string
+	pos      int
+}
+
+// parseLabelSelector parse a comma separated list of requirements on the labels (all must match)
+// key=value, key==value, key!=value, key in (v1,v2), key notin (v1,v2), key (exists) and !key (not exists)
+// e.g. env=prod,tier!=db,owner in (alice,bob), an invalid selector returns a DBObjectValidationError
+func parseLabelSelector(selector string) ([]labelRequirement, error) {
+
+	p := &labelSelectorParser{selector: selector}
+
+	requirements := []labelRequirement{}
+	for {
+		requirement, err := p.parseRequirement()
+		if err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, *requirement)
+
+		p.skipSpaces()
+		if p.pos == len(p.selector) {
+			return requirements, nil
+		}
+
+		if p.selector[p.pos] != ',' {
+			return nil, p.errorf("expected ',' but got '%c'", p.selector[p.pos])
+		}
+		p.pos++
+	}
+}
+
+// errorf return a DBObjectValidationError at the current position
+func (p *labelSelectorParser) errorf(format string, args ...interface{}) error {
+	return &DBObjectValidationError{Msg: fmt.Sprintf("invalid label selector at position %d: %s", p.pos+1, fmt.Sprintf(format, args...))}
+}
+
+// skipSpaces skip the spaces at the current position
+func (p *labelSelectorParser) skipSpaces() {
+	for p.pos < len(p.selector) && p.selector[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// word return the key or value at the current position (empty if none)
+func (p *labelSelectorParser) word() string {
+
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.selector) && !strings.ContainsRune(" ,()=!", rune(p.selector[p.pos])) {
+		p.pos++
+	}
+
+	return p.selector[start:p.pos]
+}
+
+// parseKey parse a valid label key at the current position
+func (p *labelSelectorParser) parseKey() (string, error) {
+
+	start := p.pos
+	key := p.word()
+	if key == "" {
+		return "", p.errorf("expected a label key")
+	}
+
+	if !IsLabelKey(key) {
+		p.pos = start
+		p.skipSpaces()
+		return "", p.errorf("invalid label key '%s'", key)
+	}
+
+	return key, nil
+}
+
+// parseValue parse a valid label value at the current position
+func (p *labelSelectorParser) parseValue() (string, error) {
+
+	start := p.pos
+	value := p.word()
+	if !IsLabelValue(value) {
+		p.pos = start
+		p.skipSpaces()
+		return "", p.errorf("invalid label value '%s'", value)
+	}
+
+	return value, nil
+}
+
+// parseRequirement parse a requirement at the current position
+func (p *labelSelectorParser) parseRequirement() (*labelRequirement, error) {
+
+	p.skipSpaces()
+	if p.pos < len(p.selector) && p.selector[p.pos] == '!' {
+		p.pos++
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		return &labelRequirement{key: key, operator: labelSelectorNotExists}, nil
+	}
+
+	key, err := p.parseKey()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	rest := p.selector[p.pos:]
+
+	switch {
+	case rest == "" || rest[0] == ',':
+		return &labelRequirement{key: key, operator: labelSelectorExists}, nil
+
+	case strings.HasPrefix(rest, "!="), strings.HasPrefix(rest, "=="), strings.HasPrefix(rest, "="):
+		operator := labelSelectorEquals
+		if strings.HasPrefix(rest, "!=") {
+			operator = labelSelectorNotEquals
+		}
+
+		p.pos++
+		if len(rest) > 1 && rest[1] == '=' {
+			p.pos++
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		return &labelRequirement{key: key, operator: operator, values: []string{value}}, nil
+	}
+
+	start := p.pos
+	operator := p.word()
+	if operator != labelSelectorIn && operator != labelSelectorNotIn {
+		p.pos = start
+		return nil, p.errorf("expected '=', '!=', 'in' or 'notin' after '%s'", key)
+	}
+
+	p.skipSpaces()
+	if p.pos == len(p.selector) || p.selector[p.pos] != '(' {
+		return nil, p.errorf("expected '(' after '%s'", operator)
+	}
+	p.pos++
+
+	values := []string{}
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		p.skipSpaces()
+		if p.pos == len(p.selector) {
+			return nil, p.errorf("expected ')' to close the values of '%s'", key)
+		}
+
+		c := p.selector[p.pos]
+		p.pos++
+		if c == ')' {
+			break
+		}
+		if c != ',' {
+			p.pos--
+			return nil, p.errorf("expected ',' or ')' but got '%c'", c)
+		}
+	}
+
+	return &labelRequirement{key: key, operator: operator, values: values}, nil
+}
+
+// apply add the condition of the requirement to the certificates query
+func (requirement *labelRequirement) apply(query *gorm.DB) *gorm.DB {
+
+	labelQuery := "SELECT labels.certificate_id FROM labels WHERE labels.key = ?"
+
+	switch requirement.operator {
+	case labelSelectorEquals, labelSelectorIn:
+		return query.Where("certificates.id IN ("+labelQuery+" AND labels.value IN ?)", requirement.key, requirement.values)
+	case labelSelectorNotEquals, labelSelectorNotIn:
+		// the certs without the label also match
+		return query.Where("certificates.id NOT IN ("+labelQuery+" AND labels.value IN ?)", requirement.key, requirement.values)
+	case labelSelectorExists:
+		return query.Where("certificates.id IN ("+labelQuery+")", requirement.key)
+	default:
+		return query.Where("certificates.id NOT IN ("+labelQuery+")", requirement.key)
+	}
+}
@@ -0,0 +1,115 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//
+// Label functions
+// Update: SetCertLabelsByID
+// Delete: DeleteCertificateLabelsByID
+//
+
+// SetCertLabelsByID set the labels (key/value) of cert (uuid), the value of an existing key is replaced
+// an invalid key or value returns a DBObjectValidationError
+func (certBackend *CertBackend) SetCertLabelsByID(uuid uuid.UUID, labels map[string]string) (*Certificate, error) {
+	certBackend.logger.Debug("SetCertLabelsByID: Setting labels for Certificate...", "labels", labels)
+
+	// get cert by uuid
+	cert, err := certBackend.GetCertByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+	before := NewAuditCertState(cert)
+
+	// sorted to get the same validation error for the same labels
+	keys := []string{}
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	newLabels := []Label{}
+	for _, k := range keys {
+		label := Label{CertificateID: cert.ID, Key: k, Value: labels[k]}
+
+		validationErr := certBackend.v.Validate(&label)
+		if validationErr != nil {
+			return nil, &DBObjectValidationError{Msg: fmt.Sprintf("invalid label %s=%s: %s", k, labels[k], strings.Join(validationErr.Errors(), "\n"))}
+		}
+
+		newLabels = append(newLabels, label)
+	}
+
+	// replace the labels with the same keys
+	err = certBackend.auditTransaction(func(tx *gorm.DB) error {
+		if len(newLabels) != 0 {
+			result := tx.Where("certificate_id = ? AND key IN ?", cert.ID, keys).Delete(&Label{})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			result = tx.Create(&newLabels)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		cert, err = certBackend.withTx(tx).GetCertByID(uuid)
+		if err != nil {
+			return err
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionSetLabels, AuditObjectCertificate, cert.ID, before, NewAuditCertState(cert))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cert, nil
+}
+
+// DeleteCertificateLabelsByID remove the labels with keys from cert (uuid), the missing keys are ignored
+// an invalid key returns a DBObjectValidationError
+func (certBackend *CertBackend) DeleteCertificateLabelsByID(uuid uuid.UUID, keys []string) (*Certificate, error) {
+	certBackend.logger.Debug("DeleteCertificateLabelsByID: removing labels from Certificate...", "keys", keys)
+
+	for _, k := range keys {
+		if !IsLabelKey(k) {
+			return nil, &DBObjectValidationError{Msg: fmt.Sprintf("invalid label key '%s'", k)}
+		}
+	}
+
+	// get cert by uuid
+	cert, err := certBackend.GetCertByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+	before := NewAuditCertState(cert)
+
+	err = certBackend.auditTransaction(func(tx *gorm.DB) error {
+		if len(keys) != 0 {
+			result := tx.Where("certificate_id = ? AND key IN ?", cert.ID, keys).Delete(&Label{})
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		cert, err = certBackend.withTx(tx).GetCertByID(uuid)
+		if err != nil {
+			return err
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionRemoveLabels, AuditObjectCertificate, cert.ID, before, NewAuditCertState(cert))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cert, nil
+}
@@ -0,0 +1,128 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabelSyntax(t *testing.T) {
+
+	v := NewValidation()
+
+	tests := []struct {
+		label Label
+		valid bool
+	}{
+		{Label{Key: "env", Value: "prod"}, true},
+		{Label{Key: "example.com/owner", Value: "alice"}, true},
+		{Label{Key: "app.kubernetes_io-name", Value: ""}, true},
+		{Label{Key: "", Value: "prod"}, false},
+		{Label{Key: "-env", Value: "prod"}, false},
+		{Label{Key: "Example.com/owner", Value: "alice"}, false},
+		{Label{Key: "example.com/", Value: "alice"}, false},
+		{Label{Key: strings.Repeat("k", 64), Value: "prod"}, false},
+		{Label{Key: "env", Value: "prod eu"}, false},
+		{Label{Key: "env", Value: "prod-"}, false},
+	}
+
+	for _, test := range tests {
+		errs := v.Validate(&test.label)
+		if (len(errs) == 0) != test.valid {
+			t.Logf("Expecting valid %v for %s=%s got %v", test.valid, test.label.Key, test.label.Value, errs)
+			t.FailNow()
+		}
+	}
+
+	for _, invalid := range []string{"", "env=prod,", "env=prod eu", "env in prod", "env in (a,b", "env notin (a b)", "env >= 1", "!", "env=(prod)"} {
+		_, err := parseLabelSelector(invalid)
+		if _, ok := err.(*DBObjectValidationError); !ok {
+			t.Logf("Expecting validation error for '%s' got %v", invalid, err)
+			t.FailNow()
+		}
+	}
+}
+
+func TestCertLabels(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	root, err := certBakcend.CreateCertificate(testRootPEM)
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	leaf, err := certBakcend.CreateCertificate(testLeafPEM)
+	if err == nil {
+		_, err = certBakcend.CreateCertificate(testIntermediatePEM)
+	}
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.SetCertLabelsByID(root.ID, map[string]string{"env": "prod", "tier": "db", "owner": "alice"})
+	if err != nil {
+		t.Logf("Error setting labels %s", err.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.SetCertLabelsByID(leaf.ID, map[string]string{"env": "dev", "owner": "bob"})
+	if err != nil {
+		t.Logf("Error setting labels %s", err.Error())
+		t.FailNow()
+	}
+
+	// the value of an existing key is replaced
+	cert, err := certBakcend.SetCertLabelsByID(leaf.ID, map[string]string{"env": "prod", "tier": "web"})
+	if err != nil || len(cert.Labels) != 3 {
+		t.Logf("Expecting 3 labels got %v (%v)", cert, err)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.SetCertLabelsByID(leaf.ID, map[string]string{"env": "prod eu"})
+	if _, ok := err.(*DBObjectValidationError); !ok {
+		t.Logf("Expecting validation error for an invalid value got %v", err)
+		t.FailNow()
+	}
+
+	tests := []struct {
+		selector string
+		count    int
+	}{
+		{"env=prod", 2},
+		{"env==prod,tier!=db", 1},
+		{"tier!=db", 2},
+		{"owner in (alice, bob)", 2},
+		{"owner notin (alice)", 2},
+		{"owner", 2},
+		{"!owner", 1},
+		{"env=prod,owner in (carol)", 0},
+	}
+
+	for _, test := range tests {
+		certs, err := certBakcend.ListCertsWithFilter(CertFilter{LabelSelector: test.selector})
+		if err != nil || len(certs) != test.count {
+			t.Logf("Expecting %d certs for '%s' got %d (%v)", test.count, test.selector, len(certs), err)
+			t.FailNow()
+		}
+	}
+
+	_, err = certBakcend.ListCertsWithFilter(CertFilter{LabelSelector: "env in prod"})
+	if _, ok := err.(*DBObjectValidationError); !ok {
+		t.Logf("Expecting validation error for an invalid selector got %v", err)
+		t.FailNow()
+	}
+
+	cert, err = certBakcend.DeleteCertificateLabelsByID(leaf.ID, []string{"tier", "missing"})
+	if err != nil || len(cert.Labels) != 2 {
+		t.Logf("Expecting 2 labels got %v (%v)", cert, err)
+		t.FailNow()
+	}
+
+	certs, err := certBakcend.ListCertsWithFilter(CertFilter{LabelSelector: "tier"})
+	if err != nil || len(certs) != 1 || certs[0].ID != root.ID {
+		t.Logf("Expecting only the root with a tier label got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+}
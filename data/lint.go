@@ -0,0 +1,392 @@
+package data
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LintSeverity the severity of a lint finding
+type LintSeverity string
+
+const (
+	// LintSeverityInfo informational finding
+	LintSeverityInfo LintSeverity = "info"
+	// LintSeverityWarning the cert does not follow a best practice
+	LintSeverityWarning LintSeverity = "warning"
+	// LintSeverityError the cert is likely to be rejected or insecure
+	LintSeverityError LintSeverity = "error"
+)
+
+// LintRule a check run against every certificate
+// implement it and register it with CertBackend.RegisterLintRules to add custom rules
+type LintRule interface {
+	// ID the unique id of the rule (e.g. rsa_key_too_small)
+	ID() string
+	// Severity the severity of the findings of the rule
+	Severity() LintSeverity
+	// Check return a message for every problem found in cert (nil if the cert passes)
+	Check(cert *x509.Certificate) []string
+}
+
+// VersionedLintRule a LintRule with a version, bump it when the checks of the rule
+// change so the certs are linted again on startup
+type VersionedLintRule interface {
+	LintRule
+	// Version the version of the checks of the rule
+	Version() int
+}
+
+// LintRulesVersion the version of the checks of the built-in rules
+// bump it when a check changes so the certs are linted again on startup
+const LintRulesVersion = 1
+
+// LintFinding defines a problem found by a LintRule on a certificate
+// swagger:model
+type LintFinding struct {
+	// the id for the finding
+	//
+	// required: false
+	ID uuid.UUID `json:"-" gorm:"type:uuid;primary_key;"`
+
+	// the id of the certificate of the finding
+	//
+	// required: false
+	CertificateID uuid.UUID `json:"certificate_id" gorm:"type:uuid;index"`
+
+	// the id of the rule that produced the finding
+	//
+	// required: false
+	RuleID string `json:"rule_id" gorm:"index"`
+
+	// the severity of the finding: info, warning or error
+	//
+	// required: false
+	Severity LintSeverity `json:"severity" gorm:"index"`
+
+	// the description of the problem
+	//
+	// required: false
+	Message string `json:"message"`
+
+	// the CreatedAt timestamp for the finding
+	//
+	// required: false
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (finding *LintFinding) BeforeCreate(tx *gorm.DB) (err error) {
+	if finding.ID == uuid.Nil {
+		uuid := uuid.New()
+		finding.ID = uuid
+	}
+
+	return
+}
+
+// LintReportEntry a finding with the certificate it was found on
+// swagger:model
+type LintReportEntry struct {
+	LintFinding
+
+	// the sha256 of the certificate
+	//
+	// required: false
+	SHA256 string `json:"sha256"`
+
+	// the Subject of the certificate
+	//
+	// required: false
+	Subject string `json:"subject"`
+}
+
+// LintRuleSummary the number of findings of a rule
+// swagger:model
+type LintRuleSummary struct {
+	// the id of the rule
+	//
+	// required: false
+	RuleID string `json:"rule_id"`
+
+	// the severity of the rule
+	//
+	// required: false
+	Severity LintSeverity `json:"severity"`
+
+	// the number of findings of the rule
+	//
+	// required: false
+	Count int `json:"count"`
+}
+
+// LintReport the lint findings across the inventory
+// swagger:model
+type LintReport struct {
+	// the total number of findings
+	//
+	// required: false
+	Total int `json:"total"`
+
+	// the number of findings per rule
+	//
+	// required: false
+	Rules []*LintRuleSummary `json:"rules"`
+
+	// the findings
+	//
+	// required: false
+	Findings []*LintReportEntry `json:"findings"`
+}
+
+// NewLintReport create an empty LintReport
+func NewLintReport() *LintReport {
+	return &LintReport{
+		Rules:    []*LintRuleSummary{},
+		Findings: []*LintReportEntry{},
+	}
+}
+
+// Linter runs a set of LintRule against certificates
+type Linter struct {
+	rules []LintRule
+}
+
+// NewLinter create a Linter with rules
+func NewLinter(rules ...LintRule) *Linter {
+	return &Linter{rules: rules}
+}
+
+// Register add rules to the Linter, a rule replaces the registered rule with the same ID
+func (linter *Linter) Register(rules ...LintRule) {
+
+	for _, rule := range rules {
+		replaced := false
+		for i, r := range linter.rules {
+			if r.ID() == rule.ID() {
+				linter.rules[i] = rule
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			linter.rules = append(linter.rules, rule)
+		}
+	}
+}
+
+// Version return the version of the rule set, it changes when a rule is added or
+// replaced, or when the checks of a rule change (see VersionedLintRule, LintRulesVersion)
+func (linter *Linter) Version() string {
+
+	rules := []string{fmt.Sprintf("builtin:%d", LintRulesVersion)}
+	for _, rule := range linter.rules {
+		version := 0
+		if v, ok := rule.(VersionedLintRule); ok {
+			version = v.Version()
+		}
+		rules = append(rules, fmt.Sprintf("%s:%s:%T:%d", rule.ID(), rule.Severity(), rule, version))
+	}
+	sort.Strings(rules)
+
+	sum := sha256.Sum256([]byte(strings.Join(rules, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Lint return the findings of all rules for cert
+func (linter *Linter) Lint(cert *x509.Certificate) []LintFinding {
+
+	findings := []LintFinding{}
+	for _, rule := range linter.rules {
+		for _, msg := range rule.Check(cert) {
+			findings = append(findings, LintFinding{
+				RuleID:   rule.ID(),
+				Severity: rule.Severity(),
+				Message:  msg,
+			})
+		}
+	}
+
+	return findings
+}
+
+// DefaultLintRules return the built-in lint rules
+func DefaultLintRules() []LintRule {
+	return []LintRule{
+		&weakSignatureRule{},
+		&rsaKeySizeRule{minSize: 2048},
+		&tlsValidityRule{maxDays: 398, since: time.Date(2020, time.September, 1, 0, 0, 0, 0, time.UTC)},
+		&missingSANRule{},
+		&cnNotInSANRule{},
+		&caFlagOnLeafRule{},
+	}
+}
+
+// isLeaf return true if cert is not a CA
+func isLeaf(cert *x509.Certificate) bool {
+	return !cert.IsCA
+}
+
+// weakSignatureRule SHA-1 or MD5 signatures (ignored on self-signed roots)
+type weakSignatureRule struct{}
+
+func (r *weakSignatureRule) ID() string             { return "weak_signature_algorithm" }
+func (r *weakSignatureRule) Severity() LintSeverity { return LintSeverityError }
+
+func (r *weakSignatureRule) Check(cert *x509.Certificate) []string {
+
+	// the signature of a trust anchor is never verified
+	if GetIsSelfSignedFromX509Cert(cert) {
+		return nil
+	}
+
+	switch cert.SignatureAlgorithm {
+	case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		return []string{fmt.Sprintf("weak signature algorithm %s", cert.SignatureAlgorithm)}
+	}
+
+	return nil
+}
+
+// rsaKeySizeRule RSA keys under minSize bits
+type rsaKeySizeRule struct {
+	minSize int
+}
+
+func (r *rsaKeySizeRule) ID() string             { return "rsa_key_too_small" }
+func (r *rsaKeySizeRule) Severity() LintSeverity { return LintSeverityError }
+
+func (r *rsaKeySizeRule) Check(cert *x509.Certificate) []string {
+
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil
+	}
+
+	if size := key.N.BitLen(); size < r.minSize {
+		return []string{fmt.Sprintf("RSA key of %d bits is smaller than %d bits", size, r.minSize)}
+	}
+
+	return nil
+}
+
+// tlsValidityRule validity over maxDays for TLS server leaf certs issued after since
+type tlsValidityRule struct {
+	maxDays int
+	since   time.Time
+}
+
+func (r *tlsValidityRule) ID() string             { return "tls_validity_too_long" }
+func (r *tlsValidityRule) Severity() LintSeverity { return LintSeverityWarning }
+
+func (r *tlsValidityRule) Check(cert *x509.Certificate) []string {
+
+	if !isLeaf(cert) || !hasExtKeyUsage(cert, x509.ExtKeyUsageServerAuth) || cert.NotBefore.Before(r.since) {
+		return nil
+	}
+
+	days := int(cert.NotAfter.Sub(cert.NotBefore).Hours() / 24)
+	if days > r.maxDays {
+		return []string{fmt.Sprintf("validity of %d days is longer than %d days", days, r.maxDays)}
+	}
+
+	return nil
+}
+
+// missingSANRule leaf certs without any Subject Alternative Name
+type missingSANRule struct{}
+
+func (r *missingSANRule) ID() string             { return "missing_san" }
+func (r *missingSANRule) Severity() LintSeverity { return LintSeverityError }
+
+func (r *missingSANRule) Check(cert *x509.Certificate) []string {
+
+	if !isLeaf(cert) {
+		return nil
+	}
+
+	if len(cert.DNSNames)+len(cert.IPAddresses)+len(cert.EmailAddresses)+len(cert.URIs) == 0 {
+		return []string{"no Subject Alternative Name"}
+	}
+
+	return nil
+}
+
+// cnNotInSANRule leaf certs whose Common Name is not one of the DNS or IP SANs
+type cnNotInSANRule struct{}
+
+func (r *cnNotInSANRule) ID() string             { return "cn_not_in_san" }
+func (r *cnNotInSANRule) Severity() LintSeverity { return LintSeverityWarning }
+
+func (r *cnNotInSANRule) Check(cert *x509.Certificate) []string {
+
+	cn := cert.Subject.CommonName
+	if !isLeaf(cert) || cn == "" {
+		return nil
+	}
+
+	// the missing SAN is reported by missingSANRule
+	if len(cert.DNSNames)+len(cert.IPAddresses) == 0 {
+		return nil
+	}
+
+	for _, dns := range cert.DNSNames {
+		if strings.EqualFold(dns, cn) {
+			return nil
+		}
+	}
+
+	if ip := net.ParseIP(cn); ip != nil {
+		for _, san := range cert.IPAddresses {
+			if san.Equal(ip) {
+				return nil
+			}
+		}
+	}
+
+	return []string{fmt.Sprintf("Common Name '%s' is not in the SANs", cn)}
+}
+
+// caFlagOnLeafRule CA certs that cannot sign certificates (leaf with the CA flag)
+type caFlagOnLeafRule struct{}
+
+func (r *caFlagOnLeafRule) ID() string             { return "ca_flag_on_leaf" }
+func (r *caFlagOnLeafRule) Severity() LintSeverity { return LintSeverityError }
+
+func (r *caFlagOnLeafRule) Check(cert *x509.Certificate) []string {
+
+	if !cert.IsCA {
+		return nil
+	}
+
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return []string{"CA flag set on a cert without the keyCertSign key usage"}
+	}
+
+	if hasExtKeyUsage(cert, x509.ExtKeyUsageServerAuth) && !GetIsSelfSignedFromX509Cert(cert) && len(cert.DNSNames) != 0 {
+		return []string{"CA flag set on a TLS server cert"}
+	}
+
+	return nil
+}
+
+// hasExtKeyUsage return true if cert has the Extended Key Usage eku
+func hasExtKeyUsage(cert *x509.Certificate, eku x509.ExtKeyUsage) bool {
+
+	for _, u := range cert.ExtKeyUsage {
+		if u == eku {
+			return true
+		}
+	}
+
+	return false
+}
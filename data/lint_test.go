@@ -0,0 +1,238 @@
+package data
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestDefaultLintRules(t *testing.T) {
+
+	linter := NewLinter(DefaultLintRules()...)
+
+	// the leaf is valid 10 years
+	tests := []struct {
+		pem   string
+		rules []string
+	}{
+		{testRootPEM, nil},
+		{testIntermediatePEM, nil},
+		{testLeafPEM, []string{"tls_validity_too_long"}},
+	}
+
+	for _, test := range tests {
+		cert, err := ParseX509FromPEM(test.pem)
+		if err != nil {
+			t.Logf("Error parsing PEM %s", err.Error())
+			t.FailNow()
+		}
+
+		findings := linter.Lint(cert)
+		if len(findings) != len(test.rules) {
+			t.Logf("Expecting %v, but got %v", test.rules, findings)
+			t.FailNow()
+		}
+
+		for i, f := range findings {
+			if f.RuleID != test.rules[i] {
+				t.Logf("Expecting '%s', but got '%s'", test.rules[i], f.RuleID)
+				t.FailNow()
+			}
+		}
+	}
+
+	// a leaf breaking all rules
+	notBefore := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	bad := &x509.Certificate{
+		RawSubject:         []byte("subject"),
+		RawIssuer:          []byte("issuer"),
+		Subject:            pkix.Name{CommonName: "www.example.com"},
+		SignatureAlgorithm: x509.SHA1WithRSA,
+		PublicKey:          &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), 1023), E: 65537},
+		NotBefore:          notBefore,
+		NotAfter:           notBefore.AddDate(2, 0, 0),
+		ExtKeyUsage:        []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	expect := map[string]LintSeverity{
+		"weak_signature_algorithm": LintSeverityError,
+		"rsa_key_too_small":        LintSeverityError,
+		"tls_validity_too_long":    LintSeverityWarning,
+		"missing_san":              LintSeverityError,
+	}
+
+	findings := linter.Lint(bad)
+	if len(findings) != len(expect) {
+		t.Logf("Expecting %v, but got %v", expect, findings)
+		t.FailNow()
+	}
+
+	for _, f := range findings {
+		if expect[f.RuleID] != f.Severity {
+			t.Logf("Unexpected finding %v", f)
+			t.FailNow()
+		}
+	}
+
+	// CN not in SANs and CA flag on a leaf
+	bad.DNSNames = []string{"api.example.com"}
+	bad.IsCA = true
+	bad.KeyUsage = x509.KeyUsageDigitalSignature
+	rules := map[string]bool{}
+	for _, f := range NewLinter(&cnNotInSANRule{}, &caFlagOnLeafRule{}).Lint(bad) {
+		rules[f.RuleID] = true
+	}
+
+	// cn_not_in_san only applies to leaf certs
+	if !rules["ca_flag_on_leaf"] || rules["cn_not_in_san"] {
+		t.Logf("Unexpected findings %v", rules)
+		t.FailNow()
+	}
+
+	bad.IsCA = false
+	findings = NewLinter(&cnNotInSANRule{}).Lint(bad)
+	if len(findings) != 1 {
+		t.Logf("Expecting cn_not_in_san, but got %v", findings)
+		t.FailNow()
+	}
+}
+
+// testLintRule a custom rule flagging every cert
+type testLintRule struct{}
+
+func (r *testLintRule) ID() string             { return "custom" }
+func (r *testLintRule) Severity() LintSeverity { return LintSeverityInfo }
+func (r *testLintRule) Check(cert *x509.Certificate) []string {
+	return []string{"custom finding"}
+}
+
+func TestLintCertificates(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	report, err := certBakcend.CreateCertificateBundle(testLeafPEM+testIntermediatePEM+testRootPEM, nil)
+	if err != nil || len(report.Created) != 3 {
+		t.Logf("Error creating bundle %v", err)
+		t.FailNow()
+	}
+	leaf := report.Created[0]
+
+	// findings are stored on ingest and returned with the cert
+	cert, err := certBakcend.GetCertByID(leaf.ID)
+	if err != nil || len(cert.LintFindings) != 1 || cert.LintFindings[0].RuleID != "tls_validity_too_long" {
+		t.Logf("Expecting lint finding on ingest got %v (%v)", cert, err)
+		t.FailNow()
+	}
+
+	// custom rules apply on demand
+	certBakcend.RegisterLintRules(&testLintRule{})
+	cert, err = certBakcend.LintCertificateByID(leaf.ID)
+	if err != nil || len(cert.LintFindings) != 2 {
+		t.Logf("Expecting 2 lint findings got %v (%v)", cert, err)
+		t.FailNow()
+	}
+
+	// only the certs linted before the custom rule are outdated
+	linted, err := certBakcend.LintOutdatedCertificates()
+	if err != nil || linted != 2 {
+		t.Logf("Expecting 2 outdated certs got %d (%v)", linted, err)
+		t.FailNow()
+	}
+
+	linted, err = certBakcend.LintOutdatedCertificates()
+	if err != nil || linted != 0 {
+		t.Logf("Expecting no outdated certs got %d (%v)", linted, err)
+		t.FailNow()
+	}
+
+	linted, err = certBakcend.LintCertificates()
+	if err != nil || linted != 3 {
+		t.Logf("Expecting 3 linted certs got %d (%v)", linted, err)
+		t.FailNow()
+	}
+
+	lintReport, err := certBakcend.ListLintFindings(LintFindingFilter{})
+	if err != nil || lintReport.Total != 4 || len(lintReport.Rules) != 2 {
+		t.Logf("Unexpected report %+v (%v)", lintReport, err)
+		t.FailNow()
+	}
+
+	// ordered by severity
+	if lintReport.Rules[0].RuleID != "tls_validity_too_long" || lintReport.Findings[0].Subject != leaf.Subject {
+		t.Logf("Unexpected report order %+v", lintReport.Rules[0])
+		t.FailNow()
+	}
+
+	lintReport, err = certBakcend.ListLintFindings(LintFindingFilter{RuleID: "custom", Severity: LintSeverityInfo})
+	if err != nil || lintReport.Total != 3 {
+		t.Logf("Unexpected report %+v (%v)", lintReport, err)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.ListLintFindings(LintFindingFilter{Severity: "fatal"})
+	if _, ok := err.(*DBObjectValidationError); !ok {
+		t.Logf("Expecting validation error got %v", err)
+		t.FailNow()
+	}
+
+	// the findings are deleted with the cert
+	err = certBakcend.DeleteCertByID(leaf.ID)
+	if err != nil {
+		t.Logf("Error deleting cert %s", err.Error())
+		t.FailNow()
+	}
+
+	lintReport, _ = certBakcend.ListLintFindings(LintFindingFilter{})
+	if lintReport.Total != 2 {
+		t.Logf("Expecting 2 findings after delete got %d", lintReport.Total)
+		t.FailNow()
+	}
+}
+
+func TestLintCertificatesRollback(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	leaf, err := certBakcend.CreateCertificate(testLeafPEM)
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	// the findings of the new rules cannot be stored
+	certBakcend.RegisterLintRules(&testLintRule{})
+	result := certBakcend.db.Exec("CREATE TRIGGER lint_fail BEFORE INSERT ON lint_findings BEGIN SELECT RAISE(ABORT, 'lint unavailable'); END")
+	if result.Error != nil {
+		t.Logf("Error creating trigger %s", result.Error.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.LintOutdatedCertificates()
+	if err == nil {
+		t.Logf("Expecting error storing the findings")
+		t.FailNow()
+	}
+
+	// the previous findings and version are kept
+	cert, err := certBakcend.GetCertByID(leaf.ID)
+	if err != nil || len(cert.LintFindings) != 1 || cert.LintVersion != leaf.LintVersion {
+		t.Logf("Expecting the findings rolled back got %v (%v)", cert, err)
+		t.FailNow()
+	}
+
+	// so the cert is still outdated
+	result = certBakcend.db.Exec("DROP TRIGGER lint_fail")
+	if result.Error != nil {
+		t.Logf("Error dropping trigger %s", result.Error.Error())
+		t.FailNow()
+	}
+
+	linted, err := certBakcend.LintOutdatedCertificates()
+	if err != nil || linted != 1 {
+		t.Logf("Expecting 1 outdated cert got %d (%v)", linted, err)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,190 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//
+// Lint functions
+// Create: RegisterLintRules
+// Read:   ListLintFindings
+// Update: LintCertificateByID, LintCertificates, LintOutdatedCertificates, lintCertificates,
+//         lintCertificate, replaceLintFindings
+//
+
+// RegisterLintRules add custom rules to the rules run against every certificate
+// a rule replaces the registered rule with the same ID
+func (certBackend *CertBackend) RegisterLintRules(rules ...LintRule) {
+	certBackend.linter.Register(rules...)
+}
+
+// LintCertificateByID run the lint rules against the cert (uuid) and store the findings
+func (certBackend *CertBackend) LintCertificateByID(uuid uuid.UUID) (*Certificate, error) {
+	certBackend.logger.Debug("LintCertificateByID: Linting cert...", "uuid", uuid)
+
+	cert, err := certBackend.GetCertByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = certBackend.replaceLintFindings(cert, certBackend.lintCertificate(cert))
+	if err != nil {
+		return nil, err
+	}
+
+	return cert, nil
+}
+
+// LintCertificates run the lint rules against all certs and store the findings
+// return the number of linted certs
+func (certBackend *CertBackend) LintCertificates() (int, error) {
+	certBackend.logger.Debug("LintCertificates: Linting certs...")
+
+	return certBackend.lintCertificates(certBackend.db)
+}
+
+// LintOutdatedCertificates run the lint rules against the certs linted by another
+// version of the rules (see Linter.Version) and store the findings
+// return the number of linted certs
+func (certBackend *CertBackend) LintOutdatedCertificates() (int, error) {
+	certBackend.logger.Debug("LintOutdatedCertificates: Linting certs...")
+
+	return certBackend.lintCertificates(certBackend.db.Where("lint_version IS NULL OR lint_version <> ?", certBackend.linter.Version()))
+}
+
+// lintCertificates run the lint rules against the certs of the query and store the findings
+func (certBackend *CertBackend) lintCertificates(query *gorm.DB) (int, error) {
+
+	var certList Certificates
+	result := query.Find(&certList)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	for _, c := range certList {
+		err := certBackend.replaceLintFindings(c, certBackend.lintCertificate(c))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(certList), nil
+}
+
+// ListLintFindings return the findings of all certs matching the filter
+// ordered by severity and rule with a count per rule
+func (certBackend *CertBackend) ListLintFindings(filter LintFindingFilter) (*LintReport, error) {
+	certBackend.logger.Debug("ListLintFindings: Listing findings...", "filter", filter)
+
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	query := certBackend.db.Table("lint_findings").
+		Joins("JOIN certificates ON certificates.id = lint_findings.certificate_id").
+		Where("certificates.deleted_at IS NULL")
+
+	if filter.RuleID != "" {
+		query = query.Where("lint_findings.rule_id = ?", filter.RuleID)
+	}
+
+	if filter.Severity != "" {
+		query = query.Where("lint_findings.severity = ?", filter.Severity)
+	}
+
+	report := NewLintReport()
+	result := query.
+		Select("lint_findings.*, certificates.sha256, certificates.subject").
+		Order("CASE lint_findings.severity WHEN 'error' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END").
+		Order("lint_findings.rule_id, certificates.subject").
+		Scan(&report.Findings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	summaries := map[string]*LintRuleSummary{}
+	for _, f := range report.Findings {
+		summary, ok := summaries[f.RuleID]
+		if !ok {
+			summary = &LintRuleSummary{RuleID: f.RuleID, Severity: f.Severity}
+			summaries[f.RuleID] = summary
+			report.Rules = append(report.Rules, summary)
+		}
+		summary.Count++
+	}
+	report.Total = len(report.Findings)
+
+	return report, nil
+}
+
+// lintCertificate return the findings of the lint rules for cert
+func (certBackend *CertBackend) lintCertificate(cert *Certificate) []LintFinding {
+
+	x509Cert, err := cert.ToX509()
+	if err != nil {
+		certBackend.logger.Error("lintCertificate: invalid PEM", "id", cert.ID, "err", err)
+		return []LintFinding{}
+	}
+
+	return certBackend.linter.Lint(x509Cert)
+}
+
+// replaceLintFindings replace the stored findings of cert and its lint version in one transaction
+func (certBackend *CertBackend) replaceLintFindings(cert *Certificate, findings []LintFinding) error {
+
+	for i := range findings {
+		findings[i].CertificateID = cert.ID
+	}
+
+	// the version of the rules of the findings
+	version := certBackend.linter.Version()
+
+	err := certBackend.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("certificate_id = ?", cert.ID).Delete(&LintFinding{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if len(findings) != 0 {
+			result = tx.Create(&findings)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		result = tx.Model(&Certificate{}).Where("id = ?", cert.ID).Update("lint_version", version)
+		return result.Error
+	})
+	if err != nil {
+		return err
+	}
+
+	cert.LintFindings = findings
+	cert.LintVersion = version
+	return nil
+}
+
+// LintFindingFilter filters for listing lint findings
+// empty fields are ignored
+type LintFindingFilter struct {
+	// the id of the rule
+	RuleID string
+	// the severity: info, warning or error
+	Severity LintSeverity
+}
+
+// Validate return an error if the filter is not valid
+func (filter *LintFindingFilter) Validate() error {
+
+	switch filter.Severity {
+	case "", LintSeverityInfo, LintSeverityWarning, LintSeverityError:
+	default:
+		return &DBObjectValidationError{Msg: fmt.Sprintf("invalid severity '%s'", filter.Severity)}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ListOptions defines the page and the order of a list
+type ListOptions struct {
+	// the max number of items (all items if 0)
+	Limit int
+	// the number of items to skip
+	Offset int
+	// the sort key, prefixed with '-' for a descending order (default order if empty)
+	Sort string
+}
+
+// sortKeys the sort keys of a list and their column (indexed columns only)
+type sortKeys map[string]string
+
+// names return the sorted names of the keys
+func (keys sortKeys) names() []string {
+
+	names := []string{}
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// validate return a DBObjectValidationError if the options are invalid for keys
+func (opts *ListOptions) validate(keys sortKeys) error {
+
+	if opts.Limit < 0 {
+		return &DBObjectValidationError{Msg: fmt.Sprintf("invalid limit '%d'", opts.Limit)}
+	}
+
+	if opts.Offset < 0 {
+		return &DBObjectValidationError{Msg: fmt.Sprintf("invalid offset '%d'", opts.Offset)}
+	}
+
+	if opts.Sort != "" {
+		if _, ok := keys[strings.TrimPrefix(opts.Sort, "-")]; !ok {
+			return &DBObjectValidationError{Msg: fmt.Sprintf("invalid sort '%s' expecting one of %s (prefixed with '-' for descending)",
+				opts.Sort, strings.Join(keys.names(), ", "))}
+		}
+	}
+
+	return nil
+}
+
+// apply add the order and the page to the query, the id of the table
+// orders the items with the same sort key so the pages are stable
+func (opts *ListOptions) apply(query *gorm.DB, keys sortKeys, table string, defaultSort string) *gorm.DB {
+
+	sortKey := opts.Sort
+	if sortKey == "" {
+		sortKey = defaultSort
+	}
+
+	direction := ""
+	if strings.HasPrefix(sortKey, "-") {
+		direction = " DESC"
+	}
+
+	query = query.Order(keys[strings.TrimPrefix(sortKey, "-")] + direction).Order(table + ".id" + direction)
+
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	if opts.Offset > 0 {
+		// sqlite does not accept an offset without a limit
+		if opts.Limit == 0 {
+			query = query.Limit(math.MaxInt32)
+		}
+		query = query.Offset(opts.Offset)
+	}
+
+	return query
+}
@@ -0,0 +1,101 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListCertsPage(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	for _, pem := range []string{testRootPEM, testIntermediatePEM, testLeafPEM, testRenewedLeafPEM} {
+		_, err := certBakcend.CreateCertificate(pem)
+		if err != nil {
+			t.Logf("Error creating cert %s", err.Error())
+			t.FailNow()
+		}
+	}
+
+	// the first page sorted by expiry
+	certs, total, err := certBakcend.ListCertsPage(CertFilter{}, ListOptions{Limit: 3, Sort: "not_after"})
+	if err != nil || total != 4 || len(certs) != 3 {
+		t.Logf("Expecting 3 of 4 certs got %d of %d (%v)", len(certs), total, err)
+		t.FailNow()
+	}
+
+	for i := 1; i < len(certs); i++ {
+		if certs[i].NotAfter.Before(certs[i-1].NotAfter) {
+			t.Logf("Expecting certs sorted by not_after")
+			t.FailNow()
+		}
+	}
+
+	// the lists do not return the PEM
+	for _, c := range certs {
+		if c.RawPEM != "" || c.SHA256 == "" || c.Tags == nil {
+			t.Logf("Expecting the cert without its PEM got %+v", c)
+			t.FailNow()
+		}
+	}
+
+	// the last page in the descending order is the first cert
+	last, _, err := certBakcend.ListCertsPage(CertFilter{}, ListOptions{Offset: 3, Sort: "-not_after"})
+	if err != nil || len(last) != 1 || last[0].ID != certs[0].ID {
+		t.Logf("Expecting the first cert on the last page got %d (%v)", len(last), err)
+		t.FailNow()
+	}
+
+	issuedAfter := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		filter CertFilter
+		total  int64
+	}{
+		{CertFilter{Subject: "*CN=www.example.com*"}, 2},
+		{CertFilter{Issuer: "*Acme Test Issuing CA*"}, 2},
+		{CertFilter{SignatureAlgorithm: "ECDSA-SHA256"}, 2},
+		{CertFilter{IssuedAfter: &issuedAfter}, 1},
+		{CertFilter{IssuedBefore: &issuedAfter}, 3},
+	}
+
+	for _, test := range tests {
+		certs, total, err := certBakcend.ListCertsPage(test.filter, ListOptions{Limit: 1})
+		if err != nil || total != test.total || len(certs) != 1 {
+			t.Logf("Expecting %d certs for %+v got %d of %d (%v)", test.total, test.filter, len(certs), total, err)
+			t.FailNow()
+		}
+	}
+
+	for _, invalid := range []ListOptions{{Sort: "raw_pem"}, {Limit: -1}, {Offset: -1}} {
+		_, _, err = certBakcend.ListCertsPage(CertFilter{}, invalid)
+		if _, ok := err.(*DBObjectValidationError); !ok {
+			t.Logf("Expecting validation error for %+v got %v", invalid, err)
+			t.FailNow()
+		}
+	}
+}
+
+func TestListTagsPage(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	for _, name := range []string{"web-prod", "web-dev", "db"} {
+		_, err := certBakcend.CreateTagWithDescription(name, "the "+name+" servers")
+		if err != nil {
+			t.Logf("Error creating tag %s", err.Error())
+			t.FailNow()
+		}
+	}
+
+	tags, total, err := certBakcend.ListTagsPage(TagFilter{Name: "web-*"}, ListOptions{Limit: 1, Sort: "-name"})
+	if err != nil || total != 2 || len(tags) != 1 || tags[0].Name != "web-prod" {
+		t.Logf("Expecting web-prod first of 2 tags got %v of %d (%v)", tags, total, err)
+		t.FailNow()
+	}
+
+	tags, total, err = certBakcend.ListTagsPage(TagFilter{Description: "*db*"}, ListOptions{})
+	if err != nil || total != 1 || len(tags) != 1 || tags[0].Name != "db" {
+		t.Logf("Expecting db tag got %v of %d (%v)", tags, total, err)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,16 @@
+package data
+
+// Models return all the models stored in the DB (used for migration)
+func Models() []interface{} {
+	return []interface{}{
+		&Tag{},
+		&TagAlias{},
+		&Certificate{},
+		&SubjectAltName{},
+		&Label{},
+		&AutoTagRule{},
+		&LintFinding{},
+		&NotificationDelivery{},
+		&AuditEvent{},
+	}
+}
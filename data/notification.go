@@ -0,0 +1,155 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationDelivery defines the delivery state of the expiry notification
+// of a certificate for a threshold through a channel
+// swagger:model
+type NotificationDelivery struct {
+	// the id for the delivery
+	//
+	// required: false
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primary_key;"`
+
+	// the id of the certificate of the notification
+	//
+	// required: false
+	CertificateID uuid.UUID `json:"certificate_id" gorm:"type:uuid;uniqueIndex:idx_notification_delivery"`
+
+	// the threshold in days before NotAfter that fired the notification
+	//
+	// required: false
+	Threshold int `json:"threshold" gorm:"uniqueIndex:idx_notification_delivery"`
+
+	// the name of the channel (e.g. webhook, smtp, slack, teams)
+	//
+	// required: false
+	Channel string `json:"channel" gorm:"uniqueIndex:idx_notification_delivery"`
+
+	// the number of delivery attempts
+	//
+	// required: false
+	Attempts int `json:"attempts"`
+
+	// the error of the last failed attempt
+	//
+	// required: false
+	LastError string `json:"last_error,omitempty"`
+
+	// the time the notification was delivered (empty if not delivered yet)
+	//
+	// required: false
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+
+	// the CreatedAt timestamp for the delivery
+	//
+	// required: false
+	CreatedAt time.Time `json:"-" `
+
+	// the UpdatedAt timestamp for the delivery
+	//
+	// required: false
+	UpdatedAt time.Time `json:"-" `
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (delivery *NotificationDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if delivery.ID == uuid.Nil {
+		uuid := uuid.New()
+		delivery.ID = uuid
+	}
+
+	return
+}
+
+// ParseNotificationThresholds parse comma separated thresholds in days (e.g. 60,30,7,1)
+// return the thresholds sorted from the largest to the smallest
+func ParseNotificationThresholds(thresholds string) ([]int, error) {
+
+	var days []int
+	for _, t := range strings.Split(thresholds, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+
+		d, err := strconv.Atoi(t)
+		if err != nil || d <= 0 {
+			return nil, &DBObjectValidationError{Msg: fmt.Sprintf("invalid notification threshold '%s'", t)}
+		}
+		days = append(days, d)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(days)))
+	return days, nil
+}
+
+// FormatNotificationThresholds return the thresholds as comma separated days
+func FormatNotificationThresholds(thresholds []int) string {
+
+	days := make([]string, 0, len(thresholds))
+	for _, d := range thresholds {
+		days = append(days, strconv.Itoa(d))
+	}
+
+	return strings.Join(days, ",")
+}
+
+// GetNotificationThresholds return the thresholds of the cert: the thresholds of
+// its tags that override them (all of them if several tags do) or defaults
+func (cert *Certificate) GetNotificationThresholds(defaults []int) []int {
+
+	seen := map[int]bool{}
+	var thresholds []int
+	for _, tag := range cert.Tags {
+		days, err := ParseNotificationThresholds(tag.NotificationThresholds)
+		if err != nil {
+			continue
+		}
+
+		for _, d := range days {
+			if !seen[d] {
+				seen[d] = true
+				thresholds = append(thresholds, d)
+			}
+		}
+	}
+
+	if len(thresholds) == 0 {
+		return defaults
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(thresholds)))
+	return thresholds
+}
+
+// GetDueNotificationThreshold return the smallest threshold crossed at now by the cert
+// and false if no threshold is crossed or the cert is expired
+func (cert *Certificate) GetDueNotificationThreshold(thresholds []int, now time.Time) (int, bool) {
+
+	if !now.Before(cert.NotAfter) {
+		return 0, false
+	}
+
+	due, found := 0, false
+	for _, days := range thresholds {
+		if now.AddDate(0, 0, days).Before(cert.NotAfter) {
+			continue
+		}
+
+		if !found || days < due {
+			due, found = days, true
+		}
+	}
+
+	return due, found
+}
@@ -0,0 +1,146 @@
+package data
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseNotificationThresholds(t *testing.T) {
+
+	days, err := ParseNotificationThresholds(" 7, 60,1 ,30")
+	if err != nil || FormatNotificationThresholds(days) != "60,30,7,1" {
+		t.Logf("Unexpected thresholds %v (%v)", days, err)
+		t.FailNow()
+	}
+
+	days, err = ParseNotificationThresholds("")
+	if err != nil || len(days) != 0 {
+		t.Logf("Expecting no thresholds got %v (%v)", days, err)
+		t.FailNow()
+	}
+
+	for _, invalid := range []string{"30,soon", "0", "-7"} {
+		_, err = ParseNotificationThresholds(invalid)
+		if _, ok := err.(*DBObjectValidationError); !ok {
+			t.Logf("Expecting validation error for '%s' got %v", invalid, err)
+			t.FailNow()
+		}
+	}
+}
+
+func TestGetDueNotificationThreshold(t *testing.T) {
+
+	notAfter := time.Date(2031, time.June, 1, 0, 0, 0, 0, time.UTC)
+	cert := &Certificate{NotAfter: notAfter}
+	thresholds := []int{60, 30, 7, 1}
+
+	tests := []struct {
+		now       time.Time
+		threshold int
+		due       bool
+	}{
+		{notAfter.AddDate(0, 0, -90), 0, false},
+		{notAfter.AddDate(0, 0, -60), 60, true},
+		{notAfter.AddDate(0, 0, -17), 30, true},
+		{notAfter.Add(-time.Hour), 1, true},
+		{notAfter, 0, false},
+	}
+
+	for _, test := range tests {
+		threshold, due := cert.GetDueNotificationThreshold(thresholds, test.now)
+		if threshold != test.threshold || due != test.due {
+			t.Logf("Expecting %d (%v) at %s, but got %d (%v)", test.threshold, test.due, test.now, threshold, due)
+			t.FailNow()
+		}
+	}
+
+	// tags override the defaults
+	cert.Tags = []Tag{{NotificationThresholds: "14"}, {NotificationThresholds: "90,14"}, {}}
+	if got := FormatNotificationThresholds(cert.GetNotificationThresholds(thresholds)); got != "90,14" {
+		t.Logf("Expecting tag thresholds, but got %s", got)
+		t.FailNow()
+	}
+}
+
+func TestNotificationDelivery(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	leaf, err := certBakcend.CreateCertificate(testLeafPEM)
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	now := time.Date(2031, time.May, 15, 0, 0, 0, 0, time.UTC)
+
+	// a failed attempt is not a delivery
+	delivery, err := certBakcend.RecordNotificationDelivery(leaf.ID, 30, "webhook", errors.New("timeout"), now)
+	if err != nil || delivery.Attempts != 1 || delivery.DeliveredAt != nil || delivery.LastError != "timeout" {
+		t.Logf("Unexpected delivery %+v (%v)", delivery, err)
+		t.FailNow()
+	}
+
+	delivery, err = certBakcend.RecordNotificationDelivery(leaf.ID, 30, "webhook", nil, now)
+	if err != nil || delivery.Attempts != 2 || delivery.DeliveredAt == nil || delivery.LastError != "" {
+		t.Logf("Unexpected delivery %+v (%v)", delivery, err)
+		t.FailNow()
+	}
+
+	deliveries, err := certBakcend.ListNotificationDeliveriesByCertID(leaf.ID)
+	if err != nil || len(deliveries) != 1 {
+		t.Logf("Expecting 1 delivery got %v (%v)", deliveries, err)
+		t.FailNow()
+	}
+
+	// tag thresholds
+	tag, err := certBakcend.CreateTag("critical")
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.SetTagNotificationThresholdsByID(tag.ID, "soon")
+	if _, ok := err.(*DBObjectValidationError); !ok {
+		t.Logf("Expecting validation error got %v", err)
+		t.FailNow()
+	}
+
+	tag, err = certBakcend.SetTagNotificationThresholdsByID(tag.ID, "3,120")
+	if err != nil || tag.NotificationThresholds != "120,3" {
+		t.Logf("Unexpected tag thresholds %v (%v)", tag, err)
+		t.FailNow()
+	}
+
+	max, err := certBakcend.GetMaxNotificationThreshold([]int{60, 30, 7, 1})
+	if err != nil || max != 120 {
+		t.Logf("Expecting max threshold 120 got %d (%v)", max, err)
+		t.FailNow()
+	}
+
+	// the deliveries are kept while the cert is in the trash and purged with it
+	err = certBakcend.DeleteCertByID(leaf.ID)
+	if err != nil {
+		t.Logf("Error deleting cert %s", err.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.GetNotificationDelivery(leaf.ID, 30, "webhook")
+	if err != nil {
+		t.Logf("Expecting delivery of trashed cert got %v", err)
+		t.FailNow()
+	}
+
+	err = certBakcend.DeleteCertPendingRecords()
+	if err != nil {
+		t.Logf("Error purging certs %s", err.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.GetNotificationDelivery(leaf.ID, 30, "webhook")
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting delivery not found got %v", err)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,116 @@
+package data
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//
+// Notification functions
+// Read:   GetNotificationDelivery, ListNotificationDeliveriesByCertID, GetMaxNotificationThreshold
+// Update: RecordNotificationDelivery
+//
+
+// GetNotificationDelivery return the delivery state of the notification of
+// the cert (uuid) for threshold through channel
+func (certBackend *CertBackend) GetNotificationDelivery(uuid uuid.UUID, threshold int, channel string) (*NotificationDelivery, error) {
+
+	var delivery NotificationDelivery
+	result := certBackend.db.
+		Where("certificate_id = ? AND threshold = ? AND channel = ?", uuid, threshold, channel).
+		First(&delivery)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, &DBObjectNotFound{Err: result.Error, ID: uuid.String()}
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &delivery, nil
+}
+
+// ListNotificationDeliveriesByCertID return the delivery states of the notifications of the cert (uuid)
+func (certBackend *CertBackend) ListNotificationDeliveriesByCertID(uuid uuid.UUID) ([]*NotificationDelivery, error) {
+	certBackend.logger.Debug("ListNotificationDeliveriesByCertID: Listing deliveries...", "uuid", uuid)
+
+	// check the cert exists
+	_, err := certBackend.GetCertByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []*NotificationDelivery{}
+	result := certBackend.db.Where("certificate_id = ?", uuid).Order("threshold DESC, channel").Find(&deliveries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return deliveries, nil
+}
+
+// RecordNotificationDelivery record an attempt to deliver the notification of the cert (uuid)
+// for threshold through channel, sendErr is the error of the attempt (nil if delivered)
+func (certBackend *CertBackend) RecordNotificationDelivery(uuid uuid.UUID, threshold int, channel string, sendErr error, now time.Time) (*NotificationDelivery, error) {
+
+	delivery, err := certBackend.GetNotificationDelivery(uuid, threshold, channel)
+	if err != nil {
+		if _, ok := err.(*DBObjectNotFound); !ok {
+			return nil, err
+		}
+
+		delivery = &NotificationDelivery{
+			CertificateID: uuid,
+			Threshold:     threshold,
+			Channel:       channel,
+		}
+	}
+
+	delivery.Attempts++
+	if sendErr != nil {
+		delivery.LastError = sendErr.Error()
+	} else {
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	}
+
+	result := certBackend.db.Save(delivery)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return delivery, nil
+}
+
+// GetMaxNotificationThreshold return the largest of the default thresholds and the thresholds of the tags
+func (certBackend *CertBackend) GetMaxNotificationThreshold(defaults []int) (int, error) {
+
+	var tagThresholds []string
+	result := certBackend.db.Model(&Tag{}).Where("notification_thresholds <> ''").Pluck("notification_thresholds", &tagThresholds)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	max := 0
+	for _, d := range defaults {
+		if d > max {
+			max = d
+		}
+	}
+
+	for _, t := range tagThresholds {
+		days, err := ParseNotificationThresholds(t)
+		if err != nil {
+			continue
+		}
+
+		// sorted from the largest
+		if len(days) != 0 && days[0] > max {
+			max = days[0]
+		}
+	}
+
+	return max, nil
+}
@@ -0,0 +1,90 @@
+package data
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// RenewalPolicy defines when a certificate is the renewal of another one:
+// same subject and same SANs, a validity starting before the end of the
+// validity of the renewed cert (plus MaxGap) and ending after it
+type RenewalPolicy struct {
+	// the max time between the NotAfter of a cert and the NotBefore of its renewal
+	MaxGap time.Duration
+	// if the renewal must have the same public key
+	RequireSameKey bool
+}
+
+// CertificateRenewalPolicy the policy used to link the renewed certificates
+var CertificateRenewalPolicy = RenewalPolicy{
+	MaxGap: 24 * time.Hour,
+}
+
+// IsRenewal return true if cert is a renewal of predecessor
+func (policy RenewalPolicy) IsRenewal(predecessor *Certificate, cert *Certificate) bool {
+
+	if predecessor.ID == cert.ID || predecessor.Subject != cert.Subject {
+		return false
+	}
+
+	if renewalSANKey(predecessor.SubjectAltNames) != renewalSANKey(cert.SubjectAltNames) {
+		return false
+	}
+
+	if policy.RequireSameKey && predecessor.SPKISHA256 != cert.SPKISHA256 {
+		return false
+	}
+
+	// overlapping or adjacent validity, the renewal expires later
+	if cert.NotBefore.Before(predecessor.NotBefore) || !cert.NotAfter.After(predecessor.NotAfter) {
+		return false
+	}
+
+	return !cert.NotBefore.After(predecessor.NotAfter.Add(policy.MaxGap))
+}
+
+// linkRenewals return the predecessor of each cert of the family (same subject and SANs)
+// each cert has at most one predecessor and one successor: the latest cert it renews
+func (policy RenewalPolicy) linkRenewals(family Certificates) map[*Certificate]*Certificate {
+
+	sorted := append(Certificates{}, family...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].NotBefore.Equal(sorted[j].NotBefore) {
+			return sorted[i].NotBefore.Before(sorted[j].NotBefore)
+		}
+		if !sorted[i].NotAfter.Equal(sorted[j].NotAfter) {
+			return sorted[i].NotAfter.Before(sorted[j].NotAfter)
+		}
+		return sorted[i].ID.String() < sorted[j].ID.String()
+	})
+
+	predecessors := map[*Certificate]*Certificate{}
+	renewed := map[*Certificate]bool{}
+
+	for i, cert := range sorted {
+		for j := i - 1; j >= 0; j-- {
+			if renewed[sorted[j]] || !policy.IsRenewal(sorted[j], cert) {
+				continue
+			}
+
+			predecessors[cert] = sorted[j]
+			renewed[sorted[j]] = true
+			break
+		}
+	}
+
+	return predecessors
+}
+
+// renewalSANKey return the SANs as a sorted list (the order in the cert does not matter)
+func renewalSANKey(sans []SubjectAltName) string {
+
+	values := []string{}
+	for _, san := range sans {
+		values = append(values, san.Type+":"+strings.ToLower(san.Value))
+	}
+	sort.Strings(values)
+
+	return strings.Join(values, "|")
+}
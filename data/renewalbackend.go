@@ -0,0 +1,158 @@
+package data
+
+import (
+	"github.com/google/uuid"
+)
+
+//
+// Renewal functions
+// Read:    GetCertLineageByID
+// Resolve: ResolveCertRenewals, resolveCertRenewals, linkRenewalFamily
+//
+
+// GetCertLineageByID return the history of the logical certificate of the cert (uuid):
+// its predecessors and its successors (the first issued first)
+func (certBackend *CertBackend) GetCertLineageByID(uuid uuid.UUID) (Certificates, error) {
+	certBackend.logger.Debug("GetCertLineageByID: Getting lineage...", "uuid", uuid)
+
+	cert, err := certBackend.GetCertByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// the predecessors (the links are checked to avoid looping on inconsistent data)
+	lineage := Certificates{cert}
+	seen := map[string]bool{cert.ID.String(): true}
+	for current := cert; current.PredecessorID != nil && !seen[current.PredecessorID.String()]; {
+		predecessor, err := certBackend.GetCertByID(*current.PredecessorID)
+		if err != nil {
+			break
+		}
+
+		seen[predecessor.ID.String()] = true
+		lineage = append(Certificates{predecessor}, lineage...)
+		current = predecessor
+	}
+
+	// the successors
+	for current := cert; current.SuccessorID != nil && !seen[current.SuccessorID.String()]; {
+		successor, err := certBackend.GetCertByID(*current.SuccessorID)
+		if err != nil {
+			break
+		}
+
+		seen[successor.ID.String()] = true
+		lineage = append(lineage, successor)
+		current = successor
+	}
+
+	return lineage, nil
+}
+
+// ResolveCertRenewals link the renewed certs of all stored certs
+func (certBackend *CertBackend) ResolveCertRenewals() error {
+	certBackend.logger.Debug("ResolveCertRenewals: Resolving renewals...")
+
+	var certList Certificates
+	result := certBackend.db.Preload("SubjectAltNames").Find(&certList)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	families := map[string]Certificates{}
+	for _, c := range certList {
+		key := c.Subject + "\n" + renewalSANKey(c.SubjectAltNames)
+		families[key] = append(families[key], c)
+	}
+
+	for _, family := range families {
+		err := certBackend.linkRenewalFamily(family)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveCertRenewals link again the stored certs with the subject and SANs of cert
+// (cert must have its SubjectAltNames)
+func (certBackend *CertBackend) resolveCertRenewals(cert *Certificate) error {
+
+	var candidates Certificates
+	result := certBackend.db.Preload("SubjectAltNames").Where("subject = ?", cert.Subject).Find(&candidates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	key := renewalSANKey(cert.SubjectAltNames)
+
+	var family Certificates
+	for _, c := range candidates {
+		if renewalSANKey(c.SubjectAltNames) == key {
+			family = append(family, c)
+		}
+	}
+
+	err := certBackend.linkRenewalFamily(family)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range family {
+		if c.ID == cert.ID {
+			cert.PredecessorID = c.PredecessorID
+			cert.SuccessorID = c.SuccessorID
+			cert.Replaced = c.Replaced
+		}
+	}
+
+	return nil
+}
+
+// linkRenewalFamily store the predecessor and successor links of the certs with the same subject and SANs
+func (certBackend *CertBackend) linkRenewalFamily(family Certificates) error {
+
+	predecessors := CertificateRenewalPolicy.linkRenewals(family)
+
+	successors := map[*Certificate]*Certificate{}
+	for cert, predecessor := range predecessors {
+		successors[predecessor] = cert
+	}
+
+	for _, c := range family {
+		var predecessorID, successorID *uuid.UUID
+		if p, ok := predecessors[c]; ok {
+			predecessorID = &p.ID
+		}
+		if s, ok := successors[c]; ok {
+			successorID = &s.ID
+		}
+
+		if sameUUID(c.PredecessorID, predecessorID) && sameUUID(c.SuccessorID, successorID) {
+			continue
+		}
+
+		certBackend.logger.Debug("linkRenewalFamily: linking renewal", "cert", c.ID, "predecessor", predecessorID, "successor", successorID)
+		result := certBackend.db.Model(&Certificate{}).Where("id = ?", c.ID).
+			Updates(map[string]interface{}{"predecessor_id": predecessorID, "successor_id": successorID})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		c.PredecessorID = predecessorID
+		c.SuccessorID = successorID
+		c.Replaced = successorID != nil
+	}
+
+	return nil
+}
+
+// sameUUID return true if a and b are both nil or the same id
+func sameUUID(a *uuid.UUID, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return *a == *b
+}
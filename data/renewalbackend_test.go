@@ -0,0 +1,163 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenewalPolicy(t *testing.T) {
+
+	leaf, err := NewCertificate(testLeafPEM)
+	if err != nil {
+		t.Logf("Error parsing cert %s", err.Error())
+		t.FailNow()
+	}
+
+	renewed, err := NewCertificate(testRenewedLeafPEM)
+	if err != nil {
+		t.Logf("Error parsing cert %s", err.Error())
+		t.FailNow()
+	}
+	leaf.ID[0], renewed.ID[0] = 1, 2
+
+	// a renewal starting after the end of the validity of the leaf
+	late := *renewed
+	late.NotBefore = leaf.NotAfter.Add(48 * time.Hour)
+
+	// a renewal with another key
+	otherKey := *renewed
+	otherKey.SPKISHA256 = "other"
+
+	// a cert with other SANs
+	otherSANs := *renewed
+	otherSANs.SubjectAltNames = renewed.SubjectAltNames[1:]
+
+	tests := []struct {
+		name        string
+		policy      RenewalPolicy
+		predecessor *Certificate
+		cert        *Certificate
+		isRenewal   bool
+	}{
+		{"overlapping", RenewalPolicy{}, leaf, renewed, true},
+		{"reversed", RenewalPolicy{}, renewed, leaf, false},
+		{"gap", RenewalPolicy{MaxGap: 24 * time.Hour}, leaf, &late, false},
+		{"gap allowed", RenewalPolicy{MaxGap: 72 * time.Hour}, leaf, &late, true},
+		{"other key", RenewalPolicy{}, leaf, &otherKey, true},
+		{"other key required same", RenewalPolicy{RequireSameKey: true}, leaf, &otherKey, false},
+		{"same key required same", RenewalPolicy{RequireSameKey: true}, leaf, renewed, true},
+		{"other SANs", RenewalPolicy{}, leaf, &otherSANs, false},
+	}
+
+	for _, test := range tests {
+		if test.policy.IsRenewal(test.predecessor, test.cert) != test.isRenewal {
+			t.Logf("Expecting %s renewal %v", test.name, test.isRenewal)
+			t.FailNow()
+		}
+	}
+}
+
+func TestRenewalLineage(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	// the renewal is uploaded before the renewed cert
+	renewed, err := certBakcend.CreateCertificate(testRenewedLeafPEM)
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	leaf, err := certBakcend.CreateCertificate(testLeafPEM)
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	if leaf.SuccessorID == nil || *leaf.SuccessorID != renewed.ID || !leaf.Replaced {
+		t.Logf("Expecting leaf replaced by %s, got %v", renewed.ID, leaf.SuccessorID)
+		t.FailNow()
+	}
+
+	renewed, _ = certBakcend.GetCertByID(renewed.ID)
+	if renewed.PredecessorID == nil || *renewed.PredecessorID != leaf.ID || renewed.Replaced {
+		t.Logf("Expecting renewal of %s, got %v", leaf.ID, renewed.PredecessorID)
+		t.FailNow()
+	}
+
+	// the same lineage from both certs
+	for _, id := range []*Certificate{leaf, renewed} {
+		lineage, err := certBakcend.GetCertLineageByID(id.ID)
+		if err != nil || len(lineage) != 2 || lineage[0].ID != leaf.ID || lineage[1].ID != renewed.ID {
+			t.Logf("Unexpected lineage of %s: %v (%v)", id.ID, lineage, err)
+			t.FailNow()
+		}
+	}
+
+	// the other certs have no renewal
+	ca, _ := certBakcend.CreateCertificate(testIntermediatePEM)
+	lineage, err := certBakcend.GetCertLineageByID(ca.ID)
+	if err != nil || len(lineage) != 1 {
+		t.Logf("Expecting lineage of the CA only got %d (%v)", len(lineage), err)
+		t.FailNow()
+	}
+
+	replaced := true
+	certs, err := certBakcend.ListCertsWithFilter(CertFilter{Replaced: &replaced})
+	if err != nil || len(certs) != 1 || certs[0].ID != leaf.ID {
+		t.Logf("Expecting leaf as only replaced cert got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+
+	replaced = false
+	certs, _ = certBakcend.ListCertsWithFilter(CertFilter{Replaced: &replaced})
+	if len(certs) != 2 {
+		t.Logf("Expecting 2 not replaced certs got %d", len(certs))
+		t.FailNow()
+	}
+
+	// a deleted renewal no longer replaces the leaf
+	err = certBakcend.DeleteCertByID(renewed.ID)
+	if err != nil {
+		t.Logf("Error deleting cert %s", err.Error())
+		t.FailNow()
+	}
+
+	leaf, _ = certBakcend.GetCertByID(leaf.ID)
+	if leaf.SuccessorID != nil || leaf.Replaced {
+		t.Logf("Expecting leaf not replaced after delete got %v", leaf.SuccessorID)
+		t.FailNow()
+	}
+
+	// the restored renewal replaces it again
+	renewed, err = certBakcend.RestoreCertByID(renewed.ID)
+	if err != nil {
+		t.Logf("Error restoring cert %s", err.Error())
+		t.FailNow()
+	}
+
+	leaf, _ = certBakcend.GetCertByID(leaf.ID)
+	if leaf.SuccessorID == nil || *leaf.SuccessorID != renewed.ID || renewed.PredecessorID == nil {
+		t.Logf("Expecting leaf replaced after restore got %v", leaf.SuccessorID)
+		t.FailNow()
+	}
+
+	// the links are rebuilt from scratch
+	result := certBakcend.db.Exec("UPDATE certificates SET predecessor_id = NULL, successor_id = NULL")
+	if result.Error != nil {
+		t.Logf("Error clearing links %s", result.Error.Error())
+		t.FailNow()
+	}
+
+	err = certBakcend.ResolveCertRenewals()
+	if err != nil {
+		t.Logf("Error resolving renewals %s", err.Error())
+		t.FailNow()
+	}
+
+	leaf, _ = certBakcend.GetCertByID(leaf.ID)
+	if leaf.SuccessorID == nil || *leaf.SuccessorID != renewed.ID {
+		t.Logf("Expecting leaf replaced after resolve got %v", leaf.SuccessorID)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,52 @@
+package data
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SAN types
+const (
+	// SANTypeDNS a dNSName SAN
+	SANTypeDNS = "dns"
+	// SANTypeIP an iPAddress SAN
+	SANTypeIP = "ip"
+	// SANTypeEmail a rfc822Name SAN
+	SANTypeEmail = "email"
+	// SANTypeURI an uniformResourceIdentifier SAN
+	SANTypeURI = "uri"
+)
+
+// SubjectAltName defines a Subject Alternative Name of a X509 Cert
+// swagger:model
+type SubjectAltName struct {
+	// the id for the SAN
+	//
+	// required: false
+	ID uuid.UUID `json:"-" gorm:"type:uuid;primary_key;"`
+
+	// the id of the certificate of the SAN
+	//
+	// required: false
+	CertificateID uuid.UUID `json:"-" gorm:"type:uuid;index"`
+
+	// the type of the SAN: dns, ip, email or uri
+	//
+	// required: false
+	Type string `json:"type" gorm:"index:idx_san_type_value"`
+
+	// the value of the SAN
+	//
+	// required: false
+	Value string `json:"value" gorm:"index:idx_san_type_value"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (san *SubjectAltName) BeforeCreate(tx *gorm.DB) (err error) {
+	if san.ID == uuid.Nil {
+		uuid := uuid.New()
+		san.ID = uuid
+	}
+
+	return
+}
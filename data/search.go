@@ -0,0 +1,586 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Certificate search query language
+//
+//	query     = or
+//	or        = and { "OR" and }
+//	and       = not { [ "AND" ] not }
+//	not       = "NOT" not | "(" or ")" | condition
+//	condition = field operator value
+//	operator  = ":" | "=" | "!=" | "<" | "<=" | ">" | ">="
+//	value     = word | '"' quoted '"'
+//
+// e.g. san:*.example.com AND issuer.o:"Let's Encrypt" AND not_after<2026-12-01 AND tag:prod AND NOT tag:decommissioned
+//
+// ':' matches the value where '*' matches any sequence of characters, '=' and '!=' compare the
+// exact value, the comparisons are only valid on the dates (2006-01-02 or RFC3339) and the numbers
+// the keywords are case insensitive
+
+// maxSearchLength the max length of a search query
+const maxSearchLength = 2048
+
+// maxSearchDepth the max nesting of NOT and parentheses of a search query
+const maxSearchDepth = 32
+
+// searchFieldKind how a field is compared
+type searchFieldKind int
+
+const (
+	// searchText a text column
+	searchText searchFieldKind = iota
+	// searchList a '|' separated list column
+	searchList
+	// searchDate a time column
+	searchDate
+	// searchNumber an integer column
+	searchNumber
+	// searchBool a boolean column
+	searchBool
+	// searchSAN the SANs of the cert (column is the SAN type, any type if empty)
+	searchSAN
+	// searchTag the tags of the cert
+	searchTag
+	// searchStatus the validity status of the cert
+	searchStatus
+	// searchReplaced if the cert was replaced by a renewal
+	searchReplaced
+)
+
+// searchField a field of the search query language
+type searchField struct {
+	kind   searchFieldKind
+	column string
+}
+
+// searchFields the fields of the search query language
+var searchFields = map[string]searchField{
+	"subject":       {searchText, "certificates.subject"},
+	"issuer":        {searchText, "certificates.issuer"},
+	"serial":        {searchText, "certificates.serial_number"},
+	"sha256":        {searchText, "certificates.sha256"},
+	"sha1":          {searchText, "certificates.sha1"},
+	"md5":           {searchText, "certificates.md5"},
+	"spki_sha256":   {searchText, "certificates.spki_sha256"},
+	"sigalg":        {searchText, "certificates.signature_algorithm"},
+	"key":           {searchText, "certificates.public_key_algorithm"},
+	"key_curve":     {searchText, "certificates.public_key_curve"},
+	"key_size":      {searchNumber, "certificates.public_key_size"},
+	"key_usage":     {searchList, "certificates.key_usage"},
+	"ext_key_usage": {searchList, "certificates.ext_key_usage"},
+	"not_before":    {searchDate, "certificates.not_before"},
+	"not_after":     {searchDate, "certificates.not_after"},
+	"created_at":    {searchDate, "certificates.created_at"},
+	"is_ca":         {searchBool, "certificates.is_ca"},
+	"self_signed":   {searchBool, "certificates.is_self_signed"},
+	"max_path_len":  {searchNumber, "certificates.max_path_len"},
+	"san":           {searchSAN, ""},
+	"san.dns":       {searchSAN, SANTypeDNS},
+	"san.ip":        {searchSAN, SANTypeIP},
+	"san.email":     {searchSAN, SANTypeEmail},
+	"san.uri":       {searchSAN, SANTypeURI},
+	"tag":           {searchTag, ""},
+	"status":        {searchStatus, ""},
+	"replaced":      {searchReplaced, ""},
+}
+
+func init() {
+	// the DN attributes of the subject and the issuer
+	for _, dn := range []string{"subject", "issuer"} {
+		for attribute, column := range map[string]string{
+			"cn":            "common_name",
+			"o":             "organization",
+			"ou":            "organizational_unit",
+			"c":             "country",
+			"l":             "locality",
+			"st":            "province",
+			"serial_number": "serial_number",
+		} {
+			searchFields[dn+"."+attribute] = searchField{searchList, "certificates." + dn + "_" + column}
+		}
+	}
+}
+
+// SearchFieldNames return the sorted names of the fields of the search query language
+func SearchFieldNames() []string {
+
+	names := []string{}
+	for name := range searchFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// searchCondition a SQL condition on the certificates table with its arguments
+type searchCondition struct {
+	clause string
+	args   []interface{}
+}
+
+// searchTokenKind the kind of a token of a search query
+type searchTokenKind int
+
+const (
+	searchTokenEOF searchTokenKind = iota
+	searchTokenLParen
+	searchTokenRParen
+	searchTokenAnd
+	searchTokenOr
+	searchTokenNot
+	searchTokenTerm
+)
+
+// searchToken a token of a search query, the field, operator and value of a term
+type searchToken struct {
+	kind  searchTokenKind
+	pos   int
+	field string
+	op    string
+	value string
+}
+
+// describe return the token as shown in an error
+func (token searchToken) describe() string {
+
+	switch token.kind {
+	case searchTokenEOF:
+		return "end of query"
+	case searchTokenLParen:
+		return "'('"
+	case searchTokenRParen:
+		return "')'"
+	case searchTokenAnd:
+		return "AND"
+	case searchTokenOr:
+		return "OR"
+	case searchTokenNot:
+		return "NOT"
+	}
+
+	return fmt.Sprintf("'%s%s%s'", token.field, token.op, token.value)
+}
+
+// newSearchError return a DBObjectValidationError for the problem at pos (0 based) of the query
+func newSearchError(pos int, format string, a ...interface{}) error {
+	return &DBObjectValidationError{Msg: fmt.Sprintf("invalid search at position %d: %s", pos+1, fmt.Sprintf(format, a...))}
+}
+
+// isSearchFieldRune return true if r can be part of a field name
+func isSearchFieldRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_'
+}
+
+// isSearchValueEnd return true if r ends a value that is not quoted
+func isSearchValueEnd(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+}
+
+// lexSearch split the query into tokens
+func lexSearch(query string) ([]searchToken, error) {
+
+	runes := []rune(query)
+	tokens := []searchToken{}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, searchToken{kind: searchTokenLParen, pos: i})
+			i++
+			continue
+		case r == ')':
+			tokens = append(tokens, searchToken{kind: searchTokenRParen, pos: i})
+			i++
+			continue
+		case !isSearchFieldRune(r):
+			return nil, newSearchError(i, "unexpected character '%c'", r)
+		}
+
+		// a keyword or a field
+		start := i
+		for i < len(runes) && isSearchFieldRune(runes[i]) {
+			i++
+		}
+		word := string(runes[start:i])
+
+		if i == len(runes) || !strings.ContainsRune(":=!<>", runes[i]) {
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, searchToken{kind: searchTokenAnd, pos: start})
+			case "OR":
+				tokens = append(tokens, searchToken{kind: searchTokenOr, pos: start})
+			case "NOT":
+				tokens = append(tokens, searchToken{kind: searchTokenNot, pos: start})
+			default:
+				return nil, newSearchError(start, "expected field:value, AND, OR or NOT but got '%s'", word)
+			}
+			continue
+		}
+
+		// the operator
+		token := searchToken{kind: searchTokenTerm, pos: start, field: strings.ToLower(word)}
+		opStart := i
+		switch runes[i] {
+		case ':', '=':
+			i++
+		case '!':
+			i++
+			if i == len(runes) || runes[i] != '=' {
+				return nil, newSearchError(opStart, "expected '!=' after '%s'", word)
+			}
+			i++
+		case '<', '>':
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				i++
+			}
+		}
+		token.op = string(runes[opStart:i])
+
+		// the value
+		if i < len(runes) && runes[i] == '"' {
+			var value strings.Builder
+			quote := i
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, newSearchError(quote, "missing closing quote")
+			}
+			i++
+			token.value = value.String()
+		} else {
+			valueStart := i
+			for i < len(runes) && !isSearchValueEnd(runes[i]) {
+				i++
+			}
+			token.value = string(runes[valueStart:i])
+			if token.value == "" {
+				return nil, newSearchError(valueStart, "expected a value after '%s%s'", word, token.op)
+			}
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return append(tokens, searchToken{kind: searchTokenEOF, pos: len(runes)}), nil
+}
+
+// searchParser a recursive descent parser of the tokens of a search query
+type searchParser struct {
+	tokens []searchToken
+	next   int
+	depth  int
+	now    time.Time
+}
+
+// peek return the next token
+func (p *searchParser) peek() searchToken {
+	return p.tokens[p.next]
+}
+
+// pop return the next token and move to the following one
+func (p *searchParser) pop() searchToken {
+	token := p.tokens[p.next]
+	if token.kind != searchTokenEOF {
+		p.next++
+	}
+	return token
+}
+
+// parseOr parse: and { "OR" and }
+func (p *searchParser) parseOr() (*searchCondition, error) {
+
+	conditions := []*searchCondition{}
+	for {
+		condition, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+
+		if p.peek().kind != searchTokenOr {
+			return joinSearchConditions(conditions, " OR "), nil
+		}
+		p.pop()
+	}
+}
+
+// parseAnd parse: not { [ "AND" ] not }
+func (p *searchParser) parseAnd() (*searchCondition, error) {
+
+	conditions := []*searchCondition{}
+	for {
+		condition, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+
+		switch p.peek().kind {
+		case searchTokenAnd:
+			p.pop()
+		case searchTokenNot, searchTokenLParen, searchTokenTerm:
+			// implicit AND
+		default:
+			return joinSearchConditions(conditions, " AND "), nil
+		}
+	}
+}
+
+// parseNot parse: "NOT" not | "(" or ")" | condition
+func (p *searchParser) parseNot() (*searchCondition, error) {
+
+	token := p.pop()
+
+	switch token.kind {
+	case searchTokenNot, searchTokenLParen:
+		p.depth++
+		if p.depth > maxSearchDepth {
+			return nil, newSearchError(token.pos, "too many nested NOT and parentheses (max %d)", maxSearchDepth)
+		}
+		defer func() { p.depth-- }()
+	}
+
+	switch token.kind {
+	case searchTokenNot:
+		condition, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &searchCondition{clause: "NOT " + condition.clause, args: condition.args}, nil
+
+	case searchTokenLParen:
+		condition, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.pop(); closing.kind != searchTokenRParen {
+			return nil, newSearchError(closing.pos, "expected ')' to close '(' at position %d but got %s", token.pos+1, closing.describe())
+		}
+		return condition, nil
+
+	case searchTokenTerm:
+		return newSearchTermCondition(token, p.now)
+	}
+
+	return nil, newSearchError(token.pos, "expected field:value, NOT or '(' but got %s", token.describe())
+}
+
+// joinSearchConditions join the conditions with the operator (in parentheses)
+func joinSearchConditions(conditions []*searchCondition, operator string) *searchCondition {
+
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+
+	clauses := []string{}
+	args := []interface{}{}
+	for _, c := range conditions {
+		clauses = append(clauses, c.clause)
+		args = append(args, c.args...)
+	}
+
+	return &searchCondition{clause: "(" + strings.Join(clauses, operator) + ")", args: args}
+}
+
+// parseCertSearch parse the search query into a condition on the certificates table
+// the status of the certs is evaluated at now
+func parseCertSearch(query string, now time.Time) (*searchCondition, error) {
+
+	if strings.TrimSpace(query) == "" {
+		return nil, &DBObjectValidationError{Msg: "empty search"}
+	}
+
+	if len(query) > maxSearchLength {
+		return nil, &DBObjectValidationError{Msg: fmt.Sprintf("search too long (max %d characters)", maxSearchLength)}
+	}
+
+	tokens, err := lexSearch(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &searchParser{tokens: tokens, now: now}
+	condition, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if token := p.peek(); token.kind != searchTokenEOF {
+		return nil, newSearchError(token.pos, "unexpected %s", token.describe())
+	}
+
+	return condition, nil
+}
+
+// newSearchTermCondition return the condition of a field operator value term
+func newSearchTermCondition(token searchToken, now time.Time) (*searchCondition, error) {
+
+	field, ok := searchFields[token.field]
+	if !ok {
+		return nil, newSearchError(token.pos, "unknown field '%s' expecting one of %s", token.field, strings.Join(SearchFieldNames(), ", "))
+	}
+
+	// the comparisons are only valid on ordered fields
+	switch token.op {
+	case "<", "<=", ">", ">=":
+		if field.kind != searchDate && field.kind != searchNumber {
+			return nil, newSearchError(token.pos, "operator '%s' is not valid on '%s' (only ':', '=' and '!=')", token.op, token.field)
+		}
+	}
+
+	negate := token.op == "!="
+	var condition *searchCondition
+
+	switch field.kind {
+	case searchText:
+		if token.op == ":" {
+			condition = &searchCondition{clause: field.column + " LIKE ? ESCAPE '\\'", args: []interface{}{toLikePattern(token.value)}}
+		} else {
+			condition = &searchCondition{clause: field.column + " = ?", args: []interface{}{token.value}}
+		}
+
+	case searchList:
+		condition = &searchCondition{clause: listContainsClause(field.column), args: []interface{}{listContainsPattern(token.value)}}
+
+	case searchDate:
+		return newSearchDateCondition(token, field.column)
+
+	case searchNumber:
+		n, err := strconv.Atoi(token.value)
+		if err != nil {
+			return nil, newSearchError(token.pos, "invalid number '%s' for '%s'", token.value, token.field)
+		}
+		op := token.op
+		switch op {
+		case ":":
+			op = "="
+		case "!=":
+			op, negate = "=", true
+		}
+		condition = &searchCondition{clause: field.column + " " + op + " ?", args: []interface{}{n}}
+
+	case searchBool, searchReplaced:
+		b, err := strconv.ParseBool(token.value)
+		if err != nil {
+			return nil, newSearchError(token.pos, "invalid boolean '%s' for '%s' (true or false)", token.value, token.field)
+		}
+		if field.kind == searchReplaced {
+			condition = &searchCondition{clause: "certificates.successor_id IS NOT NULL"}
+		} else {
+			condition = &searchCondition{clause: field.column + " = ?", args: []interface{}{true}}
+		}
+		negate = negate == b
+
+	case searchSAN:
+		sanQuery := "SELECT certificate_id FROM subject_alt_names WHERE "
+		args := []interface{}{}
+		if field.column != "" {
+			sanQuery += "type = ? AND "
+			args = append(args, field.column)
+		}
+		if token.op == ":" {
+			sanQuery += "value LIKE ? ESCAPE '\\'"
+			args = append(args, toLikePattern(strings.ToLower(token.value)))
+		} else {
+			sanQuery += "value = ?"
+			args = append(args, strings.ToLower(token.value))
+		}
+		condition = &searchCondition{clause: "certificates.id IN (" + sanQuery + ")", args: args}
+
+	case searchTag:
+		tagQuery := "SELECT tags_ref.certificate_id FROM tags_ref JOIN tags ON tags.id = tags_ref.tag_id WHERE tags.deleted_at IS NULL AND "
+		var arg interface{} = token.value
+		// the tags merged into another tag match by their alias
+		if token.op == ":" {
+			tagQuery += "(tags.name LIKE ? ESCAPE '\\' OR tags.id IN (SELECT tag_id FROM tag_aliases WHERE name LIKE ? ESCAPE '\\'))"
+			arg = toLikePattern(token.value)
+		} else {
+			tagQuery += "(tags.name = ? OR " + tagAliasClause + ")"
+		}
+		condition = &searchCondition{clause: "certificates.id IN (" + tagQuery + ")", args: []interface{}{arg, arg}}
+
+	case searchStatus:
+		var err error
+		condition, err = newSearchStatusCondition(token, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if negate {
+		condition.clause = "NOT (" + condition.clause + ")"
+	}
+
+	return condition, nil
+}
+
+// newSearchDateCondition return the condition comparing the time column with a date
+// (the whole day for 2006-01-02) or a time (RFC3339)
+func newSearchDateCondition(token searchToken, column string) (*searchCondition, error) {
+
+	// the times are stored and compared as strings in UTC
+	var from, to time.Time
+	if day, err := time.Parse("2006-01-02", token.value); err == nil {
+		from, to = day, day.AddDate(0, 0, 1)
+	} else if t, err := time.Parse(time.RFC3339, token.value); err == nil {
+		from, to = t.UTC(), t.UTC().Add(time.Nanosecond)
+	} else {
+		return nil, newSearchError(token.pos, "invalid date '%s' for '%s' (2006-01-02 or RFC3339)", token.value, token.field)
+	}
+
+	switch token.op {
+	case "<":
+		return &searchCondition{clause: column + " < ?", args: []interface{}{from}}, nil
+	case "<=":
+		return &searchCondition{clause: column + " < ?", args: []interface{}{to}}, nil
+	case ">":
+		return &searchCondition{clause: column + " >= ?", args: []interface{}{to}}, nil
+	case ">=":
+		return &searchCondition{clause: column + " >= ?", args: []interface{}{from}}, nil
+	case "!=":
+		return &searchCondition{clause: "(" + column + " < ? OR " + column + " >= ?)", args: []interface{}{from, to}}, nil
+	}
+
+	return &searchCondition{clause: "(" + column + " >= ? AND " + column + " < ?)", args: []interface{}{from, to}}, nil
+}
+
+// newSearchStatusCondition return the condition matching the certs with the validity status at now
+func newSearchStatusCondition(token searchToken, now time.Time) (*searchCondition, error) {
+
+	now = now.UTC()
+	expiring := now.Add(CertificateExpiringWindow)
+
+	switch CertificateStatus(strings.ToLower(token.value)) {
+	case CertificateStatusNotYetValid:
+		return &searchCondition{clause: "certificates.not_before > ?", args: []interface{}{now}}, nil
+	case CertificateStatusExpired:
+		return &searchCondition{clause: "certificates.not_after < ?", args: []interface{}{now}}, nil
+	case CertificateStatusExpiring:
+		return &searchCondition{clause: "(certificates.not_before <= ? AND certificates.not_after >= ? AND certificates.not_after < ?)",
+			args: []interface{}{now, now, expiring}}, nil
+	case CertificateStatusValid:
+		return &searchCondition{clause: "(certificates.not_before <= ? AND certificates.not_after >= ?)",
+			args: []interface{}{now, expiring}}, nil
+	}
+
+	return nil, newSearchError(token.pos, "invalid status '%s' (valid, expiring, expired or not-yet-valid)", token.value)
+}
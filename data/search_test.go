@@ -0,0 +1,116 @@
+package data
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCertSearchErrors(t *testing.T) {
+
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{"", "empty search"},
+		{"example.com", "position 1: expected field:value"},
+		{"owner:bob", "position 1: unknown field 'owner'"},
+		{"tag:prod AND", "position 13: expected field:value, NOT or '(' but got end of query"},
+		{"(tag:prod OR tag:dev", "position 21: expected ')' to close '(' at position 1"},
+		{"tag:prod)", "position 9: unexpected ')'"},
+		{`issuer.o:"Let's Encrypt`, "position 10: missing closing quote"},
+		{"not_after<2026-13-01", "position 1: invalid date '2026-13-01'"},
+		{"tag<prod", "operator '<' is not valid on 'tag'"},
+		{"is_ca:yes", "invalid boolean 'yes'"},
+		{"key_size>big", "invalid number 'big'"},
+		{"status:revoked", "invalid status 'revoked'"},
+		{"san:", "position 5: expected a value after 'san:'"},
+		{"san!a", "position 4: expected '!='"},
+		{"tag:prod & tag:dev", "position 10: unexpected character '&'"},
+		{strings.Repeat("NOT ", maxSearchDepth+1) + "tag:prod", "too many nested"},
+	}
+
+	for _, test := range tests {
+		_, err := parseCertSearch(test.query, time.Now())
+		if _, ok := err.(*DBObjectValidationError); !ok || !strings.Contains(err.Error(), test.err) {
+			t.Logf("Expecting error '%s' for '%s' got %v", test.err, test.query, err)
+			t.FailNow()
+		}
+	}
+}
+
+func TestSearchCertsPage(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	for _, name := range []string{"trust", "prod", "decommissioned"} {
+		_, err := certBakcend.CreateTag(name)
+		if err != nil {
+			t.Logf("Error creating tag %s", err.Error())
+			t.FailNow()
+		}
+	}
+
+	_, err := certBakcend.CreateCertificateWithTags(testRootPEM, []string{"trust"})
+	if err == nil {
+		_, err = certBakcend.CreateCertificateWithTags(testIntermediatePEM, []string{"prod", "decommissioned"})
+	}
+	if err == nil {
+		_, err = certBakcend.CreateCertificateWithTags(testLeafPEM, []string{"prod"})
+	}
+	if err == nil {
+		_, err = certBakcend.CreateCertificate(testRenewedLeafPEM)
+	}
+	if err != nil {
+		t.Logf("Error creating certs %s", err.Error())
+		t.FailNow()
+	}
+
+	tests := []struct {
+		query string
+		count int64
+	}{
+		{`san:*.example.com AND issuer.o:"Acme" AND not_after<2032-01-01 AND tag:prod AND NOT tag:decommissioned`, 1},
+		{"tag:prod", 2},
+		{"tag:prod NOT tag:decommissioned", 1},
+		{"tag:PROD", 2},
+		{"tag:pro*", 2},
+		{"tag=pro*", 0},
+		{"tag!=prod", 2},
+		{"TAG:trust or tag:decommissioned", 2},
+		{"(tag:trust OR tag:decommissioned) AND is_ca:true", 2},
+		{"is_ca:false", 2},
+		{"is_ca!=true", 2},
+		{"self_signed:true", 1},
+		{"san.dns:www.example.com", 2},
+		{"san.ip=10.0.0.1", 2},
+		{"san.dns:10.0.0.1", 0},
+		{"subject.cn:www.example.com", 2},
+		{"subject:*Acme*", 4},
+		{"key:RSA AND key_size>=2048", 3},
+		{"key_size<2048", 1},
+		{"not_after=2031-06-01", 1},
+		{"not_after<=2031-06-01", 1},
+		{"not_after>2031-06-01", 3},
+		{"not_before>=2031-05-01T00:00:00Z", 1},
+		{"status:valid", 3},
+		{"status:not-yet-valid", 1},
+		{"replaced:true", 1},
+		{"replaced:false AND subject.cn:www.example.com", 1},
+	}
+
+	for _, test := range tests {
+		_, total, err := certBakcend.SearchCertsPage(test.query, ListOptions{})
+		if err != nil || total != test.count {
+			t.Logf("Expecting %d certs for '%s' got %d (%v)", test.count, test.query, total, err)
+			t.FailNow()
+		}
+	}
+
+	// the page and the order apply to the search
+	certs, total, err := certBakcend.SearchCertsPage("subject:*", ListOptions{Limit: 1, Sort: "-not_after"})
+	if err != nil || total != 4 || len(certs) != 1 || certs[0].NotAfter.Year() != 2041 {
+		t.Logf("Expecting the renewed leaf first of 4 got %d of %d (%v)", len(certs), total, err)
+		t.FailNow()
+	}
+}
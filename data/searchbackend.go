@@ -0,0 +1,39 @@
+package data
+
+import (
+	"time"
+)
+
+//
+// Search functions
+// Read: SearchCertsPage
+//
+
+// SearchCertsPage returns a page of the certs matching the search query with their assosicated tags and
+// labels (without their PEM) and the total number of certs matching the query, an invalid query returns a DBObjectValidationError
+func (certBackend *CertBackend) SearchCertsPage(query string, opts ListOptions) (Certificates, int64, error) {
+	certBackend.logger.Debug("SearchCertsPage: ", "query", query, "opts", opts)
+
+	condition, err := parseCertSearch(query, time.Now())
+	if err == nil {
+		err = opts.validate(certSortKeys)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	result := certBackend.db.Model(&Certificate{}).Where(condition.clause, condition.args...).Count(&total)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var certList Certificates
+	search := certBackend.listCertsQuery().Where(condition.clause, condition.args...)
+	result = opts.apply(search, certSortKeys, "certificates", "created_at").Find(&certList)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return certList, total, nil
+}
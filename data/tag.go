@@ -1,12 +1,18 @@
 package data
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// TagPathSeparator separates the names of the ancestors of a hierarchical tag (e.g. payments/prod/eu)
+const TagPathSeparator = "/"
+
 // Tag defines Tag for X509 Cert
 // swagger:model
 type Tag struct {
@@ -16,16 +22,37 @@ type Tag struct {
 	// required: false
 	ID uuid.UUID `json:"id" gorm:"type:uuid;primary_key;"`
 
-	// the Tag name
+	// the Tag name, the path of a hierarchical tag from its root tag (e.g. payments/prod/eu)
 	//
 	// required: false
 	Name string `json:"name"  gorm:"uniqueIndex" validate:"required,excludesall= "`
 
+	// the id of the parent tag of a hierarchical tag (e.g. payments/prod for payments/prod/eu)
+	//
+	// required: false
+	ParentID *uuid.UUID `json:"parent_id,omitempty" gorm:"type:uuid;index"`
+
+	// the other names of the tag (e.g. the names of the tags merged into the tag)
+	//
+	// required: false
+	Aliases []TagAlias `json:"aliases,omitempty" gorm:"foreignKey:TagID"`
+
+	// the child tags (only set when listing the tree of tags)
+	//
+	// required: false
+	Children Tags `json:"children,omitempty" gorm:"-"`
+
 	// the Tag Description
 	//
 	// required: false
 	Description string `json:"description" `
 
+	// the comma separated thresholds in days before expiry at which the certificates
+	// of the tag are notified (e.g. 60,30,7,1), the default thresholds if empty
+	//
+	// required: false
+	NotificationThresholds string `json:"notification_thresholds,omitempty"`
+
 	// the List of Certificates for the tag
 	//
 	// required: false
@@ -34,12 +61,17 @@ type Tag struct {
 	// the CreatedAt timestamp for the tag
 	//
 	// required: false
-	CreatedAt time.Time `json:"-" `
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
 
 	// the UpdatedAt timestamp for the tag
 	//
 	// required: false
-	UpdatedAt time.Time `json:"-" `
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// the DeletedAt timestamp for the tag (only set for the tags in the trash)
+	//
+	// required: false
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
 // BeforeCreate will set a UUID rather than numeric ID.
@@ -52,12 +84,111 @@ func (tag *Tag) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// TagAlias defines another name of a Tag (e.g. the name of a tag merged into the tag)
+// swagger:model
+type TagAlias struct {
+	// the id for the alias
+	//
+	// required: false
+	ID uuid.UUID `json:"-" gorm:"type:uuid;primary_key;"`
+
+	// the id of the tag of the alias
+	//
+	// required: false
+	TagID uuid.UUID `json:"-" gorm:"type:uuid;index"`
+
+	// the name of the alias
+	//
+	// required: false
+	Name string `json:"name" gorm:"uniqueIndex"`
+
+	// the CreatedAt timestamp for the alias
+	//
+	// required: false
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (alias *TagAlias) BeforeCreate(tx *gorm.DB) (err error) {
+	if alias.ID == uuid.Nil {
+		uuid := uuid.New()
+		alias.ID = uuid
+	}
+
+	return
+}
+
 // NewTag create a new tag struct
+// the name of a hierarchical tag must not have empty segments (e.g. payments//eu)
 func NewTag(name string) (*Tag, error) {
+
+	if strings.Contains(name, TagPathSeparator) {
+		for _, segment := range strings.Split(name, TagPathSeparator) {
+			if segment == "" {
+				return nil, &DBObjectValidationError{Msg: fmt.Sprintf("invalid tag name '%s': empty segment", name)}
+			}
+		}
+	}
+
 	return &Tag{
 		Name: name,
 	}, nil
 }
 
+// ParentName return the name of the parent of a hierarchical tag (empty for a root tag)
+func (tag *Tag) ParentName() string {
+
+	i := strings.LastIndex(tag.Name, TagPathSeparator)
+	if i < 0 {
+		return ""
+	}
+
+	return tag.Name[:i]
+}
+
+// tagAliasClause the condition matching the tag with an alias
+const tagAliasClause = "tags.id IN (SELECT tag_aliases.tag_id FROM tag_aliases WHERE tag_aliases.name = ?)"
+
+// tagDescendantsClause the condition matching the descendants of the tag with a name or an alias
+// (the name and the alias are both bound), the descendants are matched by the name of the tag
+// so the alias of a merged tag matches the descendants of the tag it was merged into
+const tagDescendantsClause = "EXISTS (SELECT 1 FROM tags AS ancestors WHERE (ancestors.name = ? OR ancestors.id IN " +
+	"(SELECT tag_aliases.tag_id FROM tag_aliases WHERE tag_aliases.name = ?)) AND " +
+	"substr(tags.name, 1, length(ancestors.name) + 1) = ancestors.name || '" + TagPathSeparator + "')"
+
+// tagDescendantsPattern return the LIKE pattern matching the names of the descendants of the tag name
+func tagDescendantsPattern(name string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(name) + TagPathSeparator + "%"
+}
+
 // Tags a list of Tag
 type Tags []*Tag
+
+// tree return the root tags of the tags with their descendants as Children (sorted by name)
+// a tag whose parent is not in the list is a root
+func (tags Tags) tree() Tags {
+
+	sorted := make(Tags, len(tags))
+	copy(sorted, tags)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	byID := map[uuid.UUID]*Tag{}
+	for _, t := range sorted {
+		t.Children = nil
+		byID[t.ID] = t
+	}
+
+	roots := Tags{}
+	for _, t := range sorted {
+		if t.ParentID != nil {
+			if parent, ok := byID[*t.ParentID]; ok {
+				parent.Children = append(parent.Children, t)
+				continue
+			}
+		}
+		roots = append(roots, t)
+	}
+
+	return roots
+}
@@ -2,18 +2,21 @@ package data
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 //
 // Tag CRUD functions
 // Create: CreateTag, CreateTagWithDescription
-// Read:   GetTagByName, GetTagByID, ListTags,  getTagByNameWithoutPreload
-// Update: SetTagDescriptionByID
-// Delete: DeleteTagByID,  DeleteTagPendingRecords
+// Read:   GetTagByName, GetTagByID, ListTags, ListTagsPage, getTagByNameWithoutPreload, getTagByNameUnscoped,
+//         getTagByNameOrAliasUnscoped
+// Update: SetTagDescriptionByID, SetTagNotificationThresholdsByID
+// Delete: DeleteTagByID,  DeleteTagPendingRecords, purgeTags
 //
 
 // CreateTag create a new Tag
@@ -29,16 +32,30 @@ func (certBackend *CertBackend) CreateTag(tagName string) (*Tag, error) {
 		return nil, &DBObjectValidationError{Msg: strings.Join(validationErr.Errors(), "\n")}
 	}
 
-	// lookup if object already exist
-	foundTag, _ := certBackend.getTagByNameWithoutPreload(tagName)
+	// lookup if object already exist (the name of a tag in the trash is taken until it is purged)
+	foundTag, _ := certBackend.getTagByNameOrAliasUnscoped(tagName)
 	if foundTag != nil {
 		return foundTag, &DBObjectAlreadyExist{ID: foundTag.ID.String()}
 	}
 
-	// Create new entry
-	result := certBackend.db.Create(&tag)
-	if result.Error != nil {
-		return nil, result.Error
+	err = certBackend.auditTransaction(func(tx *gorm.DB) error {
+
+		// link a hierarchical tag to its parent (created if missing)
+		err := certBackend.withTx(tx).ensureTagParent(tag)
+		if err != nil {
+			return err
+		}
+
+		// Create new entry
+		result := tx.Create(&tag)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionCreate, AuditObjectTag, tag.ID, nil, NewAuditTagState(tag))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return tag, nil
@@ -61,16 +78,30 @@ func (certBackend *CertBackend) CreateTagWithDescription(tagName string, descrip
 		return nil, &DBObjectValidationError{Msg: strings.Join(validationErr.Errors(), "\n")}
 	}
 
-	// lookup if object already exist
-	foundTag, _ := certBackend.getTagByNameWithoutPreload(tagName)
+	// lookup if object already exist (the name of a tag in the trash is taken until it is purged)
+	foundTag, _ := certBackend.getTagByNameOrAliasUnscoped(tagName)
 	if foundTag != nil {
 		return foundTag, &DBObjectAlreadyExist{ID: foundTag.ID.String()}
 	}
 
-	// Create new entry
-	result := certBackend.db.Create(&tag)
-	if result.Error != nil {
-		return nil, result.Error
+	err = certBackend.auditTransaction(func(tx *gorm.DB) error {
+
+		// link a hierarchical tag to its parent (created if missing)
+		err := certBackend.withTx(tx).ensureTagParent(tag)
+		if err != nil {
+			return err
+		}
+
+		// Create new entry
+		result := tx.Create(&tag)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionCreate, AuditObjectTag, tag.ID, nil, NewAuditTagState(tag))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return tag, nil
@@ -83,6 +114,10 @@ func (certBackend *CertBackend) GetTagByName(tagName string) (*Tag, error) {
 	var tag Tag
 
 	result := certBackend.db.Preload(clause.Associations).Where("name = ?", tagName).First(&tag)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		// a tag merged into another tag is found by its alias
+		result = certBackend.db.Preload(clause.Associations).Where(tagAliasClause, tagName).First(&tag)
+	}
 	if result.Error != nil {
 		return nil, errors.New("Tag not found " + tagName)
 	}
@@ -102,6 +137,10 @@ func (certBackend *CertBackend) getTagByNameWithoutPreload(tagName string) (*Tag
 	var tag Tag
 
 	result := certBackend.db.Where("name = ?", tagName).First(&tag)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		// a tag merged into another tag is found by its alias
+		result = certBackend.db.Where(tagAliasClause, tagName).First(&tag)
+	}
 	if result.Error != nil {
 		return nil, errors.New("Tag not found " + tagName)
 	}
@@ -114,6 +153,48 @@ func (certBackend *CertBackend) getTagByNameWithoutPreload(tagName string) (*Tag
 	return &tag, nil
 }
 
+// getTagByNameUnscoped return Tag (without associated cert) including the tags in the trash
+func (certBackend *CertBackend) getTagByNameUnscoped(tagName string) (*Tag, error) {
+	certBackend.logger.Debug("getTagByNameUnscoped: Getting Tag...", "tagName", tagName)
+
+	var tag Tag
+
+	result := certBackend.db.Unscoped().Where("name = ?", tagName).Limit(1).Find(&tag)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// check if result is empty
+	if result.RowsAffected == 0 {
+		return nil, &DBObjectNotFound{ID: tagName}
+	}
+
+	return &tag, nil
+}
+
+// getTagByNameOrAliasUnscoped return Tag (without associated cert) with the name or the alias
+// including the tags in the trash
+func (certBackend *CertBackend) getTagByNameOrAliasUnscoped(tagName string) (*Tag, error) {
+
+	tag, err := certBackend.getTagByNameUnscoped(tagName)
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		return tag, err
+	}
+
+	var aliasTag Tag
+	result := certBackend.db.Unscoped().Where(tagAliasClause, tagName).Limit(1).Find(&aliasTag)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// check if result is empty
+	if result.RowsAffected == 0 {
+		return nil, &DBObjectNotFound{ID: tagName}
+	}
+
+	return &aliasTag, nil
+}
+
 // GetTagByID return Tag (without associated cert)
 func (certBackend *CertBackend) GetTagByID(uuid uuid.UUID) (*Tag, error) {
 	certBackend.logger.Debug("GetTagByID: Getting Tag...", "uuid", uuid)
@@ -148,6 +229,31 @@ func (certBackend *CertBackend) ListTags() (Tags, error) {
 	return tagList, nil
 }
 
+// ListTagsPage returns a page of the tags matching the filter (without associated certs)
+// and the total number of tags matching the filter
+func (certBackend *CertBackend) ListTagsPage(filter TagFilter, opts ListOptions) (Tags, int64, error) {
+	certBackend.logger.Debug("ListTagsPage: ", "filter", filter, "opts", opts)
+
+	err := opts.validate(tagSortKeys)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	result := filter.apply(certBackend.db.Model(&Tag{})).Count(&total)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var tagList Tags
+	result = opts.apply(filter.apply(certBackend.db), tagSortKeys, "tags", "name").Find(&tagList)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return tagList, total, nil
+}
+
 // SetTagDescriptionByID Update the Tag description
 func (certBackend *CertBackend) SetTagDescriptionByID(uuid uuid.UUID, description string) (*Tag, error) {
 	certBackend.logger.Debug("SetTagDescriptionByID: ", "uuid", uuid, "description", description)
@@ -156,63 +262,174 @@ func (certBackend *CertBackend) SetTagDescriptionByID(uuid uuid.UUID, descriptio
 	if err != nil {
 		return nil, err
 	}
+	before := NewAuditTagState(tag)
+
 	// set descrition
 	tag.Description = description
 
-	// save change
-	updateresult := certBackend.db.Save(&tag)
-	if updateresult.Error != nil {
-		return nil, updateresult.Error
+	err = certBackend.auditTransaction(func(tx *gorm.DB) error {
+		// save change
+		updateresult := tx.Save(&tag)
+		if updateresult.Error != nil {
+			return updateresult.Error
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionUpdate, AuditObjectTag, tag.ID, before, NewAuditTagState(tag))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// SetTagNotificationThresholdsByID Update the Tag notification thresholds
+// (comma separated days, empty to use the default thresholds)
+func (certBackend *CertBackend) SetTagNotificationThresholdsByID(uuid uuid.UUID, thresholds string) (*Tag, error) {
+	certBackend.logger.Debug("SetTagNotificationThresholdsByID: ", "uuid", uuid, "thresholds", thresholds)
+
+	days, err := ParseNotificationThresholds(thresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := certBackend.GetTagByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+	before := NewAuditTagState(tag)
+
+	// set normalized thresholds
+	tag.NotificationThresholds = FormatNotificationThresholds(days)
+
+	err = certBackend.auditTransaction(func(tx *gorm.DB) error {
+		// save change
+		updateresult := tx.Model(&Tag{}).Where("id = ?", tag.ID).Update("notification_thresholds", tag.NotificationThresholds)
+		if updateresult.Error != nil {
+			return updateresult.Error
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionUpdate, AuditObjectTag, tag.ID, before, NewAuditTagState(tag))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return tag, nil
 }
 
 // DeleteTagByID delete the tag with uuid
-// This is only doing a soft delete (update the DeleteAt field)
+// This is only doing a soft delete (update the DeletedAt field), the tag
+// is moved to the trash and can be restored until it is purged
 func (certBackend *CertBackend) DeleteTagByID(uuid uuid.UUID) error {
 	certBackend.logger.Debug("DeleteTagByID: Deleting tag", "uuid", uuid)
 
-	result := certBackend.db.Delete(&Tag{}, uuid)
+	tag, err := certBackend.GetTagByID(uuid)
+	if err != nil {
+		return err
+	}
+
+	// the child tags would be left without parent
+	var children int64
+	result := certBackend.db.Model(&Tag{}).Where("parent_id = ?", uuid).Count(&children)
 	if result.Error != nil {
 		return result.Error
 	}
+	if children != 0 {
+		return &DBObjectValidationError{Msg: fmt.Sprintf("tag '%s' has %d child tags, delete them first", tag.Name, children)}
+	}
 
-	// check if result is empty
-	if result.RowsAffected == 0 {
-		return &DBObjectNotFound{ID: uuid.String()}
+	return certBackend.auditTransaction(func(tx *gorm.DB) error {
 
-	}
+		result := tx.Delete(&Tag{}, uuid)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// check if result is empty
+		if result.RowsAffected == 0 {
+			return &DBObjectNotFound{ID: uuid.String()}
 
-	return nil
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionDelete, AuditObjectTag, uuid, NewAuditTagState(tag), nil)
+	})
 }
 
 // DeleteTagPendingRecords deletes all Tags that where flagged for deleting
 func (certBackend *CertBackend) DeleteTagPendingRecords() error {
-	certBackend.logger.Debug("DeleteTagPendingRecords: Deletings cert")
+	certBackend.logger.Debug("DeleteTagPendingRecords: Deletings tag")
+
+	_, err := certBackend.purgeTags(certBackend.db.Unscoped().Where("deleted_at IS NOT NULL"))
+	return err
+}
+
+// purgeTags permanently deletes the Tags found by query, return the number of purged tags
+func (certBackend *CertBackend) purgeTags(query *gorm.DB) (int, error) {
 
 	// lookup all unscoped
 	var unscopedTags Tags
 
-	// lookup the entries with a deleted_at not null
-	result := certBackend.db.Unscoped().Where("deleted_at IS NOT NULL").Find(&unscopedTags)
+	// lookup the entries to purge
+	result := query.Find(&unscopedTags)
 	if result.Error != nil {
-		return result.Error
+		return 0, result.Error
 	}
 
-	// Iterate over unscoped certificates
-	// and delete them permanently
+	// Iterate over unscoped tags
+	// and delete them permanently, each tag with its events
 	for _, t := range unscopedTags {
-		certBackend.logger.Error("DeleteTagPendingRecords: Deletings tag", "tag", t)
-
-		// clearing all associated Tags with that cert
-		certBackend.db.Model(&t).Association("Certificates").Clear()
-
-		result := certBackend.db.Unscoped().Delete(&t)
-		if result.Error != nil {
-			return result.Error
+		certBackend.logger.Debug("purgeTags: Deletings tag", "tag", t)
+
+		err := certBackend.auditTransaction(func(tx *gorm.DB) error {
+			txBackend := certBackend.withTx(tx)
+
+			// clearing all associated Certificates with that tag
+			err := tx.Model(t).Association("Certificates").Clear()
+			if err != nil {
+				return err
+			}
+
+			// the children in the trash are linked again to a new parent when restored
+			result := tx.Unscoped().Model(&Tag{}).Where("parent_id = ?", t.ID).Update("parent_id", nil)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			// the names of the merged tags are free again
+			result = tx.Where("tag_id = ?", t.ID).Delete(&TagAlias{})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			// the auto-tag rules of the tag cannot tag anymore
+			rules, err := txBackend.findAutoTagRules(tx.Where("tag_id = ?", t.ID))
+			if err != nil {
+				return err
+			}
+			for _, r := range rules {
+				result = tx.Delete(r)
+				if result.Error != nil {
+					return result.Error
+				}
+
+				err = txBackend.recordAudit(AuditActionPurge, AuditObjectAutoTagRule, r.ID, NewAuditAutoTagRuleState(r), nil)
+				if err != nil {
+					return err
+				}
+			}
+
+			result = tx.Unscoped().Delete(t)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			return txBackend.recordAudit(AuditActionPurge, AuditObjectTag, t.ID, NewAuditTagState(t), nil)
+		})
+		if err != nil {
+			return 0, err
 		}
 	}
 
-	return nil
+	return len(unscopedTags), nil
 }
@@ -19,7 +19,7 @@ func TestTags(t *testing.T) {
 	}
 	// init new db
 	db := database.NewSqliteDB("/tmp/sqlite.db")
-	err = db.AutoMigrate(&Tag{}, &Certificate{})
+	err = db.AutoMigrate(Models()...)
 	if err != nil {
 		t.Logf("Error creating Tag %s", err.Error())
 		t.FailNow()
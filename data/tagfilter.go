@@ -0,0 +1,35 @@
+package data
+
+import (
+	"gorm.io/gorm"
+)
+
+// TagFilter filters for listing tags
+// empty fields are ignored
+type TagFilter struct {
+	// the name to match, '*' matches any sequence of characters
+	Name string
+	// the description to match, '*' matches any sequence of characters
+	Description string
+}
+
+// apply add the conditions of the filter to the tags query
+func (filter *TagFilter) apply(query *gorm.DB) *gorm.DB {
+
+	if filter.Name != "" {
+		query = query.Where("tags.name LIKE ? ESCAPE '\\'", toLikePattern(filter.Name))
+	}
+
+	if filter.Description != "" {
+		query = query.Where("tags.description LIKE ? ESCAPE '\\'", toLikePattern(filter.Description))
+	}
+
+	return query
+}
+
+// tagSortKeys the keys to sort the tags
+var tagSortKeys = sortKeys{
+	"id":         "tags.id",
+	"name":       "tags.name",
+	"created_at": "tags.created_at",
+}
@@ -0,0 +1,139 @@
+package data
+
+import (
+	"github.com/google/uuid"
+)
+
+//
+// Tag hierarchy functions
+// Read:   ListTagTree, GetTagByIDWithDescendants, GetTagByNameWithDescendants
+// Update: ResolveTagHierarchy, ensureTagParent, linkTagParent
+//
+
+// ListTagTree returns the root tags matching the filter with their descendants as Children
+// (without associated certs), a tag whose parent does not match the filter is a root
+func (certBackend *CertBackend) ListTagTree(filter TagFilter) (Tags, error) {
+	certBackend.logger.Debug("ListTagTree: ", "filter", filter)
+
+	var tagList Tags
+	result := filter.apply(certBackend.db).Order("tags.name").Find(&tagList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return tagList.tree(), nil
+}
+
+// GetTagByIDWithDescendants return Tag with the certs tagged with the tag or any of its descendants
+func (certBackend *CertBackend) GetTagByIDWithDescendants(uuid uuid.UUID) (*Tag, error) {
+
+	tag, err := certBackend.GetTagByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, certBackend.loadTagDescendantCerts(tag)
+}
+
+// GetTagByNameWithDescendants return Tag with the certs tagged with the tag or any of its descendants
+func (certBackend *CertBackend) GetTagByNameWithDescendants(tagName string) (*Tag, error) {
+
+	tag, err := certBackend.GetTagByName(tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, certBackend.loadTagDescendantCerts(tag)
+}
+
+// loadTagDescendantCerts set the Certificates of tag to the certs tagged with the tag or any of its descendants
+func (certBackend *CertBackend) loadTagDescendantCerts(tag *Tag) error {
+
+	clause, args := tagCertsCondition(tag.Name, true)
+
+	var certs []Certificate
+	result := certBackend.db.Where("certificates.id IN ("+clause+")", args...).Find(&certs)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	tag.Certificates = certs
+
+	return nil
+}
+
+// ResolveTagHierarchy links all the hierarchical tags to their parent
+// creating the missing ancestors (e.g. for the tags created before the hierarchy)
+func (certBackend *CertBackend) ResolveTagHierarchy() error {
+	certBackend.logger.Debug("ResolveTagHierarchy: Linking tags to their parent...")
+
+	var tagList Tags
+	// the parents are linked before their children
+	result := certBackend.db.Order("name").Find(&tagList)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, t := range tagList {
+		err := certBackend.linkTagParent(t)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureTagParent set the ParentID of tag to its parent tag, the parent (and its ancestors)
+// is created if it does not exist or restored if it is in the trash
+func (certBackend *CertBackend) ensureTagParent(tag *Tag) error {
+
+	parentName := tag.ParentName()
+	if parentName == "" {
+		tag.ParentID = nil
+		return nil
+	}
+
+	parent, err := certBackend.getTagByNameUnscoped(parentName)
+	if err != nil {
+		if _, ok := err.(*DBObjectNotFound); !ok {
+			return err
+		}
+
+		certBackend.logger.Debug("ensureTagParent: Creating parent tag", "tagName", tag.Name, "parent", parentName)
+		parent, err = certBackend.CreateTag(parentName)
+		if err != nil {
+			return err
+		}
+
+	} else if parent.DeletedAt.Valid {
+
+		certBackend.logger.Debug("ensureTagParent: Restoring parent tag", "tagName", tag.Name, "parent", parentName)
+		parent, err = certBackend.RestoreTagByID(parent.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	tag.ParentID = &parent.ID
+
+	return nil
+}
+
+// linkTagParent update the stored parent of tag to its parent tag (see ensureTagParent)
+func (certBackend *CertBackend) linkTagParent(tag *Tag) error {
+
+	parentID := tag.ParentID
+
+	err := certBackend.ensureTagParent(tag)
+	if err != nil {
+		return err
+	}
+
+	if sameUUID(parentID, tag.ParentID) {
+		return nil
+	}
+
+	result := certBackend.db.Unscoped().Model(&Tag{}).Where("id = ?", tag.ID).Update("parent_id", tag.ParentID)
+	return result.Error
+}
@@ -0,0 +1,156 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTagHierarchy(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	// the missing ancestors are created
+	eu, err := certBakcend.CreateTag("payments/prod/eu")
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	prod, err := certBakcend.getTagByNameWithoutPreload("payments/prod")
+	if err != nil || eu.ParentID == nil || *eu.ParentID != prod.ID {
+		t.Logf("Expecting payments/prod parent of payments/prod/eu got %v (%v)", eu.ParentID, err)
+		t.FailNow()
+	}
+
+	payments, err := certBakcend.getTagByNameWithoutPreload("payments")
+	if err != nil || prod.ParentID == nil || *prod.ParentID != payments.ID || payments.ParentID != nil {
+		t.Logf("Expecting payments root parent of payments/prod got %v (%v)", prod.ParentID, err)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.CreateTagWithDescription("payments/dev", "the payments dev servers")
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	for _, invalid := range []string{"payments//eu", "/payments", "payments/"} {
+		_, err = certBakcend.CreateTag(invalid)
+		if _, ok := err.(*DBObjectValidationError); !ok {
+			t.Logf("Expecting validation error for '%s' got %v", invalid, err)
+			t.FailNow()
+		}
+	}
+
+	tree, err := certBakcend.ListTagTree(TagFilter{})
+	if err != nil || len(tree) != 1 || tree[0].Name != "payments" || len(tree[0].Children) != 2 ||
+		tree[0].Children[0].Name != "payments/dev" || len(tree[0].Children[1].Children) != 1 {
+		t.Logf("Expecting the payments tree got %v (%v)", tree, err)
+		t.FailNow()
+	}
+
+	// the certs tagged with a descendant
+	_, err = certBakcend.CreateCertificateWithTags(testLeafPEM, []string{"payments/prod/eu"})
+	if err == nil {
+		_, err = certBakcend.CreateCertificateWithTags(testRootPEM, []string{"payments"})
+	}
+	if err != nil {
+		t.Logf("Error creating certs %s", err.Error())
+		t.FailNow()
+	}
+
+	certs, err := certBakcend.ListCertsWithFilter(CertFilter{Tag: "payments"})
+	if err != nil || len(certs) != 1 {
+		t.Logf("Expecting 1 cert tagged with payments got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+
+	certs, err = certBakcend.ListCertsWithFilter(CertFilter{Tag: "payments", TagDescendants: true})
+	if err != nil || len(certs) != 2 {
+		t.Logf("Expecting 2 certs tagged with payments or a descendant got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+
+	// the alias of a tag merged into payments matches the descendants of payments
+	_, err = certBakcend.CreateTag("billing")
+	if err == nil {
+		_, err = certBakcend.MergeTagsByID(payments.ID, []string{"billing"})
+	}
+	if err != nil {
+		t.Logf("Error merging tag %s", err.Error())
+		t.FailNow()
+	}
+
+	certs, err = certBakcend.ListCertsWithFilter(CertFilter{Tag: "billing", TagDescendants: true})
+	if err != nil || len(certs) != 2 {
+		t.Logf("Expecting 2 certs tagged with the alias billing or a descendant got %d (%v)", len(certs), err)
+		t.FailNow()
+	}
+
+	tag, err := certBakcend.GetTagByNameWithDescendants("payments/prod")
+	if err != nil || len(tag.Certificates) != 1 {
+		t.Logf("Expecting 1 cert tagged with a descendant of payments/prod got %v (%v)", tag, err)
+		t.FailNow()
+	}
+
+	// a tag with children cannot be deleted
+	err = certBakcend.DeleteTagByID(prod.ID)
+	if _, ok := err.(*DBObjectValidationError); !ok {
+		t.Logf("Expecting validation error deleting a parent got %v", err)
+		t.FailNow()
+	}
+
+	// the parent in the trash is restored with its child
+	err = certBakcend.DeleteTagByID(eu.ID)
+	if err == nil {
+		err = certBakcend.DeleteTagByID(prod.ID)
+	}
+	if err == nil {
+		_, err = certBakcend.RestoreTagByID(eu.ID)
+	}
+	if err == nil {
+		_, err = certBakcend.getTagByNameWithoutPreload("payments/prod")
+	}
+	if err != nil {
+		t.Logf("Expecting payments/prod restored with payments/prod/eu got %v", err)
+		t.FailNow()
+	}
+
+	err = certBakcend.DeleteTagByID(eu.ID)
+	if err == nil {
+		err = certBakcend.DeleteTagByID(prod.ID)
+	}
+	if err == nil {
+		_, err = certBakcend.PurgeTrash(time.Now().Add(time.Minute))
+	}
+	if err != nil {
+		t.Logf("Error purging tags %s", err.Error())
+		t.FailNow()
+	}
+
+	// the tags are purged
+	_, err = certBakcend.getTagByNameUnscoped("payments/prod")
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting payments/prod purged got %v", err)
+		t.FailNow()
+	}
+
+	// the purged parent is created again with its child
+	_, err = certBakcend.CreateTag("payments/prod/eu")
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	err = certBakcend.ResolveTagHierarchy()
+	if err != nil {
+		t.Logf("Error resolving tag hierarchy %s", err.Error())
+		t.FailNow()
+	}
+
+	tree, err = certBakcend.ListTagTree(TagFilter{Name: "payments/prod*"})
+	if err != nil || len(tree) != 1 || tree[0].Name != "payments/prod" || len(tree[0].Children) != 1 {
+		t.Logf("Expecting the payments/prod tree got %v (%v)", tree, err)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,232 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//
+// Tag rename and merge functions
+// Update: RenameTagByID, MergeTagsByID
+//
+
+// RenameTagByID rename the tag (uuid) and its descendants (e.g. payments/prod/eu to billing/prod/eu
+// when renaming payments to billing) in one transaction, the certs keep their tags
+// a new name already used by another tag or alias returns a DBObjectAlreadyExist
+func (certBackend *CertBackend) RenameTagByID(uuid uuid.UUID, newName string) (*Tag, error) {
+	certBackend.logger.Debug("RenameTagByID: Renaming tag", "uuid", uuid, "newName", newName)
+
+	tag, err := certBackend.GetTagByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if newName == tag.Name {
+		return tag, nil
+	}
+
+	renamed, err := NewTag(newName)
+	if err != nil {
+		return nil, err
+	}
+	validationErr := certBackend.v.Validate(renamed)
+	if validationErr != nil {
+		return nil, &DBObjectValidationError{Msg: strings.Join(validationErr.Errors(), "\n")}
+	}
+
+	if strings.HasPrefix(newName, tag.Name+TagPathSeparator) {
+		return nil, &DBObjectValidationError{Msg: fmt.Sprintf("cannot rename tag '%s' to its descendant '%s'", tag.Name, newName)}
+	}
+
+	// the descendants (including the ones in the trash) are renamed with the tag
+	var descendants Tags
+	result := certBackend.db.Unscoped().Where("name LIKE ? ESCAPE '\\'", tagDescendantsPattern(tag.Name)).Find(&descendants)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	newNames := map[*Tag]string{tag: newName}
+	for _, d := range descendants {
+		newNames[d] = newName + strings.TrimPrefix(d.Name, tag.Name)
+	}
+
+	// the new names must be free, an alias of the tag can become its name
+	for t, name := range newNames {
+		foundTag, err := certBackend.getTagByNameOrAliasUnscoped(name)
+		if err == nil && (foundTag.ID != tag.ID || t != tag) {
+			return foundTag, &DBObjectAlreadyExist{ID: foundTag.ID.String()}
+		}
+	}
+
+	err = certBackend.auditTransaction(func(tx *gorm.DB) error {
+
+		// the new parent (and its ancestors) is created if it does not exist
+		err := certBackend.withTx(tx).ensureTagParent(renamed)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("tag_id = ? AND name = ?", tag.ID, newName).Delete(&TagAlias{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Model(&Tag{}).Where("id = ?", tag.ID).
+			Updates(map[string]interface{}{"name": newName, "parent_id": renamed.ParentID})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		for _, d := range descendants {
+			result = tx.Unscoped().Model(&Tag{}).Where("id = ?", d.ID).Update("name", newNames[d])
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		before := NewAuditTagState(tag)
+		tag, err = certBackend.withTx(tx).GetTagByID(uuid)
+		if err != nil {
+			return err
+		}
+
+		err = certBackend.withTx(tx).recordAudit(AuditActionRename, AuditObjectTag, tag.ID, before, NewAuditTagState(tag))
+		if err != nil {
+			return err
+		}
+
+		for _, d := range descendants {
+			after := *d
+			after.Name = newNames[d]
+			err = certBackend.withTx(tx).recordAudit(AuditActionRename, AuditObjectTag, d.ID, NewAuditTagState(d), NewAuditTagState(&after))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// MergeTagsByID merge the tags with the names into the tag (uuid) in one transaction: the certs of
+// the merged tags are tagged with the tag, the merged tags are deleted and their names (and aliases)
+// become aliases of the tag, their auto-tag rules now add the tag
+// a merged tag with child tags returns a DBObjectValidationError
+func (certBackend *CertBackend) MergeTagsByID(uuid uuid.UUID, tagList []string) (*Tag, error) {
+	certBackend.logger.Debug("MergeTagsByID: Merging tags", "uuid", uuid, "tagList", tagList)
+
+	target, err := certBackend.GetTagByID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// lookup the tags to merge
+	var merged Tags
+	for _, t := range tagList {
+		tag, err := certBackend.getTagByNameWithoutPreload(t)
+		if err != nil {
+			// if one tag does not exist abort merge
+			return nil, &DBObjectNotFound{Err: err, ID: t}
+		}
+
+		if tag.ID == target.ID {
+			return nil, &DBObjectValidationError{Msg: fmt.Sprintf("cannot merge tag '%s' into itself", target.Name)}
+		}
+
+		// the child tags would be left without parent
+		var children int64
+		result := certBackend.db.Unscoped().Model(&Tag{}).Where("parent_id = ?", tag.ID).Count(&children)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if children != 0 {
+			return nil, &DBObjectValidationError{Msg: fmt.Sprintf("tag '%s' has %d child tags, merge or delete them first", tag.Name, children)}
+		}
+
+		result = certBackend.db.Preload("Aliases").Find(tag, tag.ID)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		duplicate := false
+		for _, m := range merged {
+			duplicate = duplicate || m.ID == tag.ID
+		}
+		if !duplicate {
+			merged = append(merged, tag)
+		}
+	}
+
+	var tag Tag
+	err = certBackend.auditTransaction(func(tx *gorm.DB) error {
+		for _, tag := range merged {
+
+			// move the associations to the target (once per cert)
+			result := tx.Exec("INSERT INTO tags_ref (certificate_id, tag_id) SELECT certificate_id, ? FROM tags_ref "+
+				"WHERE tag_id = ? AND certificate_id NOT IN (SELECT certificate_id FROM tags_ref WHERE tag_id = ?)",
+				target.ID, tag.ID, target.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			result = tx.Exec("DELETE FROM tags_ref WHERE tag_id = ?", tag.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			// the name of the merged tag and its aliases are aliases of the target
+			result = tx.Model(&TagAlias{}).Where("tag_id = ?", tag.ID).Update("tag_id", target.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			// the auto-tag rules of the merged tag tag the certs with the target
+			result = tx.Model(&AutoTagRule{}).Where("tag_id = ?", tag.ID).Update("tag_id", target.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			// the name must be free before being an alias
+			result = tx.Unscoped().Delete(&Tag{}, tag.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			result = tx.Create(&TagAlias{TagID: target.ID, Name: tag.Name})
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		before := NewAuditTagState(target)
+
+		result := tx.Preload(clause.Associations).Find(&tag, uuid)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		after := NewAuditTagState(&tag)
+		for _, m := range merged {
+			err := certBackend.withTx(tx).recordAudit(AuditActionMerge, AuditObjectTag, m.ID, NewAuditTagState(m), after)
+			if err != nil {
+				return err
+			}
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionMerge, AuditObjectTag, tag.ID, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
@@ -0,0 +1,169 @@
+package data
+
+import (
+	"testing"
+)
+
+func TestRenameTag(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	eu, err := certBakcend.CreateTag("payments/prod/eu")
+	if err == nil {
+		_, err = certBakcend.CreateTag("billing")
+	}
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	cert, err := certBakcend.CreateCertificateWithTags(testLeafPEM, []string{"payments/prod/eu"})
+	if err != nil {
+		t.Logf("Error creating cert %s", err.Error())
+		t.FailNow()
+	}
+
+	payments, err := certBakcend.getTagByNameWithoutPreload("payments")
+	if err != nil {
+		t.Logf("Error getting tag %s", err.Error())
+		t.FailNow()
+	}
+
+	// the descendants are renamed with the tag
+	renamed, err := certBakcend.RenameTagByID(payments.ID, "finance/payments")
+	if err != nil || renamed.Name != "finance/payments" || renamed.ParentID == nil {
+		t.Logf("Expecting finance/payments got %v (%v)", renamed, err)
+		t.FailNow()
+	}
+
+	tag, err := certBakcend.GetTagByID(eu.ID)
+	if err != nil || tag.Name != "finance/payments/prod/eu" || len(tag.Certificates) != 1 || tag.Certificates[0].ID != cert.ID {
+		t.Logf("Expecting finance/payments/prod/eu with the cert got %v (%v)", tag, err)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.RenameTagByID(payments.ID, "billing")
+	if _, ok := err.(*DBObjectAlreadyExist); !ok {
+		t.Logf("Expecting already exist error got %v", err)
+		t.FailNow()
+	}
+
+	for _, invalid := range []string{"finance/payments/old", "pay ments", "finance//payments"} {
+		_, err = certBakcend.RenameTagByID(payments.ID, invalid)
+		if _, ok := err.(*DBObjectValidationError); !ok {
+			t.Logf("Expecting validation error for '%s' got %v", invalid, err)
+			t.FailNow()
+		}
+	}
+
+	events, err := certBakcend.ListAuditEvents(AuditFilter{Action: AuditActionRename})
+	if err != nil || len(events) != 3 {
+		t.Logf("Expecting 3 rename events got %d (%v)", len(events), err)
+		t.FailNow()
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	for _, name := range []string{"prod", "production", "live"} {
+		_, err := certBakcend.CreateTag(name)
+		if err != nil {
+			t.Logf("Error creating tag %s", err.Error())
+			t.FailNow()
+		}
+	}
+
+	_, err := certBakcend.CreateCertificateWithTags(testRootPEM, []string{"prod"})
+	if err == nil {
+		_, err = certBakcend.CreateCertificateWithTags(testLeafPEM, []string{"prod", "production"})
+	}
+	if err == nil {
+		_, err = certBakcend.CreateCertificateWithTags(testIntermediatePEM, []string{"live"})
+	}
+	if err != nil {
+		t.Logf("Error creating certs %s", err.Error())
+		t.FailNow()
+	}
+
+	production, err := certBakcend.GetTagByName("production")
+	if err != nil {
+		t.Logf("Error getting tag %s", err.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.MergeTagsByID(production.ID, []string{"live"})
+	if err != nil {
+		t.Logf("Error merging tags %s", err.Error())
+		t.FailNow()
+	}
+
+	// the aliases of the merged tag are moved to the tag
+	_, err = certBakcend.CreateTag("prod-eu")
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	prod, err := certBakcend.GetTagByName("prod")
+	if err != nil {
+		t.Logf("Error getting tag %s", err.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.MergeTagsByID(prod.ID, []string{"prod-eu"})
+	if err == nil {
+		production, err = certBakcend.MergeTagsByID(production.ID, []string{"prod"})
+	}
+	if err != nil || len(production.Certificates) != 3 || len(production.Aliases) != 3 {
+		t.Logf("Expecting 3 certs and 3 aliases got %v (%v)", production, err)
+		t.FailNow()
+	}
+
+	// both names are found
+	for _, name := range []string{"prod", "prod-eu", "live", "production"} {
+		tag, err := certBakcend.GetTagByName(name)
+		if err != nil || tag.ID != production.ID {
+			t.Logf("Expecting production for '%s' got %v (%v)", name, tag, err)
+			t.FailNow()
+		}
+
+		certs, err := certBakcend.ListCertsWithFilter(CertFilter{Tag: name})
+		if err != nil || len(certs) != 3 {
+			t.Logf("Expecting 3 certs tagged with '%s' got %d (%v)", name, len(certs), err)
+			t.FailNow()
+		}
+	}
+
+	_, total, err := certBakcend.SearchCertsPage("tag:prod", ListOptions{})
+	if err != nil || total != 3 {
+		t.Logf("Expecting 3 certs for tag:prod got %d (%v)", total, err)
+		t.FailNow()
+	}
+
+	// the alias can be used to tag a cert, not to create a tag
+	_, err = certBakcend.CreateTag("prod")
+	if _, ok := err.(*DBObjectAlreadyExist); !ok {
+		t.Logf("Expecting already exist error got %v", err)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.MergeTagsByID(production.ID, []string{"prod"})
+	if _, ok := err.(*DBObjectValidationError); !ok {
+		t.Logf("Expecting validation error merging a tag into itself got %v", err)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.MergeTagsByID(production.ID, []string{"missing"})
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting not found error got %v", err)
+		t.FailNow()
+	}
+
+	events, err := certBakcend.ListAuditEvents(AuditFilter{Action: AuditActionMerge})
+	if err != nil || len(events) != 6 {
+		t.Logf("Expecting 6 merge events got %d (%v)", len(events), err)
+		t.FailNow()
+	}
+}
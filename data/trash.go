@@ -0,0 +1,15 @@
+package data
+
+// TrashPurgeReport the number of records permanently deleted from the trash
+// swagger:model
+type TrashPurgeReport struct {
+	// the number of purged certificates
+	//
+	// required: false
+	Certificates int `json:"certificates"`
+
+	// the number of purged tags
+	//
+	// required: false
+	Tags int `json:"tags"`
+}
@@ -0,0 +1,165 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//
+// Trash functions
+// Read:    ListTrashedCerts, ListTrashedTags
+// Restore: RestoreCertByID, RestoreTagByID
+// Purge:   PurgeTrash
+//
+
+// ListTrashedCerts return the deleted certs that were not purged yet (last deleted first)
+func (certBackend *CertBackend) ListTrashedCerts() (Certificates, error) {
+	certBackend.logger.Debug("ListTrashedCerts: Listing trashed certs...")
+
+	var certList Certificates
+	result := certBackend.db.Unscoped().Preload("Tags").
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&certList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return certList, nil
+}
+
+// ListTrashedTags return the deleted tags that were not purged yet (last deleted first)
+func (certBackend *CertBackend) ListTrashedTags() (Tags, error) {
+	certBackend.logger.Debug("ListTrashedTags: Listing trashed tags...")
+
+	var tagList Tags
+	result := certBackend.db.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&tagList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return tagList, nil
+}
+
+// RestoreCertByID move the cert (uuid) out of the trash with its tags
+// and link it again with its issuer, the certs it issued and its renewals
+func (certBackend *CertBackend) RestoreCertByID(uuid uuid.UUID) (*Certificate, error) {
+	certBackend.logger.Debug("RestoreCertByID: Restoring cert", "uuid", uuid)
+
+	var cert Certificate
+	result := certBackend.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", uuid).Limit(1).Find(&cert)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// check if result is empty
+	if result.RowsAffected == 0 {
+		return nil, &DBObjectNotFound{ID: uuid.String()}
+	}
+
+	var restored *Certificate
+	err := certBackend.auditTransaction(func(tx *gorm.DB) error {
+		txBackend := certBackend.withTx(tx)
+
+		// the issuer may have been deleted while the cert was in the trash
+		result := tx.Unscoped().Model(&Certificate{}).Where("id = ?", uuid).
+			Updates(map[string]interface{}{"deleted_at": nil, "issuer_id": nil})
+		if result.Error != nil {
+			return result.Error
+		}
+		cert.IssuerID = nil
+
+		err := txBackend.resolveCertIssuer(&cert)
+		if err != nil {
+			certBackend.logger.Error("RestoreCertByID: error resolving issuer", "id", uuid, "err", err)
+		}
+
+		err = txBackend.resolveIssuedCerts(&cert)
+		if err != nil {
+			certBackend.logger.Error("RestoreCertByID: error resolving issued certs", "id", uuid, "err", err)
+		}
+
+		restored, err = txBackend.GetCertByID(uuid)
+		if err != nil {
+			return err
+		}
+
+		err = txBackend.resolveCertRenewals(restored)
+		if err != nil {
+			certBackend.logger.Error("RestoreCertByID: error resolving renewals", "id", uuid, "err", err)
+		}
+
+		return txBackend.recordAudit(AuditActionRestore, AuditObjectCertificate, uuid, nil, NewAuditCertState(restored))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// RestoreTagByID move the tag (uuid) out of the trash with its certs
+// and link it again with its parent (restored or created if needed)
+func (certBackend *CertBackend) RestoreTagByID(uuid uuid.UUID) (*Tag, error) {
+	certBackend.logger.Debug("RestoreTagByID: Restoring tag", "uuid", uuid)
+
+	var tag Tag
+	err := certBackend.auditTransaction(func(tx *gorm.DB) error {
+
+		result := tx.Unscoped().Model(&Tag{}).
+			Where("id = ? AND deleted_at IS NOT NULL", uuid).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// check if result is empty
+		if result.RowsAffected == 0 {
+			return &DBObjectNotFound{ID: uuid.String()}
+		}
+
+		result = tx.Preload(clause.Associations).Find(&tag, uuid)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// the parent may have been deleted or purged while the tag was in the trash
+		err := certBackend.withTx(tx).linkTagParent(&tag)
+		if err != nil {
+			certBackend.logger.Error("RestoreTagByID: error linking parent", "id", uuid, "err", err)
+		}
+
+		return certBackend.withTx(tx).recordAudit(AuditActionRestore, AuditObjectTag, uuid, nil, NewAuditTagState(&tag))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// PurgeTrash permanently deletes the certs and tags deleted before
+func (certBackend *CertBackend) PurgeTrash(before time.Time) (*TrashPurgeReport, error) {
+	certBackend.logger.Debug("PurgeTrash: Purging trash...", "before", before)
+
+	// times are compared as strings by sqlite
+	before = before.UTC()
+
+	certs, err := certBackend.purgeCerts(certBackend.db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before))
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := certBackend.purgeTags(certBackend.db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TrashPurgeReport{Certificates: certs, Tags: tags}, nil
+}
@@ -0,0 +1,177 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTrash(t *testing.T) {
+
+	certBakcend := setupTestCertBackend(t)
+
+	report, err := certBakcend.CreateCertificateBundle(testRootPEM+testIntermediatePEM+testLeafPEM, nil)
+	if err != nil || len(report.Created) != 3 {
+		t.Logf("Error creating bundle %v", err)
+		t.FailNow()
+	}
+	root, intermediate, leaf := report.Created[0], report.Created[1], report.Created[2]
+
+	tag, err := certBakcend.CreateTag("web")
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.SetCertTagNameByID(leaf.ID, "web")
+	if err != nil {
+		t.Logf("Error tagging cert %s", err.Error())
+		t.FailNow()
+	}
+
+	// a deleted cert moves to the trash
+	err = certBakcend.DeleteCertByID(intermediate.ID)
+	if err != nil {
+		t.Logf("Error deleting cert %s", err.Error())
+		t.FailNow()
+	}
+
+	_, err = certBakcend.GetCertByID(intermediate.ID)
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting trashed cert not found got %v", err)
+		t.FailNow()
+	}
+
+	trashed, err := certBakcend.ListTrashedCerts()
+	if err != nil || len(trashed) != 1 || trashed[0].ID != intermediate.ID || !trashed[0].DeletedAt.Valid {
+		t.Logf("Unexpected trash %v (%v)", trashed, err)
+		t.FailNow()
+	}
+
+	cert, _ := certBakcend.GetCertByID(leaf.ID)
+	if cert.IssuerID != nil {
+		t.Logf("Expecting no issuer for leaf of trashed cert")
+		t.FailNow()
+	}
+
+	// restoring links the issuers again
+	restored, err := certBakcend.RestoreCertByID(intermediate.ID)
+	if err != nil || restored.IssuerID == nil || *restored.IssuerID != root.ID {
+		t.Logf("Unexpected restored cert %v (%v)", restored, err)
+		t.FailNow()
+	}
+
+	cert, _ = certBakcend.GetCertByID(leaf.ID)
+	if cert.IssuerID == nil || *cert.IssuerID != intermediate.ID {
+		t.Logf("Expecting leaf issuer to be restored")
+		t.FailNow()
+	}
+
+	_, err = certBakcend.RestoreCertByID(intermediate.ID)
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting not found for cert not in the trash got %v", err)
+		t.FailNow()
+	}
+
+	// creating a trashed cert again restores it with its tags
+	err = certBakcend.DeleteCertByID(leaf.ID)
+	if err != nil {
+		t.Logf("Error deleting cert %s", err.Error())
+		t.FailNow()
+	}
+
+	cert, err = certBakcend.CreateCertificate(testLeafPEM)
+	if err != nil || cert.ID != leaf.ID || len(cert.Tags) != 1 {
+		t.Logf("Expecting restored cert got %v (%v)", cert, err)
+		t.FailNow()
+	}
+
+	// the name of a trashed tag is still taken
+	err = certBakcend.DeleteTagByID(tag.ID)
+	if err != nil {
+		t.Logf("Error deleting tag %s", err.Error())
+		t.FailNow()
+	}
+
+	cert, _ = certBakcend.GetCertByID(leaf.ID)
+	if len(cert.Tags) != 0 {
+		t.Logf("Expecting no tag on cert got %v", cert.Tags)
+		t.FailNow()
+	}
+
+	certs, _ := certBakcend.ListCertsWithFilter(CertFilter{Tag: "web"})
+	if len(certs) != 0 {
+		t.Logf("Expecting no cert for trashed tag got %d", len(certs))
+		t.FailNow()
+	}
+
+	_, err = certBakcend.CreateTag("web")
+	if _, ok := err.(*DBObjectAlreadyExist); !ok {
+		t.Logf("Expecting already exist for trashed tag got %v", err)
+		t.FailNow()
+	}
+
+	tags, err := certBakcend.ListTrashedTags()
+	if err != nil || len(tags) != 1 {
+		t.Logf("Unexpected trashed tags %v (%v)", tags, err)
+		t.FailNow()
+	}
+
+	restoredTag, err := certBakcend.RestoreTagByID(tag.ID)
+	if err != nil || len(restoredTag.Certificates) != 1 {
+		t.Logf("Unexpected restored tag %v (%v)", restoredTag, err)
+		t.FailNow()
+	}
+
+	_, err = certBakcend.RestoreTagByID(uuid.New())
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting not found got %v", err)
+		t.FailNow()
+	}
+
+	// purge only the records deleted before the retention
+	err = certBakcend.DeleteCertByID(leaf.ID)
+	if err == nil {
+		err = certBakcend.DeleteTagByID(tag.ID)
+	}
+	if err != nil {
+		t.Logf("Error deleting %s", err.Error())
+		t.FailNow()
+	}
+
+	purged, err := certBakcend.PurgeTrash(time.Now().Add(-time.Hour))
+	if err != nil || purged.Certificates != 0 || purged.Tags != 0 {
+		t.Logf("Expecting nothing purged got %v (%v)", purged, err)
+		t.FailNow()
+	}
+
+	purged, err = certBakcend.PurgeTrash(time.Now().Add(time.Minute))
+	if err != nil || purged.Certificates != 1 || purged.Tags != 1 {
+		t.Logf("Expecting 1 cert and 1 tag purged got %v (%v)", purged, err)
+		t.FailNow()
+	}
+
+	// the associated records are purged with the cert
+	for _, model := range []interface{}{&SubjectAltName{}, &LintFinding{}} {
+		var count int64
+		certBakcend.db.Model(model).Where("certificate_id = ?", leaf.ID).Count(&count)
+		if count != 0 {
+			t.Logf("Expecting no %T left got %d", model, count)
+			t.FailNow()
+		}
+	}
+
+	_, err = certBakcend.RestoreCertByID(leaf.ID)
+	if _, ok := err.(*DBObjectNotFound); !ok {
+		t.Logf("Expecting purged cert not found got %v", err)
+		t.FailNow()
+	}
+
+	// the name of a purged tag is free
+	_, err = certBakcend.CreateTag("web")
+	if err != nil {
+		t.Logf("Error creating tag %s", err.Error())
+		t.FailNow()
+	}
+}
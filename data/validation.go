@@ -43,6 +43,8 @@ type Validation struct {
 func NewValidation() *Validation {
 	validate := validator.New()
 	// validate.RegisterValidation("kbo", validateKBO)
+	validate.RegisterValidation("label_key", validateLabelKey)
+	validate.RegisterValidation("label_value", validateLabelValue)
 
 	return &Validation{validate}
 }
@@ -68,3 +70,13 @@ func (v *Validation) Validate(i interface{}) ValidationErrors {
 
 	return returnErrs
 }
+
+// validateLabelKey validate the syntax of a label key (see IsLabelKey)
+func validateLabelKey(fl validator.FieldLevel) bool {
+	return IsLabelKey(fl.Field().String())
+}
+
+// validateLabelValue validate the syntax of a label value (see IsLabelValue)
+func validateLabelValue(fl validator.FieldLevel) bool {
+	return IsLabelValue(fl.Field().String())
+}
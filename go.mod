@@ -11,8 +11,11 @@ require (
 	github.com/gorilla/mux v1.8.0
 	github.com/hashicorp/go-hclog v0.15.0
 	github.com/leodido/go-urn v1.2.1 // indirect
+	github.com/prometheus/client_golang v1.11.0
 	github.com/stretchr/testify v1.7.0
+	go.mozilla.org/pkcs7 v0.9.0
 	gopkg.in/gormigrate.v1 v1.6.0
 	gorm.io/driver/sqlite v1.1.4
 	gorm.io/gorm v1.20.12
+	software.sslmate.com/src/go-pkcs12 v0.2.0
 )
@@ -2,6 +2,8 @@ package api
 
 import (
 	"net/http"
+
+	"github.com/google/uuid"
 )
 
 // API Object
@@ -16,6 +18,13 @@ func NewAPI() *API {
 func (api *API) CommonAPIMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Content-Type", "application/json")
+
+		// identify the request in the audit log and the response
+		if r.Header.Get(RequestIDHeader) == "" {
+			r.Header.Set(RequestIDHeader, uuid.New().String())
+		}
+		w.Header().Set(RequestIDHeader, r.Header.Get(RequestIDHeader))
+
 		next.ServeHTTP(w, r)
 	})
 }
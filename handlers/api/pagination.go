@@ -0,0 +1,92 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+const (
+	// DefaultPageLimit the number of items of a page when the request has no limit
+	// (0 returns all the items, as the lists before the pagination)
+	DefaultPageLimit = 0
+	// MaxPageLimit the max number of items of a page
+	MaxPageLimit = 1000
+	// TotalCountHeader the header with the total number of items of a paginated list
+	TotalCountHeader = "X-Total-Count"
+)
+
+// GetListOptionsFromRequest return the page and the order of a list from the request query
+// limit (DefaultPageLimit if missing, at most MaxPageLimit), offset and sort
+func GetListOptionsFromRequest(r *http.Request) (data.ListOptions, error) {
+	query := r.URL.Query()
+
+	opts := data.ListOptions{
+		Limit: DefaultPageLimit,
+		Sort:  query.Get("sort"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > MaxPageLimit {
+			return opts, fmt.Errorf("invalid limit '%s' expecting 1 to %d", limit, MaxPageLimit)
+		}
+		opts.Limit = l
+	}
+
+	if offset := query.Get("offset"); offset != "" {
+		o, err := strconv.Atoi(offset)
+		if err != nil || o < 0 {
+			return opts, fmt.Errorf("invalid offset '%s'", offset)
+		}
+		opts.Offset = o
+	}
+
+	return opts, nil
+}
+
+// SetPageHeaders set the total number of items and the links to the first, previous, next and
+// last pages (RFC 8288) of the list, must be called before writing the status code
+func SetPageHeaders(rw http.ResponseWriter, r *http.Request, opts data.ListOptions, total int64) {
+
+	rw.Header().Set(TotalCountHeader, strconv.FormatInt(total, 10))
+
+	if opts.Limit <= 0 {
+		return
+	}
+
+	pageURL := func(offset int) string {
+		query := r.URL.Query()
+		query.Set("limit", strconv.Itoa(opts.Limit))
+		query.Set("offset", strconv.Itoa(offset))
+		return r.URL.Path + "?" + query.Encode()
+	}
+
+	last := 0
+	if total > 0 {
+		last = int((total - 1) / int64(opts.Limit) * int64(opts.Limit))
+	}
+
+	links := []string{
+		fmt.Sprintf(`<%s>; rel="first"`, pageURL(0)),
+	}
+
+	if opts.Offset > 0 {
+		prev := opts.Offset - opts.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(prev)))
+	}
+
+	if int64(opts.Offset+opts.Limit) < total {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(opts.Offset+opts.Limit)))
+	}
+
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(last)))
+
+	rw.Header().Set("Link", strings.Join(links, ", "))
+}
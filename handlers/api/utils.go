@@ -7,6 +7,16 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+const (
+	// RequestIDHeader the header with the id of the request (set if missing)
+	RequestIDHeader = "X-Request-ID"
+	// ActorHeader the header with the authenticated user (set by the authenticating proxy)
+	ActorHeader = "X-Remote-User"
+	// AnonymousActor the actor of the requests without ActorHeader
+	AnonymousActor = "anonymous"
 )
 
 // GetIDFromRequest return uuid from request
@@ -24,6 +34,20 @@ func GetIDFromRequest(r *http.Request) (uuid.UUID, error) {
 	return id, nil
 }
 
+// GetAuditContextFromRequest return the actor and the id of the request for the audit log
+func GetAuditContextFromRequest(r *http.Request) data.AuditContext {
+
+	actor := r.Header.Get(ActorHeader)
+	if actor == "" {
+		actor = AnonymousActor
+	}
+
+	return data.AuditContext{
+		Actor:     actor,
+		RequestID: r.Header.Get(RequestIDHeader),
+	}
+}
+
 // ToJSON serializes the given interface into a string based JSON format
 func ToJSON(i interface{}, w io.Writer) error {
 	e := json.NewEncoder(w)
@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-hclog"
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// APIAuditHandler handler for audit log api
+type APIAuditHandler struct {
+	logger      hclog.Logger
+	v           *data.Validation
+	certBackend *data.CertBackend
+	signingKey  ed25519.PrivateKey
+}
+
+// NewAPIAuditHandler create a new APIAuditHandler
+// signingKey signs the exports of the audit chain
+func NewAPIAuditHandler(l hclog.Logger, v *data.Validation, certBackend *data.CertBackend, signingKey ed25519.PrivateKey) *APIAuditHandler {
+	return &APIAuditHandler{
+		logger:      l,
+		v:           v,
+		certBackend: certBackend,
+		signingKey:  signingKey,
+	}
+}
+
+// getAuditFilterFromRequest parse the audit filter from the query of the request
+func getAuditFilterFromRequest(r *http.Request) (data.AuditFilter, error) {
+
+	query := r.URL.Query()
+	filter := data.AuditFilter{
+		Actor:      query.Get("actor"),
+		Action:     data.AuditAction(query.Get("action")),
+		ObjectType: data.AuditObjectType(query.Get("object_type")),
+		RequestID:  query.Get("request_id"),
+	}
+
+	if id := query.Get("object_id"); id != "" {
+		objectID, err := uuid.Parse(id)
+		if err != nil {
+			return filter, fmt.Errorf("invalid object_id '%s'", id)
+		}
+		filter.ObjectID = &objectID
+	}
+
+	for param, field := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s '%s' (expecting RFC3339)", param, value)
+		}
+		*field = &t
+	}
+
+	return filter, nil
+}
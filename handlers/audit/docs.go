@@ -0,0 +1,93 @@
+// Package audit  of Audit API
+//
+// Documentation for Audit API
+//
+//	Schemes: http
+//	BasePath: /api/beta2/
+//	Version: 0.1.0
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+// swagger:meta
+package audit
+
+import (
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/handlers/api"
+)
+
+// Generic error message returned as a string
+// swagger:response errorResponse
+type errorResponseWrapper struct {
+	// Description of the error
+	// in: body
+	Body api.GenericAPIError
+}
+
+// A list of audit events
+// swagger:response auditEventListResponse
+type auditEventListResponseWrapper struct {
+	// the changes
+	// in: body
+	Body []data.AuditEvent
+}
+
+// The result of the verification of the audit chain
+// swagger:response auditChainVerificationResponse
+type auditChainVerificationResponseWrapper struct {
+	// the verification
+	// in: body
+	Body data.AuditChainVerification
+}
+
+// The audit chain as JSON Lines: the events then a data.AuditChainSignature line
+// swagger:response auditChainExportResponse
+type auditChainExportResponseWrapper struct {
+	// the export
+	// in: body
+	Body string
+}
+
+// swagger:parameters ListAuditEvents
+type auditFilterParamsWrapper struct {
+	// The user or service that did the change
+	// in: query
+	// required: false
+	Actor string `json:"actor"`
+	// The change: create, update, set_tags, remove_tags, set_labels, remove_labels, rename, merge, delete, restore or purge
+	// in: query
+	// required: false
+	Action string `json:"action"`
+	// The type of the changed object: certificate, tag or auto_tag_rule
+	// in: query
+	// required: false
+	ObjectType string `json:"object_type"`
+	// The id of the changed object
+	// in: query
+	// required: false
+	ObjectID string `json:"object_id"`
+	// The id of the request that did the change (X-Request-ID header)
+	// in: query
+	// required: false
+	RequestID string `json:"request_id"`
+	// The changes done at or after (RFC3339)
+	// in: query
+	// required: false
+	Since string `json:"since"`
+	// The changes done before (RFC3339)
+	// in: query
+	// required: false
+	Until string `json:"until"`
+}
+
+// swagger:parameters GetCertificateHistory GetTagHistory
+type auditObjectIDParamsWrapper struct {
+	// The id of the object
+	// in: path
+	// required: true
+	ID string `json:"id"`
+}
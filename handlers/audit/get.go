@@ -0,0 +1,219 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/handlers/api"
+)
+
+// swagger:route GET /audit Audit ListAuditEvents
+// Return the changes matching the filters (the last change first)
+// responses:
+//	200: auditEventListResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// ListAuditEvents handles GET requests
+func (h *APIAuditHandler) ListAuditEvents(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	filter, err := getAuditFilterFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	events, err := h.certBackend.ListAuditEvents(filter)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+		}
+
+		h.logger.Error("ListAuditEvents: Error listing events", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error listing audit events",
+		}
+	}
+
+	return h.writeEvents(rw, events)
+}
+
+// swagger:route GET /audit/certificate/{id} Audit GetCertificateHistory
+// Return the changes of the certificate (the first change first)
+// responses:
+//	200: auditEventListResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// GetCertificateHistory handles GET requests
+func (h *APIAuditHandler) GetCertificateHistory(rw http.ResponseWriter, r *http.Request) *api.APIError {
+	return h.getObjectHistory(rw, r, data.AuditObjectCertificate)
+}
+
+// swagger:route GET /audit/tag/{id} Audit GetTagHistory
+// Return the changes of the tag (the first change first)
+// responses:
+//	200: auditEventListResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// GetTagHistory handles GET requests
+func (h *APIAuditHandler) GetTagHistory(rw http.ResponseWriter, r *http.Request) *api.APIError {
+	return h.getObjectHistory(rw, r, data.AuditObjectTag)
+}
+
+// swagger:route GET /audit/verify Audit VerifyAuditChain
+// Verify the hash chain of the audit log and return the gaps and the modified events
+// responses:
+//	200: auditChainVerificationResponse
+//  500: errorResponse
+
+// VerifyAuditChain handles GET requests
+func (h *APIAuditHandler) VerifyAuditChain(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	verification, err := h.certBackend.VerifyAuditChain()
+	if err != nil {
+		h.logger.Error("VerifyAuditChain: Error verifying audit chain", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error verifying audit chain",
+		}
+	}
+
+	if !verification.Valid {
+		h.logger.Warn("VerifyAuditChain: audit chain is not valid", "errors", len(verification.Errors))
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(verification, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("VerifyAuditChain: Error Serializing JSON", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /audit/export Audit ExportAuditChain
+// Export the audit log as JSON Lines: one event per line (first event first)
+// followed by a line signed with the Ed25519 audit signing key
+// produces:
+// - application/x-ndjson
+// responses:
+//	200: auditChainExportResponse
+//  500: errorResponse
+
+// ExportAuditChain handles GET requests
+func (h *APIAuditHandler) ExportAuditChain(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	// the export is buffered to return an error before writing the status code
+	var export bytes.Buffer
+	sig, err := h.certBackend.ExportAuditChain(&export, h.signingKey)
+	if err != nil {
+		h.logger.Error("ExportAuditChain: Error exporting audit chain", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error exporting audit chain",
+		}
+	}
+
+	filename := fmt.Sprintf("audit-%s.jsonl", sig.ExportedAt.Format("20060102T150405Z"))
+	rw.Header().Set("Content-Type", "application/x-ndjson")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	_, err = export.WriteTo(rw)
+	if err != nil {
+		// the client went away, nothing more can be sent
+		h.logger.Error("ExportAuditChain: Error writing export", "err", err)
+	}
+
+	return nil
+}
+
+// Helper Functions
+
+// getObjectHistory writes the changes of the object of objectType with the id of the request
+func (h *APIAuditHandler) getObjectHistory(rw http.ResponseWriter, r *http.Request, objectType data.AuditObjectType) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	events, err := h.certBackend.ListAuditEventsByObject(objectType, uuid)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("getObjectHistory: object not found", "objectType", objectType, "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+		}
+
+		h.logger.Error("getObjectHistory: Error listing events", "objectType", objectType, "uuid", uuid, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error searching for the history of %s id=%s", objectType, uuid.String()),
+		}
+	}
+
+	return h.writeEvents(rw, events)
+}
+
+// writeEvents writes the events
+func (h *APIAuditHandler) writeEvents(rw http.ResponseWriter, events []*data.AuditEvent) *api.APIError {
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err := api.ToJSON(events, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("writeEvents: Error Serializing JSON", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package autotag
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// APIAutoTagHandler handler for auto-tag rule api
+type APIAutoTagHandler struct {
+	logger      hclog.Logger
+	v           *data.Validation
+	certBackend *data.CertBackend
+}
+
+// NewAPIAutoTagHandler create a new APIAutoTagHandler
+func NewAPIAutoTagHandler(l hclog.Logger, v *data.Validation, certBackend *data.CertBackend) *APIAutoTagHandler {
+	return &APIAutoTagHandler{
+		logger:      l,
+		v:           v,
+		certBackend: certBackend,
+	}
+}
+
+// APIAutoTagRuleInput input for auto-tag rule Creation API
+// the certificates matching all the conditions are tagged with the tag
+type APIAutoTagRuleInput struct {
+	Name string `json:"name" validate:"required,max=50"`
+	// the name of the tag of the matching certificates
+	Tag string `json:"tag" validate:"required,max=50"`
+	// the regexp the Issuer must match (e.g. ^CN=Example Internal CA)
+	IssuerPattern string `json:"issuer_pattern" validate:"max=256"`
+	// the regexp the Subject must match
+	SubjectPattern string `json:"subject_pattern" validate:"max=256"`
+	// the regexp one of the SANs must match (e.g. \.payments\.example\.com$)
+	SANPattern string `json:"san_pattern" validate:"max=256"`
+	// the public key algorithm (RSA, ECDSA, Ed25519, DSA)
+	PublicKeyAlgorithm string `json:"public_key_algorithm" validate:"omitempty,oneof=RSA ECDSA Ed25519 DSA"`
+	// if the certificate is a CA
+	IsCA *bool `json:"is_ca"`
+	// the Extended Key Usage the certificate must have (e.g. serverAuth or an OID)
+	ExtKeyUsage string `json:"ext_key_usage" validate:"max=100"`
+}
+
+// APIAutoTagRuleInputKey key to find the APIAutoTagRuleInput in request context
+type APIAutoTagRuleInputKey struct{}
+
+// getDryRunFromRequest return the dry_run query parameter (false if absent)
+func getDryRunFromRequest(r *http.Request) (bool, error) {
+
+	value := r.URL.Query().Get("dry_run")
+	if value == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid dry_run '%s'", value)
+	}
+
+	return dryRun, nil
+}
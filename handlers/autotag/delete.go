@@ -0,0 +1,57 @@
+package autotag
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/handlers/api"
+)
+
+// swagger:route DELETE /autotag/rules/{id} AutoTag DeleteAutoTagRule
+// Permanently delete the auto-tag rule (the certificates keep their tags)
+// responses:
+//	204: noContentResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// DeleteAutoTagRule handles DELETE requests
+func (h *APIAutoTagHandler) DeleteAutoTagRule(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	err = h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).DeleteAutoTagRuleByID(uuid)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("DeleteAutoTagRule: object not found", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+		}
+
+		h.logger.Error("DeleteAutoTagRule: Error deleting rule", "uuid", uuid, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error deleting auto-tag rule id=%s", uuid.String()),
+		}
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
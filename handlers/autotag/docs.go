@@ -0,0 +1,82 @@
+// Package autotag  of AutoTag API
+//
+// Documentation for AutoTag API
+//
+//	Schemes: http
+//	BasePath: /api/beta2/
+//	Version: 0.1.0
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+// swagger:meta
+package autotag
+
+import (
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/handlers/api"
+)
+
+// Generic error message returned as a string
+// swagger:response errorResponse
+type errorResponseWrapper struct {
+	// Description of the error
+	// in: body
+	Body api.GenericAPIError
+}
+
+// A list of auto-tag rules
+// swagger:response autoTagRuleListResponse
+type autoTagRuleListResponseWrapper struct {
+	// the rules
+	// in: body
+	Body []data.AutoTagRule
+}
+
+// Data structure representing a single auto-tag rule
+// swagger:response autoTagRuleResponse
+type autoTagRuleResponseWrapper struct {
+	// an auto-tag rule
+	// in: body
+	Body data.AutoTagRule
+}
+
+// The certificates tagged by auto-tag rules
+// swagger:response autoTagReportResponse
+type autoTagReportResponseWrapper struct {
+	// the tagged certificates per rule
+	// in: body
+	Body data.AutoTagReport
+}
+
+// No content is returned by this API endpoint
+// swagger:response noContentResponse
+type noContentResponseWrapper struct {
+}
+
+// swagger:parameters CreateAutoTagRule
+type autoTagRuleCreateParamsWrapper struct {
+	// Auto-tag rule data structure to Create
+	// in: body
+	// required: true
+	Body APIAutoTagRuleInput
+}
+
+// swagger:parameters ApplyAutoTagRule ApplyAutoTagRules
+type autoTagDryRunParamsWrapper struct {
+	// Only return the certificates that would be tagged
+	// in: query
+	// required: false
+	DryRun bool `json:"dry_run"`
+}
+
+// swagger:parameters GetAutoTagRule DeleteAutoTagRule ApplyAutoTagRule
+type autoTagRuleIDParamsWrapper struct {
+	// The id of the auto-tag rule
+	// in: path
+	// required: true
+	ID string `json:"id"`
+}
@@ -0,0 +1,105 @@
+package autotag
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/handlers/api"
+)
+
+// swagger:route GET /autotag/rules AutoTag ListAutoTagRules
+// Return the auto-tag rules ordered by name
+// responses:
+//	200: autoTagRuleListResponse
+//  500: errorResponse
+
+// ListAutoTagRules handles GET requests
+func (h *APIAutoTagHandler) ListAutoTagRules(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	rules, err := h.certBackend.ListAutoTagRules()
+	if err != nil {
+		h.logger.Error("ListAutoTagRules: Error listing rules", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error listing auto-tag rules",
+		}
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(rules, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("ListAutoTagRules: Error Serializing JSON", "rules", rules, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /autotag/rules/{id} AutoTag GetAutoTagRule
+// Return the auto-tag rule
+// responses:
+//	200: autoTagRuleResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// GetAutoTagRule handles GET requests
+func (h *APIAutoTagHandler) GetAutoTagRule(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	rule, err := h.certBackend.GetAutoTagRuleByID(uuid)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("GetAutoTagRule: object not found", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+		}
+
+		h.logger.Error("GetAutoTagRule: Error getting rule", "uuid", uuid, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error getting auto-tag rule id=%s", uuid.String()),
+		}
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(rule, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("GetAutoTagRule: Error Serializing JSON", "rule", rule, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package autotag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vdbulcke/cert-manager/handlers/api"
+)
+
+// MiddlewareValidateAutoTagRuleInput validates the auto-tag rule in the request and calls next if ok
+func (h *APIAutoTagHandler) MiddlewareValidateAutoTagRuleInput(next http.Handler) http.Handler {
+	return http.Handler(api.Handler{Handler: func(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+		// declaring the ruleInput
+		ruleInput := &APIAutoTagRuleInput{}
+
+		// parsing rule intput
+		err := api.FromJSON(ruleInput, r.Body)
+		if err != nil {
+			h.logger.Error("MiddlewareValidateAutoTagRuleInput: error deserializing json", "err", err)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: "error deserializing json",
+			}
+		}
+
+		// validate input
+		errs := h.v.Validate(ruleInput)
+		if len(errs) != 0 {
+			h.logger.Debug("MiddlewareValidateAutoTagRuleInput: invalid input", "ruleInput", ruleInput, "errs", errs)
+			return &api.APIError{
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: fmt.Sprintf("Validation error : %s", strings.Join(errs.Errors(), "\n")),
+			}
+		}
+
+		// add the input to the context
+		ctx := context.WithValue(r.Context(), APIAutoTagRuleInputKey{}, *ruleInput)
+		r = r.WithContext(ctx)
+
+		// Call the next handler, which can be another middleware in the chain, or the final handler.
+		next.ServeHTTP(rw, r)
+		return nil
+
+	}})
+}
@@ -0,0 +1,205 @@
+package autotag
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/handlers/api"
+)
+
+// swagger:route POST /autotag/rules AutoTag CreateAutoTagRule
+// Return the newly created auto-tag rule, the rule tags the certificates created afterwards
+// (see ApplyAutoTagRule to tag the existing certificates)
+// responses:
+//	201: autoTagRuleResponse
+//	404: errorResponse
+//	409: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// CreateAutoTagRule handles POST requests
+func (h *APIAutoTagHandler) CreateAutoTagRule(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	// look up rule input (set by the middleware) in request context
+	ruleInput, ok := r.Context().Value(APIAutoTagRuleInputKey{}).(APIAutoTagRuleInput)
+	if !ok {
+		return &api.APIError{
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error parsing input",
+		}
+	}
+
+	rule, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).CreateAutoTagRule(data.AutoTagRule{
+		Name:               ruleInput.Name,
+		TagName:            ruleInput.Tag,
+		IssuerPattern:      ruleInput.IssuerPattern,
+		SubjectPattern:     ruleInput.SubjectPattern,
+		SANPattern:         ruleInput.SANPattern,
+		PublicKeyAlgorithm: ruleInput.PublicKeyAlgorithm,
+		IsCA:               ruleInput.IsCA,
+		ExtKeyUsage:        ruleInput.ExtKeyUsage,
+	})
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: fmt.Sprintf("Tag not found %s", ruleInput.Tag),
+			}
+
+		} else if _, ok := err.(*data.DBObjectAlreadyExist); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusConflict,
+				Type:    &api.ValidationError{},
+				Message: fmt.Sprintf("the name is already used by the auto-tag rule id=%s", err.(*data.DBObjectAlreadyExist).ID),
+			}
+
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+		}
+
+		h.logger.Error("CreateAutoTagRule: Error creating rule", "ruleInput", ruleInput, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error creating auto-tag rule",
+		}
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusCreated)
+	err = api.ToJSON(rule, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("CreateAutoTagRule: Error Serializing JSON", "rule", rule, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route POST /autotag/rules/{id}/apply AutoTag ApplyAutoTagRule
+// Apply the auto-tag rule to the existing certificates and return the tagged certificates
+// (with dry_run=true the certificates that would be tagged are returned without being tagged)
+// responses:
+//	200: autoTagReportResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// ApplyAutoTagRule handles POST requests
+func (h *APIAutoTagHandler) ApplyAutoTagRule(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	dryRun, err := getDryRunFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	report, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).ApplyAutoTagRuleByID(uuid, dryRun)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("ApplyAutoTagRule: object not found", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+		}
+
+		h.logger.Error("ApplyAutoTagRule: Error applying rule", "uuid", uuid, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error applying auto-tag rule id=%s", uuid.String()),
+		}
+	}
+
+	return h.writeReport(rw, report)
+}
+
+// swagger:route POST /autotag/apply AutoTag ApplyAutoTagRules
+// Apply all the auto-tag rules to the existing certificates and return the tagged certificates
+// (with dry_run=true the certificates that would be tagged are returned without being tagged)
+// responses:
+//	200: autoTagReportResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// ApplyAutoTagRules handles POST requests
+func (h *APIAutoTagHandler) ApplyAutoTagRules(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	dryRun, err := getDryRunFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	report, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).ApplyAutoTagRules(dryRun)
+	if err != nil {
+		h.logger.Error("ApplyAutoTagRules: Error applying rules", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error applying auto-tag rules",
+		}
+	}
+
+	return h.writeReport(rw, report)
+}
+
+// writeReport write the report of applying auto-tag rules
+func (h *APIAutoTagHandler) writeReport(rw http.ResponseWriter, report *data.AutoTagReport) *api.APIError {
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err := api.ToJSON(report, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("writeReport: Error Serializing JSON", "report", report, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
@@ -22,9 +22,13 @@ func NewAPICertificateHandler(l hclog.Logger, v *data.Validation, certBackend *d
 }
 
 // APICertificateInput input struct for certificate API
+// either pem (PEM text) or data (base64 encoded DER, PKCS#7 or PKCS#12) is required
 type APICertificateInput struct {
-	Pem  string   `json:"pem" validate:"required"`
-	Tags []string `json:"tags" `
+	Pem      string   `json:"pem" validate:"required_without=Data"`
+	Data     string   `json:"data" validate:"required_without=Pem,omitempty,base64"`
+	Format   string   `json:"format" validate:"omitempty,oneof=pem der pkcs7 pkcs12"`
+	Password string   `json:"password" `
+	Tags     []string `json:"tags" `
 }
 
 // APICertificateTagInput input struct for certificate API
@@ -32,8 +36,38 @@ type APICertificateTagInput struct {
 	Tags []string `json:"tags" validate:"required"`
 }
 
+// APICertificateLabelInput input struct for setting the labels of a certificate
+type APICertificateLabelInput struct {
+	Labels map[string]string `json:"labels" validate:"required,dive,keys,label_key,endkeys,label_value"`
+}
+
+// APICertificateLabelKeysInput input struct for removing the labels of a certificate
+type APICertificateLabelKeysInput struct {
+	Keys []string `json:"keys" validate:"required,dive,label_key"`
+}
+
+// APICertificateBulkTagInput input struct for adding and removing tags on many certificates
+// the certificates with the ids, the certificates with the fingerprints and the certificates
+// matching the search query are updated
+type APICertificateBulkTagInput struct {
+	AddTags      []string `json:"add_tags" `
+	RemoveTags   []string `json:"remove_tags" `
+	IDs          []string `json:"ids" validate:"max=1000,dive,uuid"`
+	Fingerprints []string `json:"fingerprints" validate:"max=1000"`
+	Query        string   `json:"query" validate:"max=2048"`
+}
+
 // APICertificateKey place holder for storing APICertificateInput in request context
 type APICertificateKey struct{}
 
 // APICertificateTagKey place holder for storing APICertificateTagInput in request context
 type APICertificateTagKey struct{}
+
+// APICertificateLabelKey place holder for storing APICertificateLabelInput in request context
+type APICertificateLabelKey struct{}
+
+// APICertificateLabelKeysKey place holder for storing APICertificateLabelKeysInput in request context
+type APICertificateLabelKeysKey struct{}
+
+// APICertificateBulkTagKey place holder for storing APICertificateBulkTagInput in request context
+type APICertificateBulkTagKey struct{}
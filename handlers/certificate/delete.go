@@ -51,7 +51,7 @@ func (h *APICertificateHandler) DeleteCertificateTagsByID(rw http.ResponseWriter
 	}
 
 	// lookup this certificate
-	cert, err := h.certBackend.DeleteCertificateTagsByID(uuid, certTagInput.Tags)
+	cert, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).DeleteCertificateTagsByID(uuid, certTagInput.Tags)
 	if err != nil {
 		if _, ok := err.(*data.DBObjectNotFound); ok {
 			h.logger.Debug("DeleteCertificateByID: object not found", "uuid", uuid)
@@ -96,7 +96,7 @@ func (h *APICertificateHandler) DeleteCertificateTagsByID(rw http.ResponseWriter
 }
 
 // swagger:route DELETE /certificate/DeleteCertificateByID/{id} Certificate DeleteCertificateByID
-// Deletes certificate from DB
+// Moves certificate to the trash (see RestoreCertificate)
 // responses:
 //	204: noContentResponse
 //	404: errorResponse
@@ -117,7 +117,7 @@ func (h *APICertificateHandler) DeleteCertificateByID(rw http.ResponseWriter, r
 	}
 
 	// lookup this certificate
-	err = h.certBackend.DeleteCertByID(uuid)
+	err = h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).DeleteCertByID(uuid)
 	if err != nil {
 		if _, ok := err.(*data.DBObjectNotFound); ok {
 			h.logger.Debug("DeleteCertificateByID: object not found", "uuid", uuid)
@@ -148,3 +148,92 @@ func (h *APICertificateHandler) DeleteCertificateByID(rw http.ResponseWriter, r
 
 	return nil
 }
+
+// swagger:route DELETE /certificate/DeleteCertificateLabelsByID/{id} Certificate DeleteCertificateLabelsByID
+// Return the certificate without the labels with the keys (the missing keys are ignored)
+// responses:
+//	202: certificateResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// DeleteCertificateLabelsByID handles DELETE requests
+func (h *APICertificateHandler) DeleteCertificateLabelsByID(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	// getting uuid
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	// look up the label keys input (set by the middleware) in request context
+	certLabelKeysInput, ok := r.Context().Value(APICertificateLabelKeysKey{}).(APICertificateLabelKeysInput)
+	if !ok {
+		return &api.APIError{
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error parsing input",
+		}
+	}
+
+	if len(certLabelKeysInput.Keys) == 0 {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: "Empty list",
+		}
+	}
+
+	// update the labels of the certificate
+	cert, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).DeleteCertificateLabelsByID(uuid, certLabelKeysInput.Keys)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		// default
+		h.logger.Debug("DeleteCertificateLabelsByID: unexpected error updating certificate", "uuid", uuid, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error updating the labels of certificate id=%s", uuid.String()),
+		}
+
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusAccepted)
+	err = api.ToJSON(cert, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("DeleteCertificateLabelsByID: Error Serializing JSON", "cert", cert, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
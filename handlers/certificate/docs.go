@@ -40,6 +40,18 @@ type certListResponseWrapper struct {
 	Body []data.Certificates
 }
 
+// A page of certificates
+// swagger:response certificatePageResponse
+type certificatePageResponseWrapper struct {
+	// The total number of certificates matching the filters
+	XTotalCount int `json:"X-Total-Count"`
+	// The links to the first, prev, next and last pages
+	Link string `json:"Link"`
+	// the certificates of the page
+	// in: body
+	Body []data.Certificate
+}
+
 // Data structure representing a single certificate
 // swagger:response certificateResponse
 type certResponseWrapper struct {
@@ -48,6 +60,46 @@ type certResponseWrapper struct {
 	Body data.Certificate
 }
 
+// Report of the import of a bundle of certificates
+// swagger:response bundleResponse
+type bundleResponseWrapper struct {
+	// the created, already existing, failed and skipped entries
+	// in: body
+	Body data.BundleReport
+}
+
+// Report of a bulk tag operation
+// swagger:response bulkTagResponse
+type bulkTagResponseWrapper struct {
+	// the result per certificate
+	// in: body
+	Body data.BulkTagReport
+}
+
+// The chain of a certificate and its validation
+// swagger:response chainResponse
+type chainResponseWrapper struct {
+	// the hops of the chain
+	// in: body
+	Body data.CertificateChain
+}
+
+// The delivery states of the expiry notifications of a certificate
+// swagger:response notificationDeliveryListResponse
+type notificationDeliveryListResponseWrapper struct {
+	// the deliveries per threshold and channel
+	// in: body
+	Body []data.NotificationDelivery
+}
+
+// A list of certificates matching a hostname
+// swagger:response hostnameMatchListResponse
+type hostnameMatchListResponseWrapper struct {
+	// the matching SAN and the remaining validity of the certificates
+	// in: body
+	Body []data.HostnameMatch
+}
+
 // No content is returned by this API endpoint
 // swagger:response noContentResponse
 type noContentResponseWrapper struct {
@@ -69,11 +121,272 @@ type certificateUpdateTagsParamsWrapper struct {
 	Body APICertificateTagInput
 }
 
-// swagger:parameters GetCertificateByID GetCertificateByFingerprint UpdateCertificateTag DeleteCertificateTagsByID DeleteCertificateByID
+// swagger:parameters UpdateCertificateLabels
+type certificateUpdateLabelsParamsWrapper struct {
+	// Labels (key/value) to set on the Certificate
+	// in: body
+	// required: true
+	Body APICertificateLabelInput
+}
+
+// swagger:parameters DeleteCertificateLabelsByID
+type certificateDeleteLabelsParamsWrapper struct {
+	// Keys of the labels to remove from the Certificate
+	// in: body
+	// required: true
+	Body APICertificateLabelKeysInput
+}
+
+// swagger:parameters BulkUpdateCertificateTags
+type certificateBulkTagParamsWrapper struct {
+	// Tags to Add and Remove on the Certificates with the ids, the fingerprints or matching the search query
+	// in: body
+	// required: true
+	Body APICertificateBulkTagInput
+}
+
+// swagger:parameters GetCertificateByID GetCertificateByFingerprint UpdateCertificateTag DeleteCertificateTagsByID UpdateCertificateLabels DeleteCertificateLabelsByID DeleteCertificateByID GetCertificateIssuer ListIssuedCertificates GetCertificateChain GetCertificateChainByID ListNotificationDeliveries RestoreCertificate GetCertificateLineage
 type certificateIDParamsWrapper struct {
 	// The id of the certificate for which the operation relates
-	// could be uuid or sha256 fingerprint
+	// could be uuid or sha256, sha1 or md5 fingerprint
 	// in: path
 	// required: true
 	ID string `json:"id"`
 }
+
+// swagger:parameters GetCertificateChain GetCertificateChainByID
+type certificateChainParamsWrapper struct {
+	// The time at which the chain is validated (RFC3339, default now)
+	// in: query
+	// required: false
+	Time string `json:"time"`
+	// The hostname the certificate must be valid for
+	// in: query
+	// required: false
+	Hostname string `json:"hostname"`
+	// Only the certificates with this tag are trust anchors (default all self-signed certificates)
+	// in: query
+	// required: false
+	TrustAnchorTag string `json:"trust_anchor_tag"`
+}
+
+// swagger:parameters GetCertificateByIssuerSerial
+type certificateIssuerSerialParamsWrapper struct {
+	// The Issuer of the certificate as RFC 4514 (e.g. CN=Example CA,O=Example) or OpenSSL
+	// oneline (e.g. /O=Example/CN=Example CA), in any order and case insensitive
+	// in: query
+	// required: true
+	Issuer string `json:"issuer"`
+	// The hex serial number of the certificate (case and ':' insensitive)
+	// in: query
+	// required: true
+	Serial string `json:"serial"`
+}
+
+// swagger:parameters ListCerts ListCertsByExpiry
+type certificateFilterParamsWrapper struct {
+	// The Subject to match, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	Subject string `json:"subject"`
+	// The Issuer to match, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	Issuer string `json:"issuer"`
+	// The signature algorithm (e.g. SHA256-RSA)
+	// in: query
+	// required: false
+	SignatureAlgorithm string `json:"signature_algorithm"`
+	// The type of the SAN to match: dns, ip, email or uri
+	// in: query
+	// required: false
+	SANType string `json:"san_type"`
+	// The value of the SAN to match, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	SAN string `json:"san"`
+	// The public key algorithm: RSA, ECDSA, Ed25519 or DSA
+	// in: query
+	// required: false
+	PublicKeyAlgorithm string `json:"public_key_algorithm"`
+	// The public key size in bits
+	// in: query
+	// required: false
+	PublicKeySize int `json:"public_key_size"`
+	// The base64 sha256 of the SubjectPublicKeyInfo (HPKP pin)
+	// in: query
+	// required: false
+	SPKISHA256 string `json:"spki_sha256"`
+	// The comma separated Key Usages the cert must all have (e.g. digitalSignature,keyEncipherment)
+	// in: query
+	// required: false
+	KeyUsage string `json:"key_usage"`
+	// The comma separated Extended Key Usages the cert must all have (e.g. serverAuth or an OID)
+	// in: query
+	// required: false
+	ExtKeyUsage string `json:"ext_key_usage"`
+	// If the cert is a CA
+	// in: query
+	// required: false
+	IsCA bool `json:"is_ca"`
+	// The path length constraint of the CA
+	// in: query
+	// required: false
+	MaxPathLen int `json:"max_path_len"`
+	// If the cert has name constraints
+	// in: query
+	// required: false
+	HasNameConstraints bool `json:"has_name_constraints"`
+	// The certs expiring before now plus this duration (e.g. 30d, 12h, -7d)
+	// in: query
+	// required: false
+	Before string `json:"before"`
+	// The certs expiring after now plus this duration (e.g. 30d, 12h, -7d)
+	// in: query
+	// required: false
+	After string `json:"after"`
+	// The certs expiring between now and now plus this duration (e.g. 30d)
+	// in: query
+	// required: false
+	Within string `json:"within"`
+	// The certs valid from this date or after (RFC3339)
+	// in: query
+	// required: false
+	IssuedAfter string `json:"issued_after"`
+	// The certs valid from this date or before (RFC3339)
+	// in: query
+	// required: false
+	IssuedBefore string `json:"issued_before"`
+	// The certs expiring at or after this date (RFC3339)
+	// in: query
+	// required: false
+	ExpiresAfter string `json:"expires_after"`
+	// The certs expiring at or before this date (RFC3339)
+	// in: query
+	// required: false
+	ExpiresBefore string `json:"expires_before"`
+	// The name of a tag of the certs
+	// in: query
+	// required: false
+	Tag string `json:"tag"`
+	// Also match the certs tagged with a descendant of the tag (e.g. payments/prod/eu for payments)
+	// in: query
+	// required: false
+	IncludeDescendants bool `json:"include_descendants"`
+	// The label selector the labels of the certs must match: comma separated key=value, key!=value,
+	// key in (v1,v2), key notin (v1,v2), key (exists) and !key (not exists) e.g. env=prod,tier!=db
+	// in: query
+	// required: false
+	LabelSelector string `json:"label_selector"`
+	// The id of the issuer of the certs
+	// in: query
+	// required: false
+	IssuerID string `json:"issuer_id"`
+	// If the certs were replaced by a renewal
+	// in: query
+	// required: false
+	Replaced bool `json:"replaced"`
+	// The Subject Common Name, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	SubjectCN string `json:"subject_cn"`
+	// The Subject Organization, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	SubjectO string `json:"subject_o"`
+	// The Subject Organizational Unit, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	SubjectOU string `json:"subject_ou"`
+	// The Subject Country, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	SubjectC string `json:"subject_c"`
+	// The Subject Locality, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	SubjectL string `json:"subject_l"`
+	// The Subject State or Province, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	SubjectST string `json:"subject_st"`
+	// The Subject serialNumber attribute, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	SubjectSerialNumber string `json:"subject_serial_number"`
+	// The Issuer Common Name, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	IssuerCN string `json:"issuer_cn"`
+	// The Issuer Organization, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	IssuerO string `json:"issuer_o"`
+	// The Issuer Organizational Unit, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	IssuerOU string `json:"issuer_ou"`
+	// The Issuer Country, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	IssuerC string `json:"issuer_c"`
+	// The Issuer Locality, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	IssuerL string `json:"issuer_l"`
+	// The Issuer State or Province, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	IssuerST string `json:"issuer_st"`
+	// The Issuer serialNumber attribute, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	IssuerSerialNumber string `json:"issuer_serial_number"`
+}
+
+// swagger:parameters ListCerts SearchCertificates
+type certificatePageParamsWrapper struct {
+	// The max number of certificates, at most 1000 (default all the certificates)
+	// in: query
+	// required: false
+	Limit int `json:"limit"`
+	// The number of certificates to skip
+	// in: query
+	// required: false
+	Offset int `json:"offset"`
+	// The sort key (id, sha256, subject, issuer, serial_number, not_before, not_after, signature_algorithm,
+	// public_key_algorithm, public_key_size, created_at), prefixed with '-' for descending (default created_at)
+	// in: query
+	// required: false
+	Sort string `json:"sort"`
+}
+
+// swagger:parameters SearchCertificates
+type certificateSearchParamsWrapper struct {
+	// The search query: conditions field:value (match, '*' matches any sequence of characters),
+	// field=value, field!=value, and field<value, field<=value, field>value, field>=value on the dates
+	// (2006-01-02 or RFC3339) and numbers, combined with AND, OR, NOT and parentheses.
+	// The fields: subject, issuer, subject.cn (o, ou, c, l, st, serial_number), issuer.cn (...), serial,
+	// sha256, sha1, md5, spki_sha256, sigalg, key, key_curve, key_size, key_usage, ext_key_usage,
+	// not_before, not_after, created_at, is_ca, self_signed, max_path_len, san, san.dns (ip, email, uri),
+	// tag, status (valid, expiring, expired, not-yet-valid) and replaced
+	// in: query
+	// required: true
+	Query string `json:"q"`
+}
+
+// swagger:parameters ListCertsForHostname
+type certificateHostnameParamsWrapper struct {
+	// The hostname (e.g. api.eu.example.com, internationalized names as A-labels) or IP address
+	// in: query
+	// required: true
+	Hostname string `json:"hostname"`
+	// The time at which the certificates are valid as RFC3339 (default now)
+	// in: query
+	// required: false
+	Time string `json:"time"`
+	// Also return the expired and not yet valid certificates (default false)
+	// in: query
+	// required: false
+	IncludeInvalid bool `json:"include_invalid"`
+}
@@ -75,7 +75,7 @@ func (h *APICertificateHandler) GetCertificateByID(rw http.ResponseWriter, r *ht
 }
 
 // swagger:route GET /certificate/GetCertificateByFingerprint/{id} Certificate GetCertificateByFingerprint
-// Return certificate from the database
+// Return certificate from the database by its SHA256, SHA1 or MD5 fingerprint
 // responses:
 //	200: certificateResponse
 //	404: errorResponse
@@ -85,18 +85,18 @@ func (h *APICertificateHandler) GetCertificateByID(rw http.ResponseWriter, r *ht
 // GetCertificateByFingerprint handles GET requests
 func (h *APICertificateHandler) GetCertificateByFingerprint(rw http.ResponseWriter, r *http.Request) *api.APIError {
 
-	fingerprint, err := getFingerprintFromRequest(r)
+	fingerprint, algorithm, err := getFingerprintFromRequest(r)
 	if err != nil {
 		return &api.APIError{
 			Err:     err,
 			Code:    http.StatusBadRequest,
 			Type:    &api.ValidationError{},
-			Message: fmt.Sprintf("Invalid SHA256 fingerprint found in request %s", r.URL.String()),
+			Message: fmt.Sprintf("Invalid SHA256, SHA1 or MD5 fingerprint found in request %s", r.URL.String()),
 		}
 	}
 
 	// lookup this certificate
-	cert, err := h.certBackend.GetCertByFingerprint(fingerprint)
+	cert, err := h.certBackend.GetCertByFingerprintAlgorithm(algorithm, fingerprint)
 
 	if err != nil {
 		if _, ok := err.(*data.DBObjectNotFound); ok {
@@ -142,18 +142,118 @@ func (h *APICertificateHandler) GetCertificateByFingerprint(rw http.ResponseWrit
 	return nil
 }
 
+// swagger:route GET /certificate/GetCertificateByIssuerSerial Certificate GetCertificateByIssuerSerial
+// Return certificate from the database by its issuer and serial number
+// responses:
+//	200: certificateResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// GetCertificateByIssuerSerial handles GET requests
+func (h *APICertificateHandler) GetCertificateByIssuerSerial(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	issuer, serial, err := getIssuerSerialFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	// lookup this certificate
+	cert, err := h.certBackend.GetCertByIssuerAndSerial(issuer, serial)
+
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("GetCertificateByIssuerSerial: object not found", "issuer", issuer, "serial", serial)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+		}
+
+		// default
+		h.logger.Debug("GetCertificateByIssuerSerial: unexpected error searching for certificate", "issuer", issuer, "serial", serial, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error searching for certificate issuer=%s serial=%s", issuer, serial),
+		}
+
+	}
+
+	h.logger.Debug("GetCertificateByIssuerSerial: Found cert", "cert", cert)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(cert, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("GetCertificateByIssuerSerial: Error Serializing JSON", "cert", cert, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
 // swagger:route GET /certificate/ListCerts Certificate ListCerts
-// Return a list of Certificates from the database
+// Return a page of the Certificates from the database matching the filters
+// the total number of matching certificates is returned in the X-Total-Count header
+// and the links to the other pages in the Link header
 // responses:
-//	200: certificateListResponse
+//	200: certificatePageResponse
+//  400: errorResponse
 //  404: errorResponse
 //  500: errorResponse
 
 // ListCerts handles GET requests
 func (h *APICertificateHandler) ListCerts(rw http.ResponseWriter, r *http.Request) *api.APIError {
 
-	// lookup all certificate
-	certs, err := h.certBackend.ListCerts()
+	filter, err := getCertFilterFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	opts, err := api.GetListOptionsFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	// lookup the page of certificates matching the filter
+	certs, total, err := h.certBackend.ListCertsPage(filter, opts)
 	if err != nil {
 		if _, ok := err.(*data.DBObjectNotFound); ok {
 			h.logger.Debug("ListCerts: object not found")
@@ -165,6 +265,14 @@ func (h *APICertificateHandler) ListCerts(rw http.ResponseWriter, r *http.Reques
 				Message: err.Error(),
 			}
 
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
 		}
 		// default
 		h.logger.Debug("ListCerts: unexpected error searching for certificate", "err", err)
@@ -178,9 +286,10 @@ func (h *APICertificateHandler) ListCerts(rw http.ResponseWriter, r *http.Reques
 
 	}
 
-	h.logger.Debug("ListCerts: Found certs", "certs", certs)
+	h.logger.Debug("ListCerts: Found certs", "count", len(certs), "total", total)
 
 	// Write Status code
+	api.SetPageHeaders(rw, r, opts, total)
 	rw.WriteHeader(http.StatusOK)
 	err = api.ToJSON(certs, rw)
 	if err != nil {
@@ -196,3 +305,596 @@ func (h *APICertificateHandler) ListCerts(rw http.ResponseWriter, r *http.Reques
 
 	return nil
 }
+
+// swagger:route GET /certificate/SearchCertificates Certificate SearchCertificates
+// Return a page of the Certificates matching the search query q, e.g.
+// san:*.example.com AND issuer.o:"Let's Encrypt" AND not_after<2026-12-01 AND tag:prod AND NOT tag:decommissioned
+// the total number of matching certificates is returned in the X-Total-Count header
+// and the links to the other pages in the Link header
+// responses:
+//	200: certificatePageResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// SearchCertificates handles GET requests
+func (h *APICertificateHandler) SearchCertificates(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	opts, err := api.GetListOptionsFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	// lookup the page of certificates matching the query
+	certs, total, err := h.certBackend.SearchCertsPage(r.URL.Query().Get("q"), opts)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
+		}
+		// default
+		h.logger.Debug("SearchCertificates: unexpected error searching for certificate", "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error searching for certificate",
+		}
+
+	}
+
+	h.logger.Debug("SearchCertificates: Found certs", "count", len(certs), "total", total)
+
+	// Write Status code
+	api.SetPageHeaders(rw, r, opts, total)
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(certs, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("SearchCertificates: Error Serializing JSON", "certs", certs, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /certificate/ListCertsByExpiry Certificate ListCertsByExpiry
+// Return a list of Certificates from the database matching the filters ordered by expiry (not_after)
+// responses:
+//	200: certificateListResponse
+//  400: errorResponse
+//  404: errorResponse
+//  500: errorResponse
+
+// ListCertsByExpiry handles GET requests
+func (h *APICertificateHandler) ListCertsByExpiry(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	filter, err := getCertFilterFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	// lookup all certificate matching the filter
+	certs, err := h.certBackend.ListCertsByExpiry(filter)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("ListCertsByExpiry: object not found")
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
+		}
+		// default
+		h.logger.Debug("ListCertsByExpiry: unexpected error searching for certificate", "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error searching for certificate"),
+		}
+
+	}
+
+	h.logger.Debug("ListCertsByExpiry: Found certs", "certs", certs)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(certs, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("ListCertsByExpiry: Error Serializing JSON", "certs", certs, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /certificate/GetCertificateIssuer/{id} Certificate GetCertificateIssuer
+// Return the stored certificate that issued the certificate
+// responses:
+//	200: certificateResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// GetCertificateIssuer handles GET requests
+func (h *APICertificateHandler) GetCertificateIssuer(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	// lookup the issuer of this certificate
+	cert, err := h.certBackend.GetCertIssuerByID(uuid)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("GetCertificateIssuer: object not found", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		// default
+		h.logger.Debug("GetCertificateIssuer: unexpected error searching for certificate", "uuid", uuid, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error searching for issuer of certificate id=%s", uuid.String()),
+		}
+
+	}
+
+	h.logger.Debug("GetCertificateIssuer: Found cert", "cert", cert)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(cert, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("GetCertificateIssuer: Error Serializing JSON", "cert", cert, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /certificate/ListIssuedCertificates/{id} Certificate ListIssuedCertificates
+// Return the list of stored certificates issued by the CA certificate
+// responses:
+//	200: certificateListResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// ListIssuedCertificates handles GET requests
+func (h *APICertificateHandler) ListIssuedCertificates(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	// lookup the certificates issued by this CA
+	certs, err := h.certBackend.ListCertsIssuedByID(uuid)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("ListIssuedCertificates: object not found", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		// default
+		h.logger.Debug("ListIssuedCertificates: unexpected error searching for certificates", "uuid", uuid, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error searching for certificates issued by id=%s", uuid.String()),
+		}
+
+	}
+
+	h.logger.Debug("ListIssuedCertificates: Found certs", "certs", certs)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(certs, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("ListIssuedCertificates: Error Serializing JSON", "certs", certs, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /certificate/GetCertificateChain/{id} Certificate GetCertificateChain
+// Return the chain of the certificate up to a root built from the stored certificates
+// and its validation at the given time for the given hostname
+// responses:
+//	200: chainResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// swagger:route GET /certificate/{id}/chain Certificate GetCertificateChainByID
+// Return the chain of the certificate, same as GetCertificateChain
+// responses:
+//	200: chainResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// GetCertificateChain handles GET requests
+func (h *APICertificateHandler) GetCertificateChain(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	opts, err := getChainOptionsFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	// build the chain of this certificate
+	chain, err := h.certBackend.BuildCertChain(uuid, opts)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("GetCertificateChain: object not found", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		// default
+		h.logger.Debug("GetCertificateChain: unexpected error building chain", "uuid", uuid, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error building chain for certificate id=%s", uuid.String()),
+		}
+
+	}
+
+	h.logger.Debug("GetCertificateChain: Built chain", "chain", chain)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(chain, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("GetCertificateChain: Error Serializing JSON", "chain", chain, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /certificate/ListNotificationDeliveries/{id} Certificate ListNotificationDeliveries
+// Return the delivery states of the expiry notifications of the certificate
+// responses:
+//	200: notificationDeliveryListResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// ListNotificationDeliveries handles GET requests
+func (h *APICertificateHandler) ListNotificationDeliveries(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	// lookup the notification deliveries of this certificate
+	deliveries, err := h.certBackend.ListNotificationDeliveriesByCertID(uuid)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("ListNotificationDeliveries: object not found", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		// default
+		h.logger.Debug("ListNotificationDeliveries: unexpected error searching for deliveries", "uuid", uuid, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error searching for notification deliveries of id=%s", uuid.String()),
+		}
+
+	}
+
+	h.logger.Debug("ListNotificationDeliveries: Found deliveries", "deliveries", deliveries)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(deliveries, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("ListNotificationDeliveries: Error Serializing JSON", "deliveries", deliveries, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /certificate/ListTrashedCertificates Certificate ListTrashedCertificates
+// Return the deleted certificates that were not purged yet (last deleted first)
+// responses:
+//	200: certificateListResponse
+//  500: errorResponse
+
+// ListTrashedCertificates handles GET requests
+func (h *APICertificateHandler) ListTrashedCertificates(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	// lookup the certificates in the trash
+	certs, err := h.certBackend.ListTrashedCerts()
+	if err != nil {
+		h.logger.Debug("ListTrashedCertificates: unexpected error searching for certificates", "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error searching for certificates in the trash",
+		}
+	}
+
+	h.logger.Debug("ListTrashedCertificates: Found certificates", "certs", certs)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(certs, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("ListTrashedCertificates: Error Serializing JSON", "certs", certs, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /certificate/ListCertsForHostname Certificate ListCertsForHostname
+// Return the certificates with a SAN matching the hostname or IP (RFC 6125 wildcards)
+// valid now (or at the given time) ranked by remaining validity, the longest first
+// responses:
+//	200: hostnameMatchListResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// ListCertsForHostname handles GET requests
+func (h *APICertificateHandler) ListCertsForHostname(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	hostname, opts, err := getHostnameLookupOptionsFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	// lookup the certificates for this hostname
+	matches, err := h.certBackend.ListCertsForHostname(hostname, opts)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
+		}
+		// default
+		h.logger.Debug("ListCertsForHostname: unexpected error searching for certificate", "hostname", hostname, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error searching for certificate hostname=%s", hostname),
+		}
+
+	}
+
+	h.logger.Debug("ListCertsForHostname: Found certs", "hostname", hostname, "count", len(matches))
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(matches, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("ListCertsForHostname: Error Serializing JSON", "matches", matches, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /certificate/GetCertificateLineage/{id} Certificate GetCertificateLineage
+// Return the renewals of the logical certificate of the certificate: its predecessors,
+// the certificate and its successors (the first issued first)
+// responses:
+//	200: certificateListResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// GetCertificateLineage handles GET requests
+func (h *APICertificateHandler) GetCertificateLineage(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	certs, err := h.certBackend.GetCertLineageByID(uuid)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("GetCertificateLineage: object not found", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		// default
+		h.logger.Debug("GetCertificateLineage: unexpected error searching for certificates", "uuid", uuid, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error searching for the renewals of id=%s", uuid.String()),
+		}
+
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(certs, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("GetCertificateLineage: Error Serializing JSON", "certs", certs, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
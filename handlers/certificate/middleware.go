@@ -32,7 +32,8 @@ func (h *APICertificateHandler) MiddlewareValidateCertificateInput(next http.Han
 		// validate input
 		errs := h.v.Validate(certInput)
 		if len(errs) != 0 {
-			h.logger.Debug("MiddlewareValidateCertificateInput: invalid input", "certInput", certInput, "errs", errs)
+			// not logging certInput as it may contain a PKCS#12 password
+			h.logger.Debug("MiddlewareValidateCertificateInput: invalid input", "errs", errs)
 			return &api.APIError{
 				Code:    http.StatusBadRequest,
 				Type:    &api.ValidationError{},
@@ -92,3 +93,129 @@ func (h *APICertificateHandler) MiddlewareValidateCertificateTagInput(next http.
 
 	}})
 }
+
+// MiddlewareValidateCertificateLabelInput validates the labels in the request and calls next if ok
+func (h *APICertificateHandler) MiddlewareValidateCertificateLabelInput(next http.Handler) http.Handler {
+	return http.Handler(api.Handler{Handler: func(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+		// declaring the certLabelInput
+		certLabelInput := &APICertificateLabelInput{}
+
+		// parsing labels intput
+		err := api.FromJSON(certLabelInput, r.Body)
+		if err != nil {
+			h.logger.Error("MiddlewareValidateCertificateLabelInput: error deserializing json", "err", err)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: "error deserializing json",
+			}
+		}
+
+		// validate input (the syntax of the label keys and values)
+		errs := h.v.Validate(certLabelInput)
+		if len(errs) != 0 {
+			h.logger.Debug("MiddlewareValidateCertificateLabelInput: invalid input", "certLabelInput", certLabelInput, "errs", errs)
+			return &api.APIError{
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: fmt.Sprintf("Validation error : %s", strings.Join(errs.Errors(), "\n")),
+			}
+		}
+
+		// add the input to the context
+		ctx := context.WithValue(r.Context(), APICertificateLabelKey{}, *certLabelInput)
+		r = r.WithContext(ctx)
+
+		// Call the next handler, which can be another middleware in the chain, or the final handler.
+		next.ServeHTTP(rw, r)
+		return nil
+
+	}})
+}
+
+// MiddlewareValidateCertificateLabelKeysInput validates the label keys in the request and calls next if ok
+func (h *APICertificateHandler) MiddlewareValidateCertificateLabelKeysInput(next http.Handler) http.Handler {
+	return http.Handler(api.Handler{Handler: func(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+		// declaring the certLabelKeysInput
+		certLabelKeysInput := &APICertificateLabelKeysInput{}
+
+		// parsing label keys intput
+		err := api.FromJSON(certLabelKeysInput, r.Body)
+		if err != nil {
+			h.logger.Error("MiddlewareValidateCertificateLabelKeysInput: error deserializing json", "err", err)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: "error deserializing json",
+			}
+		}
+
+		// validate input (the syntax of the label keys and values)
+		errs := h.v.Validate(certLabelKeysInput)
+		if len(errs) != 0 {
+			h.logger.Debug("MiddlewareValidateCertificateLabelKeysInput: invalid input", "certLabelKeysInput", certLabelKeysInput, "errs", errs)
+			return &api.APIError{
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: fmt.Sprintf("Validation error : %s", strings.Join(errs.Errors(), "\n")),
+			}
+		}
+
+		// add the input to the context
+		ctx := context.WithValue(r.Context(), APICertificateLabelKeysKey{}, *certLabelKeysInput)
+		r = r.WithContext(ctx)
+
+		// Call the next handler, which can be another middleware in the chain, or the final handler.
+		next.ServeHTTP(rw, r)
+		return nil
+
+	}})
+}
+
+// MiddlewareValidateCertificateBulkTagInput validates the bulk tag operation in the request and calls next if ok
+func (h *APICertificateHandler) MiddlewareValidateCertificateBulkTagInput(next http.Handler) http.Handler {
+	return http.Handler(api.Handler{Handler: func(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+		// declaring the certBulkTagInput
+		certBulkTagInput := &APICertificateBulkTagInput{}
+
+		// parsing bulk tag intput
+		err := api.FromJSON(certBulkTagInput, r.Body)
+		if err != nil {
+			h.logger.Error("MiddlewareValidateCertificateBulkTagInput: error deserializing json", "err", err)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: "error deserializing json",
+			}
+		}
+
+		// validate input
+		errs := h.v.Validate(certBulkTagInput)
+		if len(errs) != 0 {
+			h.logger.Debug("MiddlewareValidateCertificateBulkTagInput: invalid input", "certBulkTagInput", certBulkTagInput, "errs", errs)
+			return &api.APIError{
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: fmt.Sprintf("Validation error : %s", strings.Join(errs.Errors(), "\n")),
+			}
+		}
+
+		// add the input to the context
+		ctx := context.WithValue(r.Context(), APICertificateBulkTagKey{}, *certBulkTagInput)
+		r = r.WithContext(ctx)
+
+		// Call the next handler, which can be another middleware in the chain, or the final handler.
+		next.ServeHTTP(rw, r)
+		return nil
+
+	}})
+}
@@ -1,6 +1,8 @@
 package certificate
 
 import (
+	"encoding/base64"
+	"fmt"
 	"net/http"
 
 	"github.com/vdbulcke/cert-manager/data"
@@ -9,8 +11,12 @@ import (
 
 // swagger:route POST /certificate/CreateCertificate Certificate CreateCertificate
 // Return newly Created Certificate from the database
+// A bundle with more than one PEM block, or a base64 encoded DER, PKCS#7 or
+// PKCS#12 (with password) in data, creates a certificate for every certificate
+// found and returns a report of the import (private keys are never stored)
 // responses:
 //	200: certificateResponse
+//  207: bundleResponse
 //  409: certificateResponse
 //	404: errorResponse
 //  400: errorResponse
@@ -22,6 +28,11 @@ func (h *APICertificateHandler) CreateCertificate(rw http.ResponseWriter, r *htt
 	// look up cert input (set by the middleware) in request context
 	certInput := r.Context().Value(APICertificateKey{}).(APICertificateInput)
 
+	// binary formats (DER, PKCS#7, PKCS#12) are imported as a bundle
+	if certInput.Data != "" {
+		return h.createCertificateFromData(rw, r, certInput)
+	}
+
 	// checking certInput contains a pem
 	if certInput.Pem == "" {
 		return &api.APIError{
@@ -31,6 +42,12 @@ func (h *APICertificateHandler) CreateCertificate(rw http.ResponseWriter, r *htt
 		}
 	}
 
+	// anything else than a single certificate is imported block by block
+	blocks := data.DecodePEMBlocks(certInput.Pem)
+	if len(blocks) > 1 || (len(blocks) == 1 && blocks[0].Type != "CERTIFICATE") {
+		return h.createCertificateBundle(rw, r, certInput)
+	}
+
 	// setting default status code
 	statusCode := http.StatusCreated
 	var cert *data.Certificate
@@ -38,10 +55,18 @@ func (h *APICertificateHandler) CreateCertificate(rw http.ResponseWriter, r *htt
 
 	// checking if the input contains tags
 	if len(certInput.Tags) != 0 {
-		cert, err = h.certBackend.CreateCertificateWithTags(certInput.Pem, certInput.Tags)
+		cert, err = h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).CreateCertificateWithTags(certInput.Pem, certInput.Tags)
 		if err != nil {
 			if _, ok := err.(*data.DBObjectAlreadyExist); ok {
 				statusCode = http.StatusConflict
+			} else if _, ok := err.(*data.DBObjectNotFound); ok {
+				return &api.APIError{
+					Err:     err,
+					Code:    http.StatusNotFound,
+					Type:    &api.ObjectNotFoundError{},
+					Message: fmt.Sprintf("Tag not found %s", err.(*data.DBObjectNotFound).ID),
+				}
+
 			} else if _, ok := err.(*data.DBObjectValidationError); ok {
 				return &api.APIError{
 					Err:     err,
@@ -63,7 +88,7 @@ func (h *APICertificateHandler) CreateCertificate(rw http.ResponseWriter, r *htt
 
 	} else {
 		// creating a cert without tags
-		cert, err = h.certBackend.CreateCertificate(certInput.Pem)
+		cert, err = h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).CreateCertificate(certInput.Pem)
 		if err != nil {
 			if _, ok := err.(*data.DBObjectAlreadyExist); ok {
 				statusCode = http.StatusConflict
@@ -107,3 +132,78 @@ func (h *APICertificateHandler) CreateCertificate(rw http.ResponseWriter, r *htt
 }
 
 // Helper Functions
+
+// createCertificateBundle creates all certificates of a bundle and writes the report
+func (h *APICertificateHandler) createCertificateBundle(rw http.ResponseWriter, r *http.Request, certInput APICertificateInput) *api.APIError {
+
+	report, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).CreateCertificateBundle(certInput.Pem, certInput.Tags)
+
+	return h.writeBundleReport(rw, report, err)
+}
+
+// createCertificateFromData creates all certificates of a base64 encoded container and writes the report
+func (h *APICertificateHandler) createCertificateFromData(rw http.ResponseWriter, r *http.Request, certInput APICertificateInput) *api.APIError {
+
+	raw, err := base64.StdEncoding.DecodeString(certInput.Data)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: "data is not valid base64",
+		}
+	}
+
+	report, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).CreateCertificateFromData(raw, data.CertificateFormat(certInput.Format), certInput.Password, certInput.Tags)
+
+	return h.writeBundleReport(rw, report, err)
+}
+
+// writeBundleReport writes the report of a bundle import or the error
+func (h *APICertificateHandler) writeBundleReport(rw http.ResponseWriter, report *data.BundleReport, err error) *api.APIError {
+
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: fmt.Sprintf("Tag not found %s", err.(*data.DBObjectNotFound).ID),
+			}
+		}
+
+		if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+		}
+
+		// default
+		return &api.APIError{Err: err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error creating certs",
+		}
+	}
+
+	h.logger.Debug("CreateCertificate: Created bundle", "report", report)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusMultiStatus)
+	err = api.ToJSON(report, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("CreateCertificate: Error Serializing JSON", "report", report, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
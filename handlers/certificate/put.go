@@ -51,7 +51,7 @@ func (h *APICertificateHandler) UpdateCertificateTag(rw http.ResponseWriter, r *
 	}
 
 	// update certificate with new tags
-	cert, err := h.certBackend.SetCertTagsNameByID(uuid, certTagInput.Tags)
+	cert, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).SetCertTagsNameByID(uuid, certTagInput.Tags)
 	if err != nil {
 		if _, ok := err.(*data.DBObjectNotFound); ok {
 			return &api.APIError{
@@ -98,3 +98,241 @@ func (h *APICertificateHandler) UpdateCertificateTag(rw http.ResponseWriter, r *
 
 	return nil
 }
+
+// swagger:route PUT /certificate/RestoreCertificate/{id} Certificate RestoreCertificate
+// Return the certificate restored from the trash
+// responses:
+//	202: certificateResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// RestoreCertificate handles PUT requests
+func (h *APICertificateHandler) RestoreCertificate(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	// restore the certificate
+	cert, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).RestoreCertByID(uuid)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("RestoreCertificate: object not found in the trash", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		// default
+		h.logger.Debug("RestoreCertificate: unexpected error restoring certificate", "uuid", uuid, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error restoring certificate id=%s", uuid.String()),
+		}
+
+	}
+
+	h.logger.Debug("RestoreCertificate: Restored certificate", "cert", cert)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusAccepted)
+	err = api.ToJSON(cert, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("RestoreCertificate: Error Serializing JSON", "cert", cert, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route PUT /certificate/UpdateCertificateLabels/{id} Certificate UpdateCertificateLabels
+// Return the certificate with the labels set (the value of an existing key is replaced)
+// responses:
+//	202: certificateResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// UpdateCertificateLabels handles PUT requests
+func (h *APICertificateHandler) UpdateCertificateLabels(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	// getting uuid
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	// look up the labels input (set by the middleware) in request context
+	certLabelInput, ok := r.Context().Value(APICertificateLabelKey{}).(APICertificateLabelInput)
+	if !ok {
+		return &api.APIError{
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error parsing input",
+		}
+	}
+
+	if len(certLabelInput.Labels) == 0 {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: "Empty list",
+		}
+	}
+
+	// update the labels of the certificate
+	cert, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).SetCertLabelsByID(uuid, certLabelInput.Labels)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		// default
+		h.logger.Debug("UpdateCertificateLabels: unexpected error updating certificate", "uuid", uuid, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error updating the labels of certificate id=%s", uuid.String()),
+		}
+
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusAccepted)
+	err = api.ToJSON(cert, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("UpdateCertificateLabels: Error Serializing JSON", "cert", cert, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route PUT /certificate/BulkUpdateCertificateTags Certificate BulkUpdateCertificateTags
+// Add and remove tags on the certificates with the ids, the fingerprints or matching the search query
+// in one transaction, return the result per certificate (an unknown tag or more than 1000 selected
+// certificates aborts the operation)
+// responses:
+//	202: bulkTagResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// BulkUpdateCertificateTags handles PUT requests
+func (h *APICertificateHandler) BulkUpdateCertificateTags(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	// look up the bulk tag input (set by the middleware) in request context
+	certBulkTagInput, ok := r.Context().Value(APICertificateBulkTagKey{}).(APICertificateBulkTagInput)
+	if !ok {
+		return &api.APIError{
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error parsing input",
+		}
+	}
+
+	selection, err := getBulkTagSelection(certBulkTagInput)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	// update the tags of the certificates
+	report, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).BulkUpdateCertTags(selection, certBulkTagInput.AddTags, certBulkTagInput.RemoveTags)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: fmt.Sprintf("Tag not found %s", err.(*data.DBObjectNotFound).ID),
+			}
+
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		// default
+		h.logger.Debug("BulkUpdateCertificateTags: unexpected error updating certificates", "selection", selection, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error updating the tags of the certificates",
+		}
+
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusAccepted)
+	err = api.ToJSON(report, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("BulkUpdateCertificateTags: Error Serializing JSON", "report", report, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
@@ -4,28 +4,47 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/vdbulcke/cert-manager/data"
 )
 
-// getFingerprintFromRequest return uuid from request
-func getFingerprintFromRequest(r *http.Request) (string, error) {
+// getFingerprintFromRequest return the fingerprint and its algorithm from request
+func getFingerprintFromRequest(r *http.Request) (string, data.FingerprintAlgorithm, error) {
 	// parse the id from the url
 	vars := mux.Vars(r)
 
 	// convert the id
 	id := vars["id"]
 
-	// convert sha256 to lower case without ':' in hex
+	// convert fingerprint to lower case without ':' in hex
 	fingerprint := convertSHA256(id)
-	// check
-	err := validateSHA256(fingerprint)
+	// check and detect the algorithm (sha256, sha1 or md5)
+	algorithm, err := data.DetectFingerprintAlgorithm(fingerprint)
 	if err != nil {
-		return "", nil
+		return "", "", err
 	}
 
-	return fingerprint, nil
+	return fingerprint, algorithm, nil
+}
+
+// getIssuerSerialFromRequest return the issuer and the serial number from the request query
+func getIssuerSerialFromRequest(r *http.Request) (string, string, error) {
+	query := r.URL.Query()
+
+	issuer := query.Get("issuer")
+	serial := query.Get("serial")
+	if issuer == "" || serial == "" {
+		return "", "", fmt.Errorf("issuer and serial are required")
+	}
+
+	// convert serial to lower case without ':' in hex
+	return issuer, convertSHA256(serial), nil
 }
 
 func convertSHA256(sha256 string) string {
@@ -49,3 +68,298 @@ func validateSHA256(sha256 string) error {
 	return nil
 
 }
+
+// getBulkTagSelection return the certificates selected by the bulk tag input
+func getBulkTagSelection(input APICertificateBulkTagInput) (data.BulkTagSelection, error) {
+
+	selection := data.BulkTagSelection{Query: input.Query}
+
+	for _, id := range input.IDs {
+		certID, err := uuid.Parse(id)
+		if err != nil {
+			return selection, fmt.Errorf("invalid id '%s'", id)
+		}
+
+		selection.IDs = append(selection.IDs, certID)
+	}
+
+	for _, fingerprint := range input.Fingerprints {
+		// convert fingerprint to lower case without ':' in hex
+		selection.Fingerprints = append(selection.Fingerprints, convertSHA256(fingerprint))
+	}
+
+	return selection, nil
+}
+
+// getChainOptionsFromRequest return the chain options from the request query
+// time (RFC3339), hostname and trust_anchor_tag
+func getChainOptionsFromRequest(r *http.Request) (data.ChainOptions, error) {
+	query := r.URL.Query()
+
+	opts := data.ChainOptions{
+		Hostname:       query.Get("hostname"),
+		TrustAnchorTag: query.Get("trust_anchor_tag"),
+	}
+
+	if t := query.Get("time"); t != "" {
+		verifyTime, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return opts, fmt.Errorf("invalid time '%s' expected RFC3339", t)
+		}
+		opts.Time = verifyTime
+	}
+
+	return opts, nil
+}
+
+// getHostnameLookupOptionsFromRequest return the hostname and the lookup options
+// from the request query hostname, time (RFC3339) and include_invalid
+func getHostnameLookupOptionsFromRequest(r *http.Request) (string, data.HostnameLookupOptions, error) {
+	query := r.URL.Query()
+
+	opts := data.HostnameLookupOptions{}
+
+	hostname := query.Get("hostname")
+	if hostname == "" {
+		return hostname, opts, fmt.Errorf("missing hostname")
+	}
+
+	if t := query.Get("time"); t != "" {
+		lookupTime, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return hostname, opts, fmt.Errorf("invalid time '%s' expected RFC3339", t)
+		}
+		opts.Time = lookupTime
+	}
+
+	includeInvalid, err := parseOptionalBool(query, "include_invalid")
+	if err != nil {
+		return hostname, opts, err
+	}
+	opts.IncludeInvalid = includeInvalid != nil && *includeInvalid
+
+	return hostname, opts, nil
+}
+
+// getCertFilterFromRequest return the certificate filter from the request query
+// subject, issuer, signature_algorithm, san_type, san, public_key_algorithm, public_key_size, spki_sha256,
+// key_usage, ext_key_usage (comma separated), is_ca, max_path_len, has_name_constraints
+// the subject_ and issuer_ DN attributes (cn, o, ou, c, l, st, serial_number),
+// the expiry window before, after, within (durations from now e.g. 30d, 12h, -7d),
+// the validity dates issued_after, issued_before, expires_after, expires_before (RFC3339),
+// tag (with include_descendants), issuer_id, replaced and label_selector
+func getCertFilterFromRequest(r *http.Request) (data.CertFilter, error) {
+	query := r.URL.Query()
+
+	filter := data.CertFilter{
+		Subject:            query.Get("subject"),
+		Issuer:             query.Get("issuer"),
+		SignatureAlgorithm: query.Get("signature_algorithm"),
+		SANType:            query.Get("san_type"),
+		SAN:                query.Get("san"),
+		PublicKeyAlgorithm: query.Get("public_key_algorithm"),
+		SPKISHA256:         query.Get("spki_sha256"),
+		KeyUsages:          splitQueryList(query.Get("key_usage")),
+		ExtKeyUsages:       splitQueryList(query.Get("ext_key_usage")),
+		SubjectDN:          getDNFromQuery(query, "subject_"),
+		IssuerDN:           getDNFromQuery(query, "issuer_"),
+		LabelSelector:      query.Get("label_selector"),
+	}
+
+	var err error
+	if size := query.Get("public_key_size"); size != "" {
+		filter.PublicKeySize, err = strconv.Atoi(size)
+		if err != nil {
+			return filter, fmt.Errorf("invalid public_key_size '%s'", size)
+		}
+	}
+
+	filter.IsCA, err = parseOptionalBool(query, "is_ca")
+	if err != nil {
+		return filter, err
+	}
+
+	filter.HasNameConstraints, err = parseOptionalBool(query, "has_name_constraints")
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Replaced, err = parseOptionalBool(query, "replaced")
+	if err != nil {
+		return filter, err
+	}
+
+	err = setExpiryWindowFromQuery(&filter, query, time.Now())
+	if err != nil {
+		return filter, err
+	}
+
+	err = setValidityDatesFromQuery(&filter, query)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Tag = query.Get("tag")
+	descendants, err := parseOptionalBool(query, "include_descendants")
+	if err != nil {
+		return filter, err
+	}
+	filter.TagDescendants = descendants != nil && *descendants
+
+	if issuerID := query.Get("issuer_id"); issuerID != "" {
+		id, err := uuid.Parse(issuerID)
+		if err != nil {
+			return filter, fmt.Errorf("invalid issuer_id '%s'", issuerID)
+		}
+		filter.IssuerID = &id
+	}
+
+	if pathLen := query.Get("max_path_len"); pathLen != "" {
+		maxPathLen, err := strconv.Atoi(pathLen)
+		if err != nil {
+			return filter, fmt.Errorf("invalid max_path_len '%s'", pathLen)
+		}
+		filter.MaxPathLen = &maxPathLen
+	}
+
+	return filter, nil
+}
+
+// setExpiryWindowFromQuery set the expiry window of filter from the durations relative to now
+// of the query parameters before, after and within
+func setExpiryWindowFromQuery(filter *data.CertFilter, query url.Values, now time.Time) error {
+
+	within := query.Get("within")
+	before := query.Get("before")
+	after := query.Get("after")
+
+	if within != "" {
+		if before != "" || after != "" {
+			return fmt.Errorf("within cannot be combined with before or after")
+		}
+
+		d, err := parseDuration(within)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid within '%s'", within)
+		}
+
+		from, to := now, now.Add(d)
+		filter.ExpiresAfter = &from
+		filter.ExpiresBefore = &to
+		return nil
+	}
+
+	if before != "" {
+		d, err := parseDuration(before)
+		if err != nil {
+			return fmt.Errorf("invalid before '%s'", before)
+		}
+
+		to := now.Add(d)
+		filter.ExpiresBefore = &to
+	}
+
+	if after != "" {
+		d, err := parseDuration(after)
+		if err != nil {
+			return fmt.Errorf("invalid after '%s'", after)
+		}
+
+		from := now.Add(d)
+		filter.ExpiresAfter = &from
+	}
+
+	return nil
+}
+
+// setValidityDatesFromQuery set the issuance and expiry windows of filter from the RFC3339 dates
+// of the query parameters issued_after, issued_before, expires_after and expires_before
+func setValidityDatesFromQuery(filter *data.CertFilter, query url.Values) error {
+
+	dates := []struct {
+		name  string
+		field **time.Time
+	}{
+		{"issued_after", &filter.IssuedAfter},
+		{"issued_before", &filter.IssuedBefore},
+		{"expires_after", &filter.ExpiresAfter},
+		{"expires_before", &filter.ExpiresBefore},
+	}
+
+	for _, date := range dates {
+		value := query.Get(date.name)
+		if value == "" {
+			continue
+		}
+
+		if strings.HasPrefix(date.name, "expires_") && *date.field != nil {
+			return fmt.Errorf("%s cannot be combined with before, after or within", date.name)
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("invalid %s '%s' expected RFC3339", date.name, value)
+		}
+		*date.field = &t
+	}
+
+	return nil
+}
+
+// parseDuration parse a duration as time.ParseDuration with the
+// additional unit 'd' for days (e.g. 30d or -7d)
+func parseDuration(value string) (time.Duration, error) {
+
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, err
+		}
+
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+// getDNFromQuery return the DN attributes from the query parameters prefixed by prefix
+func getDNFromQuery(query url.Values, prefix string) data.DistinguishedName {
+	return data.DistinguishedName{
+		CommonName:         query.Get(prefix + "cn"),
+		Organization:       query.Get(prefix + "o"),
+		OrganizationalUnit: query.Get(prefix + "ou"),
+		Country:            query.Get(prefix + "c"),
+		Locality:           query.Get(prefix + "l"),
+		Province:           query.Get(prefix + "st"),
+		SerialNumber:       query.Get(prefix + "serial_number"),
+	}
+}
+
+// splitQueryList split a comma separated query parameter (nil if empty)
+func splitQueryList(value string) []string {
+
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+// parseOptionalBool parse the bool query parameter name (nil if absent)
+func parseOptionalBool(query url.Values, name string) (*bool, error) {
+
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s '%s'", name, value)
+	}
+
+	return &b, nil
+}
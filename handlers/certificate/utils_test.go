@@ -1,6 +1,12 @@
 package certificate
 
-import "testing"
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/vdbulcke/cert-manager/data"
+)
 
 func TestSha(t *testing.T) {
 	validSha := "ca42dd41745fd0b81eb902362cf9d8bf719da1bd1b1efc946f5b4c99f42c1b9e"
@@ -38,3 +44,64 @@ func TestSha(t *testing.T) {
 	}
 
 }
+
+func TestExpiryWindow(t *testing.T) {
+
+	now := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	filter := data.CertFilter{}
+	err := setExpiryWindowFromQuery(&filter, url.Values{"within": {"30d"}}, now)
+	if err != nil || !filter.ExpiresAfter.Equal(now) || !filter.ExpiresBefore.Equal(now.AddDate(0, 0, 30)) {
+		t.Logf("Unexpected window %v %v (%v)", filter.ExpiresAfter, filter.ExpiresBefore, err)
+		t.FailNow()
+	}
+
+	filter = data.CertFilter{}
+	err = setExpiryWindowFromQuery(&filter, url.Values{"after": {"-7d"}, "before": {"12h"}}, now)
+	if err != nil || !filter.ExpiresAfter.Equal(now.AddDate(0, 0, -7)) || !filter.ExpiresBefore.Equal(now.Add(12*time.Hour)) {
+		t.Logf("Unexpected window %v %v (%v)", filter.ExpiresAfter, filter.ExpiresBefore, err)
+		t.FailNow()
+	}
+
+	invalid := []url.Values{
+		{"within": {"30d"}, "before": {"1d"}},
+		{"within": {"-1d"}},
+		{"before": {"soon"}},
+		{"after": {"1y"}},
+	}
+	for _, query := range invalid {
+		filter = data.CertFilter{}
+		if setExpiryWindowFromQuery(&filter, query, now) == nil {
+			t.Logf("Expecting error for %v", query)
+			t.FailNow()
+		}
+	}
+}
+
+func TestValidityDates(t *testing.T) {
+
+	filter := data.CertFilter{}
+	err := setValidityDatesFromQuery(&filter, url.Values{"issued_after": {"2021-01-01T00:00:00Z"}, "expires_before": {"2031-01-01T00:00:00Z"}})
+	if err != nil || filter.IssuedAfter.Year() != 2021 || filter.ExpiresBefore.Year() != 2031 || filter.IssuedBefore != nil {
+		t.Logf("Unexpected dates %v %v (%v)", filter.IssuedAfter, filter.ExpiresBefore, err)
+		t.FailNow()
+	}
+
+	err = setValidityDatesFromQuery(&data.CertFilter{}, url.Values{"issued_before": {"2021-01-01"}})
+	if err == nil {
+		t.Logf("Expecting error for a date without time")
+		t.FailNow()
+	}
+
+	// the relative expiry window and the expiry dates cannot be combined
+	filter = data.CertFilter{}
+	query := url.Values{"within": {"30d"}, "expires_after": {"2021-01-01T00:00:00Z"}}
+	err = setExpiryWindowFromQuery(&filter, query, time.Now())
+	if err == nil {
+		err = setValidityDatesFromQuery(&filter, query)
+	}
+	if err == nil {
+		t.Logf("Expecting error combining within and expires_after")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,73 @@
+// Package lint  of Lint API
+//
+// Documentation for Lint API
+//
+//	Schemes: http
+//	BasePath: /api/beta2/
+//	Version: 0.1.0
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+// swagger:meta
+package lint
+
+import (
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/handlers/api"
+)
+
+// Generic error message returned as a string
+// swagger:response errorResponse
+type errorResponseWrapper struct {
+	// Description of the error
+	// in: body
+	Body api.GenericAPIError
+}
+
+// The lint findings across the inventory
+// swagger:response lintReportResponse
+type lintReportResponseWrapper struct {
+	// the findings with a count per rule
+	// in: body
+	Body data.LintReport
+}
+
+// Data structure representing a single certificate with its findings
+// swagger:response certificateResponse
+type certificateResponseWrapper struct {
+	// a Certificate
+	// in: body
+	Body data.Certificate
+}
+
+// The number of linted certificates
+// swagger:response lintResultResponse
+type lintResultResponseWrapper struct {
+	// the result
+	// in: body
+	Body APILintResult
+}
+
+// swagger:parameters ListLintFindings
+type lintFindingFilterParamsWrapper struct {
+	// The id of the rule
+	// in: query
+	// required: false
+	RuleID string `json:"rule_id"`
+	// The severity: info, warning or error
+	// in: query
+	// required: false
+	Severity string `json:"severity"`
+}
+
+// swagger:parameters LintCertificate
+type lintCertificateIDParamsWrapper struct {
+	// The id of the certificate to lint
+	// in: path
+	// required: true
+	ID string `json:"id"`
+}
@@ -0,0 +1,61 @@
+package lint
+
+import (
+	"net/http"
+
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/handlers/api"
+)
+
+// swagger:route GET /lint/findings Lint ListLintFindings
+// Return the lint findings of all certificates matching the filters
+// responses:
+//	200: lintReportResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// ListLintFindings handles GET requests
+func (h *APILintHandler) ListLintFindings(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	query := r.URL.Query()
+	filter := data.LintFindingFilter{
+		RuleID:   query.Get("rule_id"),
+		Severity: data.LintSeverity(query.Get("severity")),
+	}
+
+	report, err := h.certBackend.ListLintFindings(filter)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+		}
+
+		h.logger.Error("ListLintFindings: Error listing findings", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error listing lint findings",
+		}
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(report, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("ListLintFindings: Error Serializing JSON", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package lint
+
+import (
+	"github.com/hashicorp/go-hclog"
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// APILintHandler handler for certificate lint api
+type APILintHandler struct {
+	logger      hclog.Logger
+	v           *data.Validation
+	certBackend *data.CertBackend
+}
+
+// NewAPILintHandler create a new APILintHandler
+func NewAPILintHandler(l hclog.Logger, v *data.Validation, certBackend *data.CertBackend) *APILintHandler {
+	return &APILintHandler{
+		logger:      l,
+		v:           v,
+		certBackend: certBackend,
+	}
+}
+
+// APILintResult result of linting all certificates
+type APILintResult struct {
+	// the number of linted certificates
+	Linted int `json:"linted"`
+}
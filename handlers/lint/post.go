@@ -0,0 +1,105 @@
+package lint
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/handlers/api"
+)
+
+// swagger:route POST /lint/certificate/{id} Lint LintCertificate
+// Run the lint rules against a certificate and return it with its findings
+// responses:
+//	200: certificateResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// LintCertificate handles POST requests
+func (h *APILintHandler) LintCertificate(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	cert, err := h.certBackend.LintCertificateByID(uuid)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("LintCertificate: object not found", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+		}
+
+		h.logger.Error("LintCertificate: Error linting certificate", "uuid", uuid, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error linting certificate id=%s", uuid.String()),
+		}
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(cert, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("LintCertificate: Error Serializing JSON", "cert", cert, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route POST /lint/certificates Lint LintCertificates
+// Run the lint rules against all certificates
+// responses:
+//	200: lintResultResponse
+//  500: errorResponse
+
+// LintCertificates handles POST requests
+func (h *APILintHandler) LintCertificates(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	linted, err := h.certBackend.LintCertificates()
+	if err != nil {
+		h.logger.Error("LintCertificates: Error linting certificates", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error linting certificates",
+		}
+	}
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(&APILintResult{Linted: linted}, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("LintCertificates: Error Serializing JSON", "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
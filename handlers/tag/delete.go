@@ -9,7 +9,8 @@ import (
 )
 
 // swagger:route DELETE /tag/DeleteTagByID/{id} Tag DeleteTagByID
-// Deletes Tags from DB (and all association)
+// Moves Tag to the trash (see RestoreTag), the associations are deleted when the tag is purged
+// a tag with child tags cannot be deleted
 // responses:
 //	204: noContentResponse
 //	404: errorResponse
@@ -29,7 +30,7 @@ func (h *APITagHandler) DeleteTagByID(rw http.ResponseWriter, r *http.Request) *
 	}
 
 	//  deletes tag
-	err = h.certBackend.DeleteTagByID(uuid)
+	err = h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).DeleteTagByID(uuid)
 	if err != nil {
 		if _, ok := err.(*data.DBObjectNotFound); ok {
 			h.logger.Debug("DeleteTagByID: object not found", "uuid", uuid)
@@ -41,6 +42,14 @@ func (h *APITagHandler) DeleteTagByID(rw http.ResponseWriter, r *http.Request) *
 				Message: err.Error(),
 			}
 
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
 		}
 
 		// default
@@ -40,6 +40,18 @@ type tagListResponseWrapper struct {
 	Body []data.Tags
 }
 
+// A page of tags
+// swagger:response tagPageResponse
+type tagPageResponseWrapper struct {
+	// The total number of tags matching the filters
+	XTotalCount int `json:"X-Total-Count"`
+	// The links to the first, prev, next and last pages
+	Link string `json:"Link"`
+	// the tags of the page
+	// in: body
+	Body []data.Tag
+}
+
 // Data structure representing a single tag
 // swagger:response tagResponse
 type tagResponseWrapper struct {
@@ -64,7 +76,31 @@ type tagDescriptionUpdateParamsWrapper struct {
 	Body APITagDescriptionInput
 }
 
-// swagger:parameters DeleteTagByID GetTagByID
+// swagger:parameters UpdateTagNotificationThresholds
+type tagNotificationThresholdsParamsWrapper struct {
+	// Tag notification thresholds to Update
+	// in: body
+	// required: true
+	Body APITagNotificationThresholdsInput
+}
+
+// swagger:parameters RenameTag
+type tagRenameParamsWrapper struct {
+	// Tag new name
+	// in: body
+	// required: true
+	Body APITagRenameInput
+}
+
+// swagger:parameters MergeTags
+type tagMergeParamsWrapper struct {
+	// Tags to merge into the Tag
+	// in: body
+	// required: true
+	Body APITagMergeInput
+}
+
+// swagger:parameters DeleteTagByID GetTagByID UpdateTagNotificationThresholds RestoreTag RenameTag MergeTags
 type tagIDParamsWrapper struct {
 	// The id of the tag for which the operation relates
 	// in: path
@@ -74,8 +110,44 @@ type tagIDParamsWrapper struct {
 
 // swagger:parameters GetTagByName
 type tagNameParamsWrapper struct {
-	// The id of the tag for which the operation relates
+	// The name of the tag for which the operation relates (e.g. payments/prod/eu)
 	// in: path
 	// required: true
 	Name string `json:"name"`
 }
+
+// swagger:parameters GetTagByID GetTagByName
+type tagDescendantsParamsWrapper struct {
+	// Also return the certificates tagged with a descendant of the tag (default false)
+	// in: query
+	// required: false
+	IncludeDescendants bool `json:"include_descendants"`
+}
+
+// swagger:parameters ListTags
+type tagListParamsWrapper struct {
+	// The name to match, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	Name string `json:"name"`
+	// The description to match, '*' matches any sequence of characters
+	// in: query
+	// required: false
+	Description string `json:"description"`
+	// The max number of tags, at most 1000 (default all the tags)
+	// in: query
+	// required: false
+	Limit int `json:"limit"`
+	// The number of tags to skip
+	// in: query
+	// required: false
+	Offset int `json:"offset"`
+	// The sort key (id, name, created_at), prefixed with '-' for descending (default name)
+	// in: query
+	// required: false
+	Sort string `json:"sort"`
+	// Return all the root tags with their descendants as children instead of a page (default false)
+	// in: query
+	// required: false
+	Tree bool `json:"tree"`
+}
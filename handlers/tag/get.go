@@ -9,7 +9,8 @@ import (
 )
 
 // swagger:route GET /tag/GetTagByID/{id} Tag GetTagByID
-// Return tag from the database
+// Return tag from the database with its certificates
+// (and the certificates of its descendants with include_descendants)
 // responses:
 //	200: tagResponse
 //	404: errorResponse
@@ -28,8 +29,23 @@ func (h *APITagHandler) GetTagByID(rw http.ResponseWriter, r *http.Request) *api
 		}
 	}
 
+	descendants, err := getBoolFromRequest(r, "include_descendants")
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
 	// lookup this tag
-	tag, err := h.certBackend.GetTagByID(uuid)
+	var tag *data.Tag
+	if descendants {
+		tag, err = h.certBackend.GetTagByIDWithDescendants(uuid)
+	} else {
+		tag, err = h.certBackend.GetTagByID(uuid)
+	}
 	if err != nil {
 		if _, ok := err.(*data.DBObjectNotFound); ok {
 			h.logger.Debug("GetTagByID: object not found", "uuid", uuid)
@@ -75,7 +91,8 @@ func (h *APITagHandler) GetTagByID(rw http.ResponseWriter, r *http.Request) *api
 }
 
 // swagger:route GET /tag/GetTagByName/{name} Tag GetTagByName
-// Return tag from the database
+// Return tag from the database with its certificates
+// (and the certificates of its descendants with include_descendants)
 // responses:
 //	200: tagResponse
 //	404: errorResponse
@@ -94,8 +111,23 @@ func (h *APITagHandler) GetTagByName(rw http.ResponseWriter, r *http.Request) *a
 		}
 	}
 
+	descendants, err := getBoolFromRequest(r, "include_descendants")
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
 	// lookup this tag
-	tag, err := h.certBackend.GetTagByName(tagName)
+	var tag *data.Tag
+	if descendants {
+		tag, err = h.certBackend.GetTagByNameWithDescendants(tagName)
+	} else {
+		tag, err = h.certBackend.GetTagByName(tagName)
+	}
 	if err != nil {
 		if _, ok := err.(*data.DBObjectNotFound); ok {
 			h.logger.Debug("GetTagByName: object not found", "tagName", tagName)
@@ -141,9 +173,12 @@ func (h *APITagHandler) GetTagByName(rw http.ResponseWriter, r *http.Request) *a
 }
 
 // swagger:route GET /tag/ListTags Tag ListTags
-// Return tag from the database
+// Return a page of the tags from the database matching the filters
+// the total number of matching tags is returned in the X-Total-Count header
+// and the links to the other pages in the Link header
+// or with tree all the root tags matching the filters with their descendants as children
 // responses:
-//	200: tagListResponse
+//	200: tagPageResponse
 //	404: errorResponse
 //  400: errorResponse
 //  500: errorResponse
@@ -151,8 +186,34 @@ func (h *APITagHandler) GetTagByName(rw http.ResponseWriter, r *http.Request) *a
 // ListTags handles GET requests
 func (h *APITagHandler) ListTags(rw http.ResponseWriter, r *http.Request) *api.APIError {
 
-	// lookup this tag
-	tags, err := h.certBackend.ListTags()
+	filter := getTagFilterFromRequest(r)
+
+	opts, err := api.GetListOptionsFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	tree, err := getBoolFromRequest(r, "tree")
+	if err != nil {
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: err.Error(),
+		}
+	}
+
+	if tree {
+		return h.listTagTree(rw, filter)
+	}
+
+	// lookup the page of tags matching the filter
+	tags, total, err := h.certBackend.ListTagsPage(filter, opts)
 	if err != nil {
 		if _, ok := err.(*data.DBObjectNotFound); ok {
 			h.logger.Debug("ListTags: object not found", "tags", tags)
@@ -164,6 +225,14 @@ func (h *APITagHandler) ListTags(rw http.ResponseWriter, r *http.Request) *api.A
 				Message: err.Error(),
 			}
 
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
 		}
 
 		// default
@@ -178,9 +247,10 @@ func (h *APITagHandler) ListTags(rw http.ResponseWriter, r *http.Request) *api.A
 
 	}
 
-	h.logger.Debug("ListTags: Found tag", "tags", tags)
+	h.logger.Debug("ListTags: Found tag", "tags", tags, "total", total)
 
 	// Write Status code
+	api.SetPageHeaders(rw, r, opts, total)
 	rw.WriteHeader(http.StatusOK)
 	err = api.ToJSON(tags, rw)
 	if err != nil {
@@ -196,3 +266,78 @@ func (h *APITagHandler) ListTags(rw http.ResponseWriter, r *http.Request) *api.A
 
 	return nil
 }
+
+// listTagTree write the root tags matching the filter with their descendants
+func (h *APITagHandler) listTagTree(rw http.ResponseWriter, filter data.TagFilter) *api.APIError {
+
+	tags, err := h.certBackend.ListTagTree(filter)
+	if err != nil {
+		h.logger.Debug("ListTags: unexpected error searching for tag tree", "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error searching for tags",
+		}
+	}
+
+	h.logger.Debug("ListTags: Found tag tree", "roots", len(tags))
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(tags, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("ListTags: Error Serializing JSON", "tags", tags, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route GET /tag/ListTrashedTags Tag ListTrashedTags
+// Return the deleted tags that were not purged yet (last deleted first)
+// responses:
+//	200: tagListResponse
+//  500: errorResponse
+
+// ListTrashedTags handles GET requests
+func (h *APITagHandler) ListTrashedTags(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	// lookup the tags in the trash
+	tags, err := h.certBackend.ListTrashedTags()
+	if err != nil {
+		h.logger.Debug("ListTrashedTags: unexpected error searching for tags", "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "Error searching for tags in the trash",
+		}
+	}
+
+	h.logger.Debug("ListTrashedTags: Found tags", "tags", tags)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusOK)
+	err = api.ToJSON(tags, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("ListTrashedTags: Error Serializing JSON", "tags", tags, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
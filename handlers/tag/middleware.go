@@ -92,3 +92,129 @@ func (h *APITagHandler) MiddlewareValidateTagDescriptionInput(next http.Handler)
 
 	}})
 }
+
+// MiddlewareValidateTagNotificationThresholdsInput validates the tag thresholds in the request and calls next if ok
+func (h *APITagHandler) MiddlewareValidateTagNotificationThresholdsInput(next http.Handler) http.Handler {
+	return http.Handler(api.Handler{Handler: func(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+		// declaring the tag thresholds Input
+		tagThresholdsInput := &APITagNotificationThresholdsInput{}
+
+		// parsing tag intput
+		err := api.FromJSON(tagThresholdsInput, r.Body)
+		if err != nil {
+			h.logger.Error("MiddlewareValidateTagNotificationThresholdsInput: error deserializing json", "err", err)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: "error deserializing json",
+			}
+		}
+
+		// validate input
+		errs := h.v.Validate(tagThresholdsInput)
+		if len(errs) != 0 {
+			h.logger.Debug("MiddlewareValidateTagNotificationThresholdsInput: invalid input", "tagThresholdsInput", tagThresholdsInput, "errs", errs)
+			return &api.APIError{
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: fmt.Sprintf("Validation error : %s", strings.Join(errs.Errors(), "\n")),
+			}
+		}
+
+		// add the input to the context
+		ctx := context.WithValue(r.Context(), APITagNotificationThresholdsInputKey{}, *tagThresholdsInput)
+		r = r.WithContext(ctx)
+
+		// Call the next handler, which can be another middleware in the chain, or the final handler.
+		next.ServeHTTP(rw, r)
+		return nil
+
+	}})
+}
+
+// MiddlewareValidateTagRenameInput validates the tag rename in the request and calls next if ok
+func (h *APITagHandler) MiddlewareValidateTagRenameInput(next http.Handler) http.Handler {
+	return http.Handler(api.Handler{Handler: func(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+		// declaring the tag rename Input
+		tagRenameInput := &APITagRenameInput{}
+
+		// parsing tag intput
+		err := api.FromJSON(tagRenameInput, r.Body)
+		if err != nil {
+			h.logger.Error("MiddlewareValidateTagRenameInput: error deserializing json", "err", err)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: "error deserializing json",
+			}
+		}
+
+		// validate input
+		errs := h.v.Validate(tagRenameInput)
+		if len(errs) != 0 {
+			h.logger.Debug("MiddlewareValidateTagRenameInput: invalid input", "tagRenameInput", tagRenameInput, "errs", errs)
+			return &api.APIError{
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: fmt.Sprintf("Validation error : %s", strings.Join(errs.Errors(), "\n")),
+			}
+		}
+
+		// add the input to the context
+		ctx := context.WithValue(r.Context(), APITagRenameInputKey{}, *tagRenameInput)
+		r = r.WithContext(ctx)
+
+		// Call the next handler, which can be another middleware in the chain, or the final handler.
+		next.ServeHTTP(rw, r)
+		return nil
+
+	}})
+}
+
+// MiddlewareValidateTagMergeInput validates the tag merge in the request and calls next if ok
+func (h *APITagHandler) MiddlewareValidateTagMergeInput(next http.Handler) http.Handler {
+	return http.Handler(api.Handler{Handler: func(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+		// declaring the tag merge Input
+		tagMergeInput := &APITagMergeInput{}
+
+		// parsing tag intput
+		err := api.FromJSON(tagMergeInput, r.Body)
+		if err != nil {
+			h.logger.Error("MiddlewareValidateTagMergeInput: error deserializing json", "err", err)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: "error deserializing json",
+			}
+		}
+
+		// validate input
+		errs := h.v.Validate(tagMergeInput)
+		if len(errs) != 0 {
+			h.logger.Debug("MiddlewareValidateTagMergeInput: invalid input", "tagMergeInput", tagMergeInput, "errs", errs)
+			return &api.APIError{
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: fmt.Sprintf("Validation error : %s", strings.Join(errs.Errors(), "\n")),
+			}
+		}
+
+		// add the input to the context
+		ctx := context.WithValue(r.Context(), APITagMergeInputKey{}, *tagMergeInput)
+		r = r.WithContext(ctx)
+
+		// Call the next handler, which can be another middleware in the chain, or the final handler.
+		next.ServeHTTP(rw, r)
+		return nil
+
+	}})
+}
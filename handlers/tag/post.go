@@ -39,7 +39,7 @@ func (h *APITagHandler) CreateTag(rw http.ResponseWriter, r *http.Request) *api.
 
 	// checking if the input contains description
 	if tagInput.Description == "" {
-		tag, err = h.certBackend.CreateTag(tagInput.Name)
+		tag, err = h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).CreateTag(tagInput.Name)
 		if err != nil {
 			if _, ok := err.(*data.DBObjectAlreadyExist); ok {
 				statusCode = http.StatusConflict
@@ -64,7 +64,7 @@ func (h *APITagHandler) CreateTag(rw http.ResponseWriter, r *http.Request) *api.
 
 	} else {
 		// creating a cert without tags
-		tag, err = h.certBackend.CreateTagWithDescription(tagInput.Name, tagInput.Description)
+		tag, err = h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).CreateTagWithDescription(tagInput.Name, tagInput.Description)
 		if err != nil {
 			if _, ok := err.(*data.DBObjectAlreadyExist); ok {
 				statusCode = http.StatusConflict
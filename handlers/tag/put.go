@@ -41,7 +41,7 @@ func (h *APITagHandler) UpdateTagDescription(rw http.ResponseWriter, r *http.Req
 	}
 
 	// updating tag description
-	tag, err := h.certBackend.SetTagDescriptionByID(uuid, tagDescriptionInput.Description)
+	tag, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).SetTagDescriptionByID(uuid, tagDescriptionInput.Description)
 	if err != nil {
 		if _, ok := err.(*data.DBObjectNotFound); ok {
 			return &api.APIError{
@@ -88,3 +88,307 @@ func (h *APITagHandler) UpdateTagDescription(rw http.ResponseWriter, r *http.Req
 
 	return nil
 }
+
+// swagger:route PUT /tag/UpdateTagNotificationThresholds/{id} Tag UpdateTagNotificationThresholds
+// Return the Tag with its updated expiry notification thresholds
+// responses:
+//	202: tagResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// UpdateTagNotificationThresholds handles PUT requests
+func (h *APITagHandler) UpdateTagNotificationThresholds(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	// look up thresholds input (set by the middleware) in request context
+	tagThresholdsInput := r.Context().Value(APITagNotificationThresholdsInputKey{}).(APITagNotificationThresholdsInput)
+
+	// updating tag thresholds
+	tag, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).SetTagNotificationThresholdsByID(uuid, tagThresholdsInput.NotificationThresholds)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
+		} else {
+			// default
+			return &api.APIError{Err: err,
+				Code:    http.StatusInternalServerError,
+				Type:    &api.InternalServerError{},
+				Message: "error updating tag",
+			}
+		}
+
+	}
+
+	h.logger.Debug("UpdateTagNotificationThresholds: Updated tag", "tag", tag)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusAccepted)
+	err = api.ToJSON(tag, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("UpdateTagNotificationThresholds: Error Serializing JSON", "tag", tag, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route PUT /tag/RestoreTag/{id} Tag RestoreTag
+// Return the tag restored from the trash
+// responses:
+//	202: tagResponse
+//	404: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// RestoreTag handles PUT requests
+func (h *APITagHandler) RestoreTag(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	// restore the tag
+	tag, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).RestoreTagByID(uuid)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			h.logger.Debug("RestoreTag: object not found in the trash", "uuid", uuid)
+
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		}
+
+		// default
+		h.logger.Debug("RestoreTag: unexpected error restoring tag", "uuid", uuid, "err", err)
+
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: fmt.Sprintf("Error restoring tag id=%s", uuid.String()),
+		}
+
+	}
+
+	h.logger.Debug("RestoreTag: Restored tag", "tag", tag)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusAccepted)
+	err = api.ToJSON(tag, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("RestoreTag: Error Serializing JSON", "tag", tag, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route PUT /tag/RenameTag/{id} Tag RenameTag
+// Return the renamed Tag, its descendants are renamed with it (e.g. payments/prod when renaming payments
+// to billing becomes billing/prod) and the certificates keep their tags
+// responses:
+//	202: tagResponse
+//	404: errorResponse
+//	409: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// RenameTag handles PUT requests
+func (h *APITagHandler) RenameTag(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	// look up rename input (set by the middleware) in request context
+	tagRenameInput := r.Context().Value(APITagRenameInputKey{}).(APITagRenameInput)
+
+	// renaming tag
+	tag, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).RenameTagByID(uuid, tagRenameInput.Name)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		} else if _, ok := err.(*data.DBObjectAlreadyExist); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusConflict,
+				Type:    &api.ValidationError{},
+				Message: fmt.Sprintf("the name is already used by the tag id=%s", err.(*data.DBObjectAlreadyExist).ID),
+			}
+
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
+		} else {
+			// default
+			h.logger.Debug("RenameTag: unexpected error updating tag", "uuid", uuid, "err", err)
+			return &api.APIError{Err: err,
+				Code:    http.StatusInternalServerError,
+				Type:    &api.InternalServerError{},
+				Message: "error updating tag",
+			}
+		}
+
+	}
+
+	h.logger.Debug("RenameTag: Updated tag", "tag", tag)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusAccepted)
+	err = api.ToJSON(tag, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("RenameTag: Error Serializing JSON", "tag", tag, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
+
+// swagger:route PUT /tag/MergeTags/{id} Tag MergeTags
+// Return the Tag with the tags merged into it: the certificates of the merged tags are tagged with the tag
+// in one transaction and the names of the merged tags become aliases of the tag
+// responses:
+//	202: tagResponse
+//	404: errorResponse
+//	409: errorResponse
+//  400: errorResponse
+//  500: errorResponse
+
+// MergeTags handles PUT requests
+func (h *APITagHandler) MergeTags(rw http.ResponseWriter, r *http.Request) *api.APIError {
+
+	uuid, err := api.GetIDFromRequest(r)
+	if err != nil {
+		return &api.APIError{
+			Code:    http.StatusBadRequest,
+			Type:    &api.ValidationError{},
+			Message: fmt.Sprintf("Cannot find id from request %s", r.URL.String()),
+		}
+	}
+
+	// look up merge input (set by the middleware) in request context
+	tagMergeInput := r.Context().Value(APITagMergeInputKey{}).(APITagMergeInput)
+
+	// merging tags
+	tag, err := h.certBackend.WithAuditContext(api.GetAuditContextFromRequest(r)).MergeTagsByID(uuid, tagMergeInput.Tags)
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusNotFound,
+				Type:    &api.ObjectNotFoundError{},
+				Message: err.Error(),
+			}
+
+		} else if _, ok := err.(*data.DBObjectAlreadyExist); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusConflict,
+				Type:    &api.ValidationError{},
+				Message: fmt.Sprintf("the name is already used by the tag id=%s", err.(*data.DBObjectAlreadyExist).ID),
+			}
+
+		} else if _, ok := err.(*data.DBObjectValidationError); ok {
+			return &api.APIError{
+				Err:     err,
+				Code:    http.StatusBadRequest,
+				Type:    &api.ValidationError{},
+				Message: err.Error(),
+			}
+
+		} else {
+			// default
+			h.logger.Debug("MergeTags: unexpected error updating tag", "uuid", uuid, "err", err)
+			return &api.APIError{Err: err,
+				Code:    http.StatusInternalServerError,
+				Type:    &api.InternalServerError{},
+				Message: "error updating tag",
+			}
+		}
+
+	}
+
+	h.logger.Debug("MergeTags: Updated tag", "tag", tag)
+
+	// Write Status code
+	rw.WriteHeader(http.StatusAccepted)
+	err = api.ToJSON(tag, rw)
+	if err != nil {
+		// we should never be here but log the error just incase
+		h.logger.Error("MergeTags: Error Serializing JSON", "tag", tag, "err", err)
+		return &api.APIError{
+			Err:     err,
+			Code:    http.StatusInternalServerError,
+			Type:    &api.InternalServerError{},
+			Message: "error formating json",
+		}
+	}
+
+	return nil
+}
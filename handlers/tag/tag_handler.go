@@ -37,3 +37,29 @@ type APITagDescriptionInput struct {
 
 // APITagDescriptionInputKey key to find the  APITagDescriptionInput in request context
 type APITagDescriptionInputKey struct{}
+
+// APITagNotificationThresholdsInput input for Tag notification thresholds Update API
+type APITagNotificationThresholdsInput struct {
+	// the comma separated thresholds in days (e.g. 60,30,7,1), empty to use the default thresholds
+	NotificationThresholds string `json:"notification_thresholds" validate:"max=100"`
+}
+
+// APITagNotificationThresholdsInputKey key to find the APITagNotificationThresholdsInput in request context
+type APITagNotificationThresholdsInputKey struct{}
+
+// APITagRenameInput input for Tag rename API
+type APITagRenameInput struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// APITagRenameInputKey key to find the APITagRenameInput in request context
+type APITagRenameInputKey struct{}
+
+// APITagMergeInput input for Tag merge API
+type APITagMergeInput struct {
+	// the names of the tags to merge into the tag
+	Tags []string `json:"tags" validate:"required,min=1"`
+}
+
+// APITagMergeInputKey key to find the APITagMergeInput in request context
+type APITagMergeInputKey struct{}
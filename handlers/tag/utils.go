@@ -1,9 +1,12 @@
 package tag
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/vdbulcke/cert-manager/data"
 )
 
 // getTagNameFromRequest return uuid from request
@@ -13,3 +16,29 @@ func getTagNameFromRequest(r *http.Request) (string, error) {
 
 	return vars["name"], nil
 }
+
+// getTagFilterFromRequest return the tag filter from the request query name and description
+func getTagFilterFromRequest(r *http.Request) data.TagFilter {
+	query := r.URL.Query()
+
+	return data.TagFilter{
+		Name:        query.Get("name"),
+		Description: query.Get("description"),
+	}
+}
+
+// getBoolFromRequest return the bool query parameter name (false if absent)
+func getBoolFromRequest(r *http.Request, name string) (bool, error) {
+
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s '%s'", name, value)
+	}
+
+	return b, nil
+}
@@ -1,8 +1,31 @@
 package main
 
-import "github.com/vdbulcke/cert-manager/server"
+import (
+	"fmt"
+	"os"
+
+	"github.com/vdbulcke/cert-manager/server"
+)
 
 func main() {
 
+	// verify an audit chain export offline
+	if len(os.Args) > 1 && os.Args[1] == "audit-verify" {
+		if len(os.Args) != 4 {
+			fmt.Fprintf(os.Stderr, "usage: %s audit-verify <public-key.pem> <export.jsonl>\n", os.Args[0])
+			os.Exit(2)
+		}
+
+		valid, err := server.VerifyAuditExportFiles(os.Args[2], os.Args[3], os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error verifying audit export: %s\n", err)
+			os.Exit(2)
+		}
+		if !valid {
+			os.Exit(1)
+		}
+		return
+	}
+
 	server.StartServer()
 }
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// namespace the prefix of all cert-manager metrics
+const namespace = "cert_manager"
+
+// CertCollector a prometheus.Collector reading the certificates from the database at each scrape
+type CertCollector struct {
+	logger      hclog.Logger
+	certBackend *data.CertBackend
+
+	expiry   *prometheus.Desc
+	total    *prometheus.Desc
+	perTag   *prometheus.Desc
+	expired  *prometheus.Desc
+	expiring *prometheus.Desc
+}
+
+// NewCertCollector create a new CertCollector
+func NewCertCollector(logger hclog.Logger, certBackend *data.CertBackend) *CertCollector {
+	return &CertCollector{
+		logger:      logger,
+		certBackend: certBackend,
+		expiry: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "certificate", "expiry_seconds"),
+			"The number of seconds until the NotAfter of the certificate (negative if expired).",
+			[]string{"id", "subject_cn", "issuer", "serial", "tags", "replaced"}, nil),
+		total: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "certificates"),
+			"The number of stored certificates.",
+			nil, nil),
+		perTag: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "tag", "certificates"),
+			"The number of certificates with the tag.",
+			[]string{"tag"}, nil),
+		expired: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "certificates_expired"),
+			"The number of expired certificates not replaced by a renewal.",
+			nil, nil),
+		expiring: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "certificates_expiring"),
+			"The number of certificates expiring within 30 days not replaced by a renewal.",
+			nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *CertCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.expiry
+	ch <- c.total
+	ch <- c.perTag
+	ch <- c.expired
+	ch <- c.expiring
+}
+
+// Collect implements prometheus.Collector
+func (c *CertCollector) Collect(ch chan<- prometheus.Metric) {
+
+	certs, err := c.certBackend.ListCertsExpiry()
+	if err != nil {
+		c.logger.Error("CertCollector: error listing certificates", "err", err)
+		ch <- prometheus.NewInvalidMetric(c.total, err)
+		return
+	}
+
+	now := time.Now()
+	perTag := map[string]int{}
+	expired, expiring := 0, 0
+
+	for _, cert := range certs {
+
+		var tags []string
+		for _, tag := range cert.Tags {
+			tags = append(tags, tag.Name)
+			perTag[tag.Name]++
+		}
+		sort.Strings(tags)
+
+		ch <- prometheus.MustNewConstMetric(c.expiry, prometheus.GaugeValue,
+			cert.NotAfter.Sub(now).Seconds(),
+			cert.ID.String(), cert.SubjectDN.CommonName, cert.Issuer, cert.SerialNumber, strings.Join(tags, ","),
+			strconv.FormatBool(cert.Replaced))
+
+		// a replaced cert does not need attention
+		if cert.Replaced {
+			continue
+		}
+
+		switch cert.GetStatus(now) {
+		case data.CertificateStatusExpired:
+			expired++
+		case data.CertificateStatusExpiring:
+			expiring++
+		}
+	}
+
+	// tags without certs are reported too
+	tags, err := c.certBackend.ListTags()
+	if err != nil {
+		c.logger.Error("CertCollector: error listing tags", "err", err)
+	}
+	for _, tag := range tags {
+		if _, ok := perTag[tag.Name]; !ok {
+			perTag[tag.Name] = 0
+		}
+	}
+
+	for name, count := range perTag {
+		ch <- prometheus.MustNewConstMetric(c.perTag, prometheus.GaugeValue, float64(count), name)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(len(certs)))
+	ch <- prometheus.MustNewConstMetric(c.expired, prometheus.GaugeValue, float64(expired))
+	ch <- prometheus.MustNewConstMetric(c.expiring, prometheus.GaugeValue, float64(expiring))
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTPMetrics the metrics of the requests served by the routes of a mux.Router
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewHTTPMetrics create a new HTTPMetrics and register it to registerer
+func NewHTTPMetrics(registerer prometheus.Registerer) *HTTPMetrics {
+
+	m := &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "The number of HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "The duration of HTTP requests by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+	}
+
+	registerer.MustRegister(m.requests, m.duration)
+
+	return m
+}
+
+// Middleware record the metrics of the requests matched by a route
+// the route label is the path template of the route (e.g. /api/beta2/certificate/GetCertificateByID/{id})
+func (m *HTTPMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+
+		route := "unknown"
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+
+		recorder := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+		start := time.Now()
+
+		next.ServeHTTP(recorder, r)
+
+		m.duration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+		m.requests.WithLabelValues(route, r.Method, strconv.Itoa(recorder.status)).Inc()
+	})
+}
+
+// statusRecorder a http.ResponseWriter keeping the status code written
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader keep the status code and write it
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
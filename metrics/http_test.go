@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHTTPMetricsMiddleware(t *testing.T) {
+
+	m := NewHTTPMetrics(prometheus.NewRegistry())
+
+	router := mux.NewRouter()
+	router.Use(m.Middleware)
+	router.HandleFunc("/certificate/GetCertificateByID/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	}).Methods(http.MethodGet)
+
+	for _, id := range []string{"a", "b"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/certificate/GetCertificateByID/"+id, nil))
+	}
+
+	// requests are counted by route template, not by path
+	count := testutil.ToFloat64(m.requests.WithLabelValues("/certificate/GetCertificateByID/{id}", http.MethodGet, "404"))
+	if count != 2 {
+		t.Logf("Expecting 2 requests, but got %f", count)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// Metrics the registry of the metrics exposed on /metrics
+type Metrics struct {
+	registry *prometheus.Registry
+
+	// HTTP the metrics of the HTTP requests
+	HTTP *HTTPMetrics
+}
+
+// NewMetrics create a registry with the certificate, HTTP, process and go runtime metrics
+func NewMetrics(logger hclog.Logger, certBackend *data.CertBackend) *Metrics {
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		NewCertCollector(logger, certBackend),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		prometheus.NewGoCollector(),
+	)
+
+	return &Metrics{
+		registry: registry,
+		HTTP:     NewHTTPMetrics(registry),
+	}
+}
+
+// Handler return the handler exposing the metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
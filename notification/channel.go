@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// Channel delivers expiry notifications
+// implement it and pass it to NewScheduler to add a custom channel
+type Channel interface {
+	// Name the unique name of the channel, used to persist the delivery state
+	Name() string
+	// Send deliver the notification, an error means the notification must be retried
+	Send(ctx context.Context, n *Notification) error
+}
+
+// Notification an expiry notification of a certificate
+type Notification struct {
+	// the id of the certificate
+	CertificateID uuid.UUID `json:"certificate_id"`
+	// the sha256 of the certificate
+	SHA256 string `json:"sha256"`
+	// the Subject of the certificate
+	Subject string `json:"subject"`
+	// the Issuer of the certificate
+	Issuer string `json:"issuer"`
+	// the Serial Number of the certificate
+	SerialNumber string `json:"serial_number"`
+	// the Not After validity of the certificate
+	NotAfter time.Time `json:"not_after"`
+	// the threshold in days that fired the notification
+	Threshold int `json:"threshold"`
+	// the number of days left before NotAfter
+	DaysLeft int `json:"days_left"`
+	// the names of the tags of the certificate
+	Tags []string `json:"tags"`
+}
+
+// NewNotification create the Notification of cert for threshold at now
+func NewNotification(cert *data.Certificate, threshold int, now time.Time) *Notification {
+
+	tags := []string{}
+	for _, t := range cert.Tags {
+		tags = append(tags, t.Name)
+	}
+
+	return &Notification{
+		CertificateID: cert.ID,
+		SHA256:        cert.SHA256,
+		Subject:       cert.Subject,
+		Issuer:        cert.Issuer,
+		SerialNumber:  cert.SerialNumber,
+		NotAfter:      cert.NotAfter,
+		Threshold:     threshold,
+		DaysLeft:      int(cert.NotAfter.Sub(now).Hours() / 24),
+		Tags:          tags,
+	}
+}
+
+// Title the one line summary of the notification
+func (n *Notification) Title() string {
+	return fmt.Sprintf("Certificate '%s' expires in %d days", n.Subject, n.DaysLeft)
+}
+
+// Text the description of the notification
+func (n *Notification) Text() string {
+	return fmt.Sprintf("The certificate '%s' issued by '%s' (serial %s, sha256 %s) expires on %s.",
+		n.Subject, n.Issuer, n.SerialNumber, n.SHA256, n.NotAfter.UTC().Format(time.RFC1123))
+}
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// NewConfigFromEnv create a Config from the environment variables
+// CERT_MANAGER_NOTIFY_THRESHOLDS (comma separated days) and CERT_MANAGER_NOTIFY_INTERVAL (positive duration)
+// unset variables keep the default values
+func NewConfigFromEnv() (Config, error) {
+
+	config := NewConfig()
+
+	if thresholds := os.Getenv("CERT_MANAGER_NOTIFY_THRESHOLDS"); thresholds != "" {
+		days, err := data.ParseNotificationThresholds(thresholds)
+		if err != nil {
+			return config, err
+		}
+		config.Thresholds = days
+	}
+
+	if interval := os.Getenv("CERT_MANAGER_NOTIFY_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			return config, err
+		}
+		if d <= 0 {
+			return config, fmt.Errorf("invalid CERT_MANAGER_NOTIFY_INTERVAL '%s': must be positive", interval)
+		}
+		config.Interval = d
+	}
+
+	return config, nil
+}
+
+// NewChannelsFromEnv create the channels configured by the environment variables
+//   - CERT_MANAGER_NOTIFY_WEBHOOK_URL: generic webhook
+//   - CERT_MANAGER_NOTIFY_SLACK_URL: Slack-compatible incoming webhook
+//   - CERT_MANAGER_NOTIFY_TEAMS_URL: MS Teams-compatible incoming webhook
+//   - CERT_MANAGER_NOTIFY_SMTP_ADDR (host:port), CERT_MANAGER_NOTIFY_SMTP_FROM,
+//     CERT_MANAGER_NOTIFY_SMTP_TO (comma separated), CERT_MANAGER_NOTIFY_SMTP_USERNAME
+//     and CERT_MANAGER_NOTIFY_SMTP_PASSWORD: SMTP email
+func NewChannelsFromEnv() []Channel {
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	var channels []Channel
+	if url := os.Getenv("CERT_MANAGER_NOTIFY_WEBHOOK_URL"); url != "" {
+		channels = append(channels, NewWebhookChannel(url, client))
+	}
+
+	if url := os.Getenv("CERT_MANAGER_NOTIFY_SLACK_URL"); url != "" {
+		channels = append(channels, NewSlackChannel(url, client))
+	}
+
+	if url := os.Getenv("CERT_MANAGER_NOTIFY_TEAMS_URL"); url != "" {
+		channels = append(channels, NewTeamsChannel(url, client))
+	}
+
+	if addr := os.Getenv("CERT_MANAGER_NOTIFY_SMTP_ADDR"); addr != "" {
+		channels = append(channels, NewSMTPChannel(
+			addr,
+			os.Getenv("CERT_MANAGER_NOTIFY_SMTP_USERNAME"),
+			os.Getenv("CERT_MANAGER_NOTIFY_SMTP_PASSWORD"),
+			os.Getenv("CERT_MANAGER_NOTIFY_SMTP_FROM"),
+			strings.Split(os.Getenv("CERT_MANAGER_NOTIFY_SMTP_TO"), ","),
+		))
+	}
+
+	return channels
+}
@@ -0,0 +1,43 @@
+package notification
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNewConfigFromEnv(t *testing.T) {
+
+	tests := []struct {
+		thresholds string
+		interval   string
+		valid      bool
+		expected   time.Duration
+	}{
+		{"", "", true, time.Hour},
+		{"30,7", "15m", true, 15 * time.Minute},
+		{"", "0", false, 0},
+		{"", "-1h", false, 0},
+		{"", "hourly", false, 0},
+		{"soon", "", false, 0},
+	}
+
+	defer os.Unsetenv("CERT_MANAGER_NOTIFY_THRESHOLDS")
+	defer os.Unsetenv("CERT_MANAGER_NOTIFY_INTERVAL")
+
+	for _, test := range tests {
+		os.Setenv("CERT_MANAGER_NOTIFY_THRESHOLDS", test.thresholds)
+		os.Setenv("CERT_MANAGER_NOTIFY_INTERVAL", test.interval)
+
+		config, err := NewConfigFromEnv()
+		if (err == nil) != test.valid {
+			t.Logf("Expecting valid %v for %v got %v", test.valid, test, err)
+			t.FailNow()
+		}
+
+		if test.valid && config.Interval != test.expected {
+			t.Logf("Expecting interval %s got %s", test.expected, config.Interval)
+			t.FailNow()
+		}
+	}
+}
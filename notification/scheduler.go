@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// Config configuration of the expiry notifications
+type Config struct {
+	// the default thresholds in days before NotAfter (overridable per Tag)
+	Thresholds []int
+	// the time between two evaluations of the certificates
+	Interval time.Duration
+	// the max number of attempts to deliver a notification through a channel
+	MaxAttempts int
+	// the max duration of a delivery attempt
+	SendTimeout time.Duration
+}
+
+// NewConfig create a Config with the default values
+func NewConfig() Config {
+	return Config{
+		Thresholds:  []int{60, 30, 7, 1},
+		Interval:    time.Hour,
+		MaxAttempts: 5,
+		SendTimeout: 30 * time.Second,
+	}
+}
+
+// Scheduler evaluates periodically the expiry of the certificates
+// and fires the notifications through the channels
+type Scheduler struct {
+	logger      hclog.Logger
+	certBackend *data.CertBackend
+	config      Config
+	channels    []Channel
+}
+
+// NewScheduler create a new Scheduler
+func NewScheduler(logger hclog.Logger, certBackend *data.CertBackend, config Config, channels ...Channel) *Scheduler {
+	return &Scheduler{
+		logger:      logger,
+		certBackend: certBackend,
+		config:      config,
+		channels:    channels,
+	}
+}
+
+// Start evaluate the certificates now and then every Interval until ctx is done
+// this function is blocking
+func (s *Scheduler) Start(ctx context.Context) {
+
+	if len(s.channels) == 0 {
+		s.logger.Info("Scheduler: no notification channel configured")
+		return
+	}
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.Run(ctx, time.Now())
+		if err != nil {
+			s.logger.Error("Scheduler: error evaluating certificates expiry", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run fire the notifications due at now that were not delivered yet, a failed notification
+// does not stop the others and the first error is returned once all were evaluated
+func (s *Scheduler) Run(ctx context.Context, now time.Time) error {
+	s.logger.Debug("Scheduler: evaluating certificates expiry", "now", now)
+
+	maxThreshold, err := s.certBackend.GetMaxNotificationThreshold(s.config.Thresholds)
+	if err != nil {
+		return err
+	}
+
+	// the certs that are not expired, not replaced by a renewal and expire within the largest threshold
+	before := now.AddDate(0, 0, maxThreshold)
+	replaced := false
+	certs, err := s.certBackend.ListCertsByExpiry(data.CertFilter{ExpiresAfter: &now, ExpiresBefore: &before, Replaced: &replaced})
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, cert := range certs {
+		threshold, due := cert.GetDueNotificationThreshold(cert.GetNotificationThresholds(s.config.Thresholds), now)
+		if !due {
+			continue
+		}
+
+		n := NewNotification(cert, threshold, now)
+		for _, channel := range s.channels {
+			err := s.notify(ctx, channel, n, now)
+			if err != nil {
+				s.logger.Error("Scheduler: error notifying", "channel", channel.Name(), "cert", n.CertificateID, "threshold", n.Threshold, "err", err)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// notify send the notification through channel unless it was already delivered
+// or the max number of attempts is reached, and record the attempt
+func (s *Scheduler) notify(ctx context.Context, channel Channel, n *Notification, now time.Time) error {
+
+	delivery, err := s.certBackend.GetNotificationDelivery(n.CertificateID, n.Threshold, channel.Name())
+	if err != nil {
+		if _, ok := err.(*data.DBObjectNotFound); !ok {
+			return err
+		}
+	}
+
+	if delivery != nil && (delivery.DeliveredAt != nil || delivery.Attempts >= s.config.MaxAttempts) {
+		return nil
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, s.config.SendTimeout)
+	sendErr := channel.Send(sendCtx, n)
+	cancel()
+
+	if sendErr != nil {
+		s.logger.Error("Scheduler: error sending notification", "channel", channel.Name(), "cert", n.CertificateID, "threshold", n.Threshold, "err", sendErr)
+	} else {
+		s.logger.Info("Scheduler: notification sent", "channel", channel.Name(), "cert", n.CertificateID, "threshold", n.Threshold)
+	}
+
+	_, err = s.certBackend.RecordNotificationDelivery(n.CertificateID, n.Threshold, channel.Name(), sendErr, now)
+	return err
+}
@@ -0,0 +1,167 @@
+package notification
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/data/database"
+	"gorm.io/gorm"
+)
+
+// fakeChannel record the notifications sent, Send fails with err if set
+type fakeChannel struct {
+	name string
+	err  error
+	sent []*Notification
+}
+
+func (c *fakeChannel) Name() string {
+	return c.name
+}
+
+func (c *fakeChannel) Send(ctx context.Context, n *Notification) error {
+	c.sent = append(c.sent, n)
+	return c.err
+}
+
+func setupTestCertBackend(t *testing.T) (*data.CertBackend, *gorm.DB) {
+
+	db := database.NewSqliteDB(filepath.Join(t.TempDir(), "sqlite.db"))
+	err := db.AutoMigrate(data.Models()...)
+	if err != nil {
+		t.Logf("Error running migration %s", err.Error())
+		t.FailNow()
+	}
+
+	return data.NewCertBackend(hclog.NewNullLogger(), db, data.NewValidation()), db
+}
+
+// createTestCertificate create a self-signed cert with the common name cn expiring at notAfter
+func createTestCertificate(t *testing.T, certBackend *data.CertBackend, cn string, notAfter time.Time) *data.Certificate {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Logf("Error generating key %s", err.Error())
+		t.FailNow()
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Logf("Error creating certificate %s", err.Error())
+		t.FailNow()
+	}
+
+	cert, err := certBackend.CreateCertificate(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	if err != nil {
+		t.Logf("Error storing certificate %s", err.Error())
+		t.FailNow()
+	}
+
+	return cert
+}
+
+func TestSchedulerRun(t *testing.T) {
+
+	certBackend, _ := setupTestCertBackend(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	createTestCertificate(t, certBackend, "a.example.com", now.AddDate(0, 0, 20))
+	createTestCertificate(t, certBackend, "b.example.com", now.AddDate(0, 0, 5))
+	// not due before the largest threshold
+	createTestCertificate(t, certBackend, "c.example.com", now.AddDate(0, 0, 90))
+
+	config := NewConfig()
+	config.Thresholds = []int{30, 7}
+	config.MaxAttempts = 2
+
+	ok := &fakeChannel{name: "ok"}
+	failing := &fakeChannel{name: "failing", err: errors.New("unavailable")}
+	scheduler := NewScheduler(hclog.NewNullLogger(), certBackend, config, failing, ok)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		// the total number of notifications sent through each channel after the run
+		ok      int
+		failing int
+	}{
+		// a failing channel does not stop the others
+		{"first run", now, 2, 2},
+		// delivered notifications are not sent again, failed ones are retried
+		{"same thresholds", now.Add(time.Hour), 2, 4},
+		// until the max number of attempts
+		{"max attempts", now.Add(2 * time.Hour), 2, 4},
+		// the next threshold of a is due
+		{"next threshold", now.AddDate(0, 0, 14), 3, 5},
+	}
+
+	for _, test := range tests {
+		err := scheduler.Run(context.Background(), test.now)
+		if err != nil {
+			t.Logf("%s: Error running scheduler %s", test.name, err.Error())
+			t.FailNow()
+		}
+
+		if len(ok.sent) != test.ok || len(failing.sent) != test.failing {
+			t.Logf("%s: Expecting %d and %d notifications got %d and %d", test.name, test.ok, test.failing, len(ok.sent), len(failing.sent))
+			t.FailNow()
+		}
+	}
+
+	if ok.sent[2].Subject != "CN=a.example.com" || ok.sent[2].Threshold != 7 {
+		t.Logf("Expecting the 7 days notification of a got %v", ok.sent[2])
+		t.FailNow()
+	}
+}
+
+func TestSchedulerRunError(t *testing.T) {
+
+	certBackend, db := setupTestCertBackend(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	createTestCertificate(t, certBackend, "a.example.com", now.AddDate(0, 0, 20))
+	createTestCertificate(t, certBackend, "b.example.com", now.AddDate(0, 0, 5))
+
+	// the deliveries of the broken channel cannot be recorded
+	result := db.Exec("CREATE TRIGGER broken_channel BEFORE INSERT ON notification_deliveries " +
+		"WHEN NEW.channel = 'broken' BEGIN SELECT RAISE(ABORT, 'broken channel'); END")
+	if result.Error != nil {
+		t.Logf("Error creating trigger %s", result.Error.Error())
+		t.FailNow()
+	}
+
+	broken := &fakeChannel{name: "broken"}
+	ok := &fakeChannel{name: "ok"}
+	scheduler := NewScheduler(hclog.NewNullLogger(), certBackend, NewConfig(), broken, ok)
+
+	err := scheduler.Run(context.Background(), now)
+	if err == nil {
+		t.Logf("Expecting error recording the deliveries of the broken channel")
+		t.FailNow()
+	}
+
+	// the other certs and channels are notified
+	if len(broken.sent) != 2 || len(ok.sent) != 2 {
+		t.Logf("Expecting 2 notifications per channel got %d and %d", len(broken.sent), len(ok.sent))
+		t.FailNow()
+	}
+}
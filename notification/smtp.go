@@ -0,0 +1,155 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPChannel send the notification by email
+type SMTPChannel struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+// NewSMTPChannel create a channel sending emails through the SMTP server addr (host:port)
+// auth is only used if username is not empty
+func NewSMTPChannel(addr string, username string, password string, from string, to []string) *SMTPChannel {
+
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPChannel{
+		addr: addr,
+		auth: auth,
+		from: from,
+		to:   to,
+	}
+}
+
+// Name the name of the channel
+func (c *SMTPChannel) Name() string {
+	return "smtp"
+}
+
+// Send send the notification email, the SMTP session is aborted when ctx is done
+func (c *SMTPChannel) Send(ctx context.Context, n *Notification) error {
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// bound the whole session by the deadline of ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		err = conn.SetDeadline(deadline)
+		if err != nil {
+			return err
+		}
+	}
+
+	// unblock the session if ctx is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	err = c.send(conn, c.message(n))
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+// message return the email of the notification, the subject (from the certificate) is
+// encoded and no header value can contain CR or LF to add headers or recipients
+func (c *SMTPChannel) message(n *Notification) []byte {
+
+	var to []string
+	for _, t := range c.to {
+		to = append(to, headerValue(t))
+	}
+
+	return []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		headerValue(c.from), strings.Join(to, ", "), mime.QEncoding.Encode("utf-8", headerValue(n.Title())), n.Text()))
+}
+
+// headerValue return v without CR and LF
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(v)
+}
+
+// send send msg on conn (as smtp.SendMail)
+func (c *SMTPChannel) send(conn net.Conn, msg []byte) error {
+
+	host, _, _ := net.SplitHostPort(c.addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp: server %s does not support AUTH", c.addr)
+		}
+
+		err = client.Auth(c.auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(c.from)
+	if err != nil {
+		return err
+	}
+
+	for _, to := range c.to {
+		err = client.Rcpt(to)
+		if err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(msg)
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,165 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accept one SMTP session on a local port and return its address
+// and the channel receiving the DATA of the session
+func fakeSMTPServer(t *testing.T) (string, chan string) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Logf("Error listening %s", err.Error())
+		t.FailNow()
+	}
+
+	received := make(chan string, 1)
+	go func() {
+		defer listener.Close()
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		write := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+		write("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				write("250 localhost")
+			case strings.HasPrefix(cmd, "DATA"):
+				write("354 end with .")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- data.String()
+				write("250 OK")
+			case strings.HasPrefix(cmd, "QUIT"):
+				write("221 bye")
+				return
+			default:
+				write("250 OK")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestSMTPChannel(t *testing.T) {
+
+	addr, received := fakeSMTPServer(t)
+
+	n := newTestNotification()
+	channel := NewSMTPChannel(addr, "", "", "cert-manager@example.com", []string{"pki@example.com"})
+
+	err := channel.Send(context.Background(), n)
+	if err != nil {
+		t.Logf("Error sending email %s", err.Error())
+		t.FailNow()
+	}
+
+	msg := <-received
+	if !strings.Contains(msg, "Subject: "+n.Title()+"\r\n") || !strings.Contains(msg, "To: pki@example.com\r\n") {
+		t.Logf("Unexpected email %s", msg)
+		t.FailNow()
+	}
+}
+
+func TestSMTPChannelTimeout(t *testing.T) {
+
+	// the server never answers
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Logf("Error listening %s", err.Error())
+		t.FailNow()
+	}
+	defer listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = NewSMTPChannel(listener.Addr().String(), "", "", "cert-manager@example.com", []string{"pki@example.com"}).
+		Send(ctx, newTestNotification())
+	if err == nil || time.Since(start) > 5*time.Second {
+		t.Logf("Expecting timeout error got %v after %s", err, time.Since(start))
+		t.FailNow()
+	}
+}
+
+func TestSMTPChannelMessage(t *testing.T) {
+
+	tests := []struct {
+		subject string
+		from    string
+		to      []string
+		// the header lines expected in the message
+		headers []string
+	}{
+		{
+			"CN=www.example.com",
+			"cert-manager@example.com",
+			[]string{"pki@example.com", "ops@example.com"},
+			[]string{"From: cert-manager@example.com", "To: pki@example.com, ops@example.com", "Subject: Certificate 'CN=www.example.com' expires in 6 days"},
+		},
+		{
+			// a CN with CR/LF cannot add headers or recipients
+			"CN=evil\r\nBcc: victim@example.com",
+			"cert-manager@example.com\r\nBcc: victim@example.com",
+			[]string{"pki@example.com\nCc: victim@example.com"},
+			[]string{"From: cert-manager@example.comBcc: victim@example.com", "To: pki@example.comCc: victim@example.com", "Subject: Certificate 'CN=evilBcc: victim@example.com' expires in 6 days"},
+		},
+		{
+			"CN=café.example.com",
+			"cert-manager@example.com",
+			[]string{"pki@example.com"},
+			[]string{"Subject: =?utf-8?q?Certificate_'CN=3Dcaf=C3=A9.example.com'_expires_in_6_days?="},
+		},
+	}
+
+	for _, test := range tests {
+		n := newTestNotification()
+		n.Subject = test.subject
+
+		msg := string(NewSMTPChannel("localhost:25", "", "", test.from, test.to).message(n))
+		headers := strings.Split(msg[:strings.Index(msg, "\r\n\r\n")], "\r\n")
+
+		if len(headers) != 4 {
+			t.Logf("Expecting 4 headers got %q", headers)
+			t.FailNow()
+		}
+
+		for _, h := range test.headers {
+			found := false
+			for _, header := range headers {
+				found = found || header == h
+			}
+
+			if !found {
+				t.Logf("Expecting header %q in %q", h, headers)
+				t.FailNow()
+			}
+		}
+	}
+}
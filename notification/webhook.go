@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WebhookChannel POST the Notification as JSON to a URL
+type WebhookChannel struct {
+	name   string
+	url    string
+	client *http.Client
+	body   func(n *Notification) interface{}
+}
+
+// NewWebhookChannel create a generic webhook channel posting the Notification
+func NewWebhookChannel(url string, client *http.Client) *WebhookChannel {
+	return &WebhookChannel{
+		name:   "webhook",
+		url:    url,
+		client: client,
+		body:   func(n *Notification) interface{} { return n },
+	}
+}
+
+// NewSlackChannel create a channel posting to a Slack-compatible incoming webhook
+func NewSlackChannel(url string, client *http.Client) *WebhookChannel {
+	return &WebhookChannel{
+		name:   "slack",
+		url:    url,
+		client: client,
+		body: func(n *Notification) interface{} {
+			return map[string]string{"text": fmt.Sprintf("*%s*\n%s", n.Title(), n.Text())}
+		},
+	}
+}
+
+// NewTeamsChannel create a channel posting a MessageCard to a MS Teams-compatible incoming webhook
+func NewTeamsChannel(url string, client *http.Client) *WebhookChannel {
+	return &WebhookChannel{
+		name:   "teams",
+		url:    url,
+		client: client,
+		body: func(n *Notification) interface{} {
+			return map[string]string{
+				"@type":    "MessageCard",
+				"@context": "https://schema.org/extensions",
+				"summary":  n.Title(),
+				"title":    n.Title(),
+				"text":     n.Text(),
+			}
+		},
+	}
+}
+
+// Name the name of the channel
+func (c *WebhookChannel) Name() string {
+	return c.name
+}
+
+// Send POST the notification, any non 2xx response is an error
+func (c *WebhookChannel) Send(ctx context.Context, n *Notification) error {
+
+	payload, err := json.Marshal(c.body(n))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: unexpected status %s", c.name, resp.Status)
+	}
+
+	return nil
+}
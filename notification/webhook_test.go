@@ -0,0 +1,101 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestNotification() *Notification {
+	return &Notification{
+		CertificateID: uuid.New(),
+		SHA256:        "be3d233194afd4b88cd4ee6c20b821f5ff53639c0b0dcfe77d76ee1946339cbe",
+		Subject:       "CN=www.example.com",
+		Issuer:        "CN=Example CA",
+		SerialNumber:  "1234",
+		NotAfter:      time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+		Threshold:     7,
+		DaysLeft:      6,
+		Tags:          []string{"web"},
+	}
+}
+
+func TestWebhookChannel(t *testing.T) {
+
+	n := newTestNotification()
+
+	tests := []struct {
+		name    string
+		channel func(url string, client *http.Client) *WebhookChannel
+		status  int
+		// the field of the posted JSON and its expected value
+		field string
+		value string
+		err   bool
+	}{
+		{"webhook", NewWebhookChannel, http.StatusOK, "certificate_id", n.CertificateID.String(), false},
+		{"slack", NewSlackChannel, http.StatusOK, "text", "*" + n.Title() + "*\n" + n.Text(), false},
+		{"teams", NewTeamsChannel, http.StatusOK, "@type", "MessageCard", false},
+		{"webhook", NewWebhookChannel, http.StatusInternalServerError, "certificate_id", n.CertificateID.String(), true},
+	}
+
+	for _, test := range tests {
+
+		var body map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			payload, _ := ioutil.ReadAll(r.Body)
+			_ = json.Unmarshal(payload, &body)
+			rw.WriteHeader(test.status)
+		}))
+
+		channel := test.channel(server.URL, server.Client())
+		err := channel.Send(context.Background(), n)
+		server.Close()
+
+		if channel.Name() != test.name {
+			t.Logf("Expecting channel %s got %s", test.name, channel.Name())
+			t.FailNow()
+		}
+
+		if (err != nil) != test.err {
+			t.Logf("%s: Expecting error %v got %v", test.name, test.err, err)
+			t.FailNow()
+		}
+
+		if body[test.field] != test.value {
+			t.Logf("%s: Expecting %s=%s got %v", test.name, test.field, test.value, body)
+			t.FailNow()
+		}
+	}
+}
+
+func TestWebhookChannelTimeout(t *testing.T) {
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := NewWebhookChannel(server.URL, server.Client()).Send(ctx, newTestNotification())
+	if err == nil || !strings.Contains(err.Error(), "deadline") {
+		t.Logf("Expecting deadline error got %v", err)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// NewAuditSigningKeyFromEnv load the key signing the audit chain exports from
+// the PKCS#8 PEM file CERT_MANAGER_AUDIT_SIGNING_KEY_FILE
+// if unset, a new key is generated: its exports can only be verified while the server runs
+func NewAuditSigningKeyFromEnv(logger hclog.Logger) (ed25519.PrivateKey, error) {
+
+	file := os.Getenv("CERT_MANAGER_AUDIT_SIGNING_KEY_FILE")
+	if file != "" {
+		pemData, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		return data.ParseAuditSigningKey(pemData)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Warn("CERT_MANAGER_AUDIT_SIGNING_KEY_FILE not set, signing audit exports with a temporary key",
+		"public_key", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})))
+
+	return key, nil
+}
+
+// VerifyAuditExportFiles verify the audit chain export with the PKIX PEM public key
+// and print the result to out
+// return false if the export is not valid
+func VerifyAuditExportFiles(publicKeyFile string, exportFile string, out io.Writer) (bool, error) {
+
+	pemData, err := ioutil.ReadFile(publicKeyFile)
+	if err != nil {
+		return false, err
+	}
+
+	publicKey, err := data.ParseAuditVerificationKey(pemData)
+	if err != nil {
+		return false, err
+	}
+
+	export, err := os.Open(exportFile)
+	if err != nil {
+		return false, err
+	}
+	defer export.Close()
+
+	verification, err := data.VerifyAuditExport(export, publicKey)
+	if err != nil {
+		return false, err
+	}
+
+	for _, e := range verification.Errors {
+		if e.Sequence == 0 {
+			fmt.Fprintf(out, "export: %s\n", e.Message)
+			continue
+		}
+		fmt.Fprintf(out, "event %d: %s\n", e.Sequence, e.Message)
+	}
+
+	if !verification.Valid {
+		fmt.Fprintf(out, "INVALID: %d events, %d problems\n", verification.Count, len(verification.Errors))
+		return false, nil
+	}
+
+	fmt.Fprintf(out, "OK: %d events, last sequence %d, last hash %s\n", verification.Count, verification.LastSequence, verification.LastHash)
+	return true, nil
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/vdbulcke/cert-manager/data"
 	"github.com/vdbulcke/cert-manager/data/database"
 	"github.com/vdbulcke/cert-manager/data/database/migration"
+	"github.com/vdbulcke/cert-manager/notification"
+	"github.com/vdbulcke/cert-manager/trash"
 )
 
 // StartServer start the server
@@ -27,15 +29,51 @@ func StartServer() {
 	// create backend
 	certBackend := data.NewCertBackend(logger, db, v)
 
+	// configure the detection of the certificate renewals
+	renewalPolicy, err := NewRenewalPolicyFromEnv()
+	if err != nil {
+		logger.Error("Error reading renewal config", "err", err)
+		os.Exit(1)
+	}
+	data.CertificateRenewalPolicy = renewalPolicy
+
 	// run DB migration
-	err := migration.DBMigration(db, logger)
+	err = migration.DBMigration(db, certBackend, logger)
 	if err != nil {
 		logger.Error("Error running db migration")
 		os.Exit(1)
 	}
 
+	// create the expiry notification scheduler
+	notificationConfig, err := notification.NewConfigFromEnv()
+	if err != nil {
+		logger.Error("Error reading notification config", "err", err)
+		os.Exit(1)
+	}
+	scheduler := notification.NewScheduler(logger, certBackend, notificationConfig, notification.NewChannelsFromEnv()...)
+
+	// create the trash purger
+	trashConfig, err := trash.NewConfigFromEnv()
+	if err != nil {
+		logger.Error("Error reading trash config", "err", err)
+		os.Exit(1)
+	}
+	purger := trash.NewPurger(logger, certBackend, trashConfig)
+
+	// load the key signing the audit chain exports
+	auditSigningKey, err := NewAuditSigningKeyFromEnv(logger)
+	if err != nil {
+		logger.Error("Error loading audit signing key", "err", err)
+		os.Exit(1)
+	}
+
+	// start the background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	go scheduler.Start(jobsCtx)
+	go purger.Start(jobsCtx)
+
 	// create the server
-	s := makeServer(logger, v, certBackend)
+	s := makeServer(logger, v, certBackend, auditSigningKey)
 
 	// create channel for capturing OS signal
 	c := MakeOSSignalChannel()
@@ -56,8 +94,12 @@ func StartServer() {
 
 	logger.Info("Got signal", "sig", sig)
 
+	// stop the background jobs
+	stopJobs()
+
 	// gracefully shutdown the server, waiting max 30 seconds for current operations to complete
-	ctx, _ := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 	s.Shutdown(ctx)
 
 }
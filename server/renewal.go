@@ -0,0 +1,35 @@
+package server
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// NewRenewalPolicyFromEnv create the policy detecting the certificate renewals from the environment variables
+// CERT_MANAGER_RENEWAL_MAX_GAP (duration e.g. 24h) and CERT_MANAGER_RENEWAL_REQUIRE_SAME_KEY (bool)
+// unset variables keep the values of data.CertificateRenewalPolicy
+func NewRenewalPolicyFromEnv() (data.RenewalPolicy, error) {
+
+	policy := data.CertificateRenewalPolicy
+
+	if maxGap := os.Getenv("CERT_MANAGER_RENEWAL_MAX_GAP"); maxGap != "" {
+		d, err := time.ParseDuration(maxGap)
+		if err != nil {
+			return policy, err
+		}
+		policy.MaxGap = d
+	}
+
+	if sameKey := os.Getenv("CERT_MANAGER_RENEWAL_REQUIRE_SAME_KEY"); sameKey != "" {
+		b, err := strconv.ParseBool(sameKey)
+		if err != nil {
+			return policy, err
+		}
+		policy.RequireSameKey = b
+	}
+
+	return policy, nil
+}
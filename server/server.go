@@ -1,6 +1,7 @@
 package server
 
 import (
+	"crypto/ed25519"
 	"net/http"
 	"time"
 
@@ -10,15 +11,23 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/vdbulcke/cert-manager/data"
 	"github.com/vdbulcke/cert-manager/handlers/api"
+	"github.com/vdbulcke/cert-manager/handlers/audit"
+	"github.com/vdbulcke/cert-manager/handlers/autotag"
 	"github.com/vdbulcke/cert-manager/handlers/certificate"
+	"github.com/vdbulcke/cert-manager/handlers/lint"
 	"github.com/vdbulcke/cert-manager/handlers/tag"
+	"github.com/vdbulcke/cert-manager/metrics"
 )
 
-func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBackend) http.Server {
+func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBackend, auditSigningKey ed25519.PrivateKey) *http.Server {
 	// create handlers
 	apiHandler := api.NewAPI()
 	certHandler := certificate.NewAPICertificateHandler(l, v, certBackend)
 	tagHandler := tag.NewAPITagHandler(l, v, certBackend)
+	lintHandler := lint.NewAPILintHandler(l, v, certBackend)
+	auditHandler := audit.NewAPIAuditHandler(l, v, certBackend, auditSigningKey)
+	autoTagHandler := autotag.NewAPIAutoTagHandler(l, v, certBackend)
+	serverMetrics := metrics.NewMetrics(l, certBackend)
 
 	// API Base Path
 	apiBasePath := "/api/beta2"
@@ -26,6 +35,9 @@ func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBacken
 	// create a new serve mux and register the handlers
 	mainRouter := mux.NewRouter()
 
+	// record the metrics of all routes
+	mainRouter.Use(serverMetrics.HTTP.Middleware)
+
 	// handlers for API
 	apiRouter := mainRouter.PathPrefix(apiBasePath).Subrouter()
 	apiRouter.Use(apiHandler.CommonAPIMiddleware)
@@ -46,11 +58,66 @@ func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBacken
 		api.Handler{Handler: certHandler.GetCertificateByFingerprint}).
 		Methods(http.MethodGet)
 
+	apiRouter.Handle(
+		"/certificate/GetCertificateByIssuerSerial",
+		api.Handler{Handler: certHandler.GetCertificateByIssuerSerial}).
+		Methods(http.MethodGet)
+
 	apiRouter.Handle(
 		"/certificate/ListCerts",
 		api.Handler{Handler: certHandler.ListCerts}).
 		Methods(http.MethodGet)
 
+	apiRouter.Handle(
+		"/certificate/ListCertsByExpiry",
+		api.Handler{Handler: certHandler.ListCertsByExpiry}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/certificate/GetCertificateIssuer/{id}",
+		api.Handler{Handler: certHandler.GetCertificateIssuer}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/certificate/ListIssuedCertificates/{id}",
+		api.Handler{Handler: certHandler.ListIssuedCertificates}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/certificate/GetCertificateChain/{id}",
+		api.Handler{Handler: certHandler.GetCertificateChain}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/certificate/{id}/chain",
+		api.Handler{Handler: certHandler.GetCertificateChain}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/certificate/SearchCertificates",
+		api.Handler{Handler: certHandler.SearchCertificates}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/certificate/ListCertsForHostname",
+		api.Handler{Handler: certHandler.ListCertsForHostname}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/certificate/GetCertificateLineage/{id}",
+		api.Handler{Handler: certHandler.GetCertificateLineage}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/certificate/ListNotificationDeliveries/{id}",
+		api.Handler{Handler: certHandler.ListNotificationDeliveries}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/certificate/ListTrashedCertificates",
+		api.Handler{Handler: certHandler.ListTrashedCertificates}).
+		Methods(http.MethodGet)
+
 	// POST
 	certAPIPost := apiRouter.Methods(http.MethodPost, http.MethodOptions).Subrouter()
 	certAPIPost.Handle(
@@ -66,6 +133,23 @@ func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBacken
 		api.Handler{Handler: certHandler.UpdateCertificateTag})
 	certAPIPut.Use(certHandler.MiddlewareValidateCertificateTagInput)
 
+	certLabelAPIPut := apiRouter.Methods(http.MethodPut).Subrouter()
+	certLabelAPIPut.Handle(
+		"/certificate/UpdateCertificateLabels/{id}",
+		api.Handler{Handler: certHandler.UpdateCertificateLabels})
+	certLabelAPIPut.Use(certHandler.MiddlewareValidateCertificateLabelInput)
+
+	certBulkTagAPIPut := apiRouter.Methods(http.MethodPut).Subrouter()
+	certBulkTagAPIPut.Handle(
+		"/certificate/BulkUpdateCertificateTags",
+		api.Handler{Handler: certHandler.BulkUpdateCertificateTags})
+	certBulkTagAPIPut.Use(certHandler.MiddlewareValidateCertificateBulkTagInput)
+
+	apiRouter.Handle(
+		"/certificate/RestoreCertificate/{id}",
+		api.Handler{Handler: certHandler.RestoreCertificate}).
+		Methods(http.MethodPut)
+
 	// DELETE
 	certAPIDelete := apiRouter.Methods(http.MethodDelete).Subrouter()
 	certAPIDelete.Handle(
@@ -73,6 +157,12 @@ func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBacken
 		api.Handler{Handler: certHandler.DeleteCertificateTagsByID})
 	certAPIDelete.Use(certHandler.MiddlewareValidateCertificateTagInput)
 
+	certLabelAPIDelete := apiRouter.Methods(http.MethodDelete).Subrouter()
+	certLabelAPIDelete.Handle(
+		"/certificate/DeleteCertificateLabelsByID/{id}",
+		api.Handler{Handler: certHandler.DeleteCertificateLabelsByID})
+	certLabelAPIDelete.Use(certHandler.MiddlewareValidateCertificateLabelKeysInput)
+
 	apiRouter.Handle(
 		"/certificate/DeleteCertificateByID/{id}",
 		api.Handler{Handler: certHandler.DeleteCertificateByID}).
@@ -87,7 +177,7 @@ func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBacken
 		Methods(http.MethodGet)
 
 	apiRouter.Handle(
-		"/tag/GetTagByName/{name}",
+		"/tag/GetTagByName/{name:.+}",
 		api.Handler{Handler: tagHandler.GetTagByName}).
 		Methods(http.MethodGet)
 
@@ -96,6 +186,11 @@ func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBacken
 		api.Handler{Handler: tagHandler.ListTags}).
 		Methods(http.MethodGet)
 
+	apiRouter.Handle(
+		"/tag/ListTrashedTags",
+		api.Handler{Handler: tagHandler.ListTrashedTags}).
+		Methods(http.MethodGet)
+
 	// POST
 	apiPost := apiRouter.Methods(http.MethodPost).Subrouter()
 	apiPost.Handle(
@@ -110,12 +205,115 @@ func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBacken
 		api.Handler{Handler: tagHandler.UpdateTagDescription})
 	apiPut.Use(tagHandler.MiddlewareValidateTagDescriptionInput)
 
+	tagAPIPut := apiRouter.Methods(http.MethodPut).Subrouter()
+	tagAPIPut.Handle(
+		"/tag/UpdateTagNotificationThresholds/{id}",
+		api.Handler{Handler: tagHandler.UpdateTagNotificationThresholds})
+	tagAPIPut.Use(tagHandler.MiddlewareValidateTagNotificationThresholdsInput)
+
+	tagRenameAPIPut := apiRouter.Methods(http.MethodPut).Subrouter()
+	tagRenameAPIPut.Handle(
+		"/tag/RenameTag/{id}",
+		api.Handler{Handler: tagHandler.RenameTag})
+	tagRenameAPIPut.Use(tagHandler.MiddlewareValidateTagRenameInput)
+
+	tagMergeAPIPut := apiRouter.Methods(http.MethodPut).Subrouter()
+	tagMergeAPIPut.Handle(
+		"/tag/MergeTags/{id}",
+		api.Handler{Handler: tagHandler.MergeTags})
+	tagMergeAPIPut.Use(tagHandler.MiddlewareValidateTagMergeInput)
+
+	apiRouter.Handle(
+		"/tag/RestoreTag/{id}",
+		api.Handler{Handler: tagHandler.RestoreTag}).
+		Methods(http.MethodPut)
+
 	// DELETE
 	apiRouter.Handle(
 		"/tag/DeleteTagByID/{id}",
 		api.Handler{Handler: tagHandler.DeleteTagByID}).
 		Methods(http.MethodDelete)
 
+	// Lint API
+	// GET
+	apiRouter.Handle(
+		"/lint/findings",
+		api.Handler{Handler: lintHandler.ListLintFindings}).
+		Methods(http.MethodGet)
+
+	// POST
+	apiRouter.Handle(
+		"/lint/certificate/{id}",
+		api.Handler{Handler: lintHandler.LintCertificate}).
+		Methods(http.MethodPost)
+
+	apiRouter.Handle(
+		"/lint/certificates",
+		api.Handler{Handler: lintHandler.LintCertificates}).
+		Methods(http.MethodPost)
+
+	// AutoTag API
+	// GET
+	apiRouter.Handle(
+		"/autotag/rules",
+		api.Handler{Handler: autoTagHandler.ListAutoTagRules}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/autotag/rules/{id}",
+		api.Handler{Handler: autoTagHandler.GetAutoTagRule}).
+		Methods(http.MethodGet)
+
+	// POST
+	autoTagAPIPost := apiRouter.Methods(http.MethodPost).Subrouter()
+	autoTagAPIPost.Handle(
+		"/autotag/rules",
+		api.Handler{Handler: autoTagHandler.CreateAutoTagRule})
+	autoTagAPIPost.Use(autoTagHandler.MiddlewareValidateAutoTagRuleInput)
+
+	apiRouter.Handle(
+		"/autotag/rules/{id}/apply",
+		api.Handler{Handler: autoTagHandler.ApplyAutoTagRule}).
+		Methods(http.MethodPost)
+
+	apiRouter.Handle(
+		"/autotag/apply",
+		api.Handler{Handler: autoTagHandler.ApplyAutoTagRules}).
+		Methods(http.MethodPost)
+
+	// DELETE
+	apiRouter.Handle(
+		"/autotag/rules/{id}",
+		api.Handler{Handler: autoTagHandler.DeleteAutoTagRule}).
+		Methods(http.MethodDelete)
+
+	// Audit API
+	// GET
+	apiRouter.Handle(
+		"/audit",
+		api.Handler{Handler: auditHandler.ListAuditEvents}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/audit/verify",
+		api.Handler{Handler: auditHandler.VerifyAuditChain}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/audit/export",
+		api.Handler{Handler: auditHandler.ExportAuditChain}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/audit/certificate/{id}",
+		api.Handler{Handler: auditHandler.GetCertificateHistory}).
+		Methods(http.MethodGet)
+
+	apiRouter.Handle(
+		"/audit/tag/{id}",
+		api.Handler{Handler: auditHandler.GetTagHistory}).
+		Methods(http.MethodGet)
+
 	//
 	// Swagger
 	//
@@ -140,6 +338,12 @@ func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBacken
 	getSwaggerYaml := mainRouter.Methods(http.MethodGet).Subrouter()
 	getSwaggerYaml.Handle("/swagger.yaml", http.FileServer(http.Dir("./static/")))
 
+	//
+	// Prometheus metrics
+	//
+	getMetrics := mainRouter.Methods(http.MethodGet).Subrouter()
+	getMetrics.Handle("/metrics", serverMetrics.Handler())
+
 	//
 	// CORS
 	//
@@ -154,13 +358,14 @@ func makeServer(l hclog.Logger, v *data.Validation, certBackend *data.CertBacken
 		http.MethodOptions,
 		http.MethodHead,
 	})
-	corsHandler := handlers.CORS(corsAllowedMethod, corsAllowedOrigin, corsAllowedHeader)
+	corsExposedHeader := handlers.ExposedHeaders([]string{api.RequestIDHeader, api.TotalCountHeader, "Link"})
+	corsHandler := handlers.CORS(corsAllowedMethod, corsAllowedOrigin, corsAllowedHeader, corsExposedHeader)
 
 	//
 	// Http Server
 	//
 	// create a new server
-	s := http.Server{
+	s := &http.Server{
 		Addr:         "0.0.0.0:9393",                                   // configure the bind address
 		Handler:      corsHandler(mainRouter),                          // set the default handler
 		ErrorLog:     l.StandardLogger(&hclog.StandardLoggerOptions{}), // set the logger for the server
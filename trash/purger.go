@@ -0,0 +1,112 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vdbulcke/cert-manager/data"
+)
+
+// Config configuration of the trash purge
+type Config struct {
+	// the time a deleted record stays in the trash before it is purged
+	Retention time.Duration
+	// the time between two purges
+	Interval time.Duration
+}
+
+// NewConfig create a Config with the default values
+func NewConfig() Config {
+	return Config{
+		Retention: 30 * 24 * time.Hour,
+		Interval:  time.Hour,
+	}
+}
+
+// NewConfigFromEnv create a Config from the environment variables
+// CERT_MANAGER_TRASH_RETENTION and CERT_MANAGER_TRASH_PURGE_INTERVAL (positive durations e.g. 720h)
+// unset variables keep the default values
+func NewConfigFromEnv() (Config, error) {
+
+	config := NewConfig()
+
+	if retention := os.Getenv("CERT_MANAGER_TRASH_RETENTION"); retention != "" {
+		d, err := time.ParseDuration(retention)
+		if err != nil {
+			return config, err
+		}
+		if d <= 0 {
+			return config, fmt.Errorf("invalid CERT_MANAGER_TRASH_RETENTION '%s': must be positive", retention)
+		}
+		config.Retention = d
+	}
+
+	if interval := os.Getenv("CERT_MANAGER_TRASH_PURGE_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			return config, err
+		}
+		if d <= 0 {
+			return config, fmt.Errorf("invalid CERT_MANAGER_TRASH_PURGE_INTERVAL '%s': must be positive", interval)
+		}
+		config.Interval = d
+	}
+
+	return config, nil
+}
+
+// Purger permanently deletes periodically the records that stayed
+// in the trash longer than the retention
+type Purger struct {
+	logger      hclog.Logger
+	certBackend *data.CertBackend
+	config      Config
+}
+
+// NewPurger create a new Purger
+func NewPurger(logger hclog.Logger, certBackend *data.CertBackend, config Config) *Purger {
+	return &Purger{
+		logger:      logger,
+		certBackend: certBackend,
+		config:      config,
+	}
+}
+
+// Start purge the trash now and then every Interval until ctx is done
+// this function is blocking
+func (p *Purger) Start(ctx context.Context) {
+
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := p.Run(time.Now())
+		if err != nil {
+			p.logger.Error("Purger: error purging trash", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run purge the records deleted before now minus the retention
+func (p *Purger) Run(now time.Time) (*data.TrashPurgeReport, error) {
+
+	report, err := p.certBackend.PurgeTrash(now.Add(-p.config.Retention))
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Certificates != 0 || report.Tags != 0 {
+		p.logger.Info("Purger: purged trash", "certificates", report.Certificates, "tags", report.Tags)
+	}
+
+	return report, nil
+}
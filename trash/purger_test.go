@@ -0,0 +1,146 @@
+package trash
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vdbulcke/cert-manager/data"
+	"github.com/vdbulcke/cert-manager/data/database"
+)
+
+func setupTestCertBackend(t *testing.T) *data.CertBackend {
+
+	db := database.NewSqliteDB(filepath.Join(t.TempDir(), "sqlite.db"))
+	err := db.AutoMigrate(data.Models()...)
+	if err != nil {
+		t.Logf("Error running migration %s", err.Error())
+		t.FailNow()
+	}
+
+	return data.NewCertBackend(hclog.NewNullLogger(), db, data.NewValidation())
+}
+
+// createTestCertificate create a self-signed cert with the common name cn
+func createTestCertificate(t *testing.T, certBackend *data.CertBackend, cn string) *data.Certificate {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Logf("Error generating key %s", err.Error())
+		t.FailNow()
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().AddDate(0, -1, 0),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Logf("Error creating certificate %s", err.Error())
+		t.FailNow()
+	}
+
+	cert, err := certBackend.CreateCertificate(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	if err != nil {
+		t.Logf("Error storing certificate %s", err.Error())
+		t.FailNow()
+	}
+
+	return cert
+}
+
+func TestNewConfigFromEnv(t *testing.T) {
+
+	tests := []struct {
+		retention string
+		interval  string
+		valid     bool
+		expected  Config
+	}{
+		{"", "", true, NewConfig()},
+		{"24h", "10m", true, Config{Retention: 24 * time.Hour, Interval: 10 * time.Minute}},
+		{"0", "", false, Config{}},
+		{"-24h", "", false, Config{}},
+		{"", "0s", false, Config{}},
+		{"", "-1m", false, Config{}},
+		{"month", "", false, Config{}},
+	}
+
+	defer os.Unsetenv("CERT_MANAGER_TRASH_RETENTION")
+	defer os.Unsetenv("CERT_MANAGER_TRASH_PURGE_INTERVAL")
+
+	for _, test := range tests {
+		os.Setenv("CERT_MANAGER_TRASH_RETENTION", test.retention)
+		os.Setenv("CERT_MANAGER_TRASH_PURGE_INTERVAL", test.interval)
+
+		config, err := NewConfigFromEnv()
+		if (err == nil) != test.valid {
+			t.Logf("Expecting valid %v for %v got %v", test.valid, test, err)
+			t.FailNow()
+		}
+
+		if test.valid && config != test.expected {
+			t.Logf("Expecting %v got %v", test.expected, config)
+			t.FailNow()
+		}
+	}
+}
+
+func TestPurgerRun(t *testing.T) {
+
+	certBackend := setupTestCertBackend(t)
+
+	deleted := createTestCertificate(t, certBackend, "deleted.example.com")
+	createTestCertificate(t, certBackend, "kept.example.com")
+
+	err := certBackend.DeleteCertByID(deleted.ID)
+	if err != nil {
+		t.Logf("Error deleting cert %s", err.Error())
+		t.FailNow()
+	}
+
+	purger := NewPurger(hclog.NewNullLogger(), certBackend, Config{Retention: 24 * time.Hour, Interval: time.Hour})
+
+	tests := []struct {
+		name string
+		now  time.Time
+		// the number of certs purged by the run
+		purged int
+	}{
+		// the cert stays in the trash for the retention
+		{"within retention", time.Now(), 0},
+		{"after retention", time.Now().Add(25 * time.Hour), 1},
+		{"already purged", time.Now().Add(26 * time.Hour), 0},
+	}
+
+	for _, test := range tests {
+		report, err := purger.Run(test.now)
+		if err != nil {
+			t.Logf("%s: Error purging trash %s", test.name, err.Error())
+			t.FailNow()
+		}
+
+		if report.Certificates != test.purged || report.Tags != 0 {
+			t.Logf("%s: Expecting %d purged certs got %v", test.name, test.purged, report)
+			t.FailNow()
+		}
+	}
+
+	certs, err := certBackend.ListCerts()
+	if err != nil || len(certs) != 1 || certs[0].SubjectDN.CommonName != "kept.example.com" {
+		t.Logf("Expecting only the kept cert got %v (%v)", certs, err)
+		t.FailNow()
+	}
+}